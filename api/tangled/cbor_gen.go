@@ -2084,7 +2084,7 @@ func (t *PijulRefUpdate) MarshalCBOR(w io.Writer) error {
 	}
 
 	cw := cbg.NewCborWriter(w)
-	fieldCount := 7
+	fieldCount := 8
 
 	if t.Languages == nil {
 		fieldCount--
@@ -2094,6 +2094,10 @@ func (t *PijulRefUpdate) MarshalCBOR(w io.Writer) error {
 		fieldCount--
 	}
 
+	if t.Unrecorded == nil {
+		fieldCount--
+	}
+
 	if _, err := cw.Write(cbg.CborEncodeMajorType(cbg.MajMap, uint64(fieldCount))); err != nil {
 		return err
 	}
@@ -2269,6 +2273,42 @@ func (t *PijulRefUpdate) MarshalCBOR(w io.Writer) error {
 			return err
 		}
 	}
+
+	// t.Unrecorded ([]string) (slice)
+	if t.Unrecorded != nil {
+
+		if len("unrecorded") > 1000000 {
+			return xerrors.Errorf("Value in field \"unrecorded\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("unrecorded"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("unrecorded")); err != nil {
+			return err
+		}
+
+		if len(t.Unrecorded) > 8192 {
+			return xerrors.Errorf("Slice value in field t.Unrecorded was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajArray, uint64(len(t.Unrecorded))); err != nil {
+			return err
+		}
+		for _, v := range t.Unrecorded {
+			if len(v) > 1000000 {
+				return xerrors.Errorf("Value in field v was too long")
+			}
+
+			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(v))); err != nil {
+				return err
+			}
+			if _, err := cw.WriteString(string(v)); err != nil {
+				return err
+			}
+
+		}
+	}
 	return nil
 }
 
@@ -2297,7 +2337,7 @@ func (t *PijulRefUpdate) UnmarshalCBOR(r io.Reader) (err error) {
 
 	n := extra
 
-	nameBuf := make([]byte, 9)
+	nameBuf := make([]byte, 10)
 	for i := uint64(0); i < n; i++ {
 		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
 		if err != nil {
@@ -2438,6 +2478,112 @@ func (t *PijulRefUpdate) UnmarshalCBOR(r io.Reader) (err error) {
 				}
 
 			}
+			// t.Unrecorded ([]string) (slice)
+		case "unrecorded":
+
+			maj, extra, err = cr.ReadHeader()
+			if err != nil {
+				return err
+			}
+
+			if extra > 8192 {
+				return fmt.Errorf("t.Unrecorded: array too large (%d)", extra)
+			}
+
+			if maj != cbg.MajArray {
+				return fmt.Errorf("expected cbor array")
+			}
+
+			if extra > 0 {
+				t.Unrecorded = make([]string, extra)
+			}
+
+			for i := 0; i < int(extra); i++ {
+				{
+					var maj byte
+					var extra uint64
+					var err error
+					_ = maj
+					_ = extra
+					_ = err
+
+					{
+						sval, err := cbg.ReadStringWithMax(cr, 1000000)
+						if err != nil {
+							return err
+						}
+
+						t.Unrecorded[i] = string(sval)
+					}
+
+				}
+			}
+
+		default:
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(r, func(cid.Cid) {}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+func (t *PijulRefUpdate_Languages) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+
+	cw := cbg.NewCborWriter(w)
+
+	if _, err := cw.Write([]byte{160}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *PijulRefUpdate_Languages) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = PijulRefUpdate_Languages{}
+
+	cr := cbg.NewCborReader(r)
+
+	maj, extra, err := cr.ReadHeader()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	if maj != cbg.MajMap {
+		return fmt.Errorf("cbor input should be of type map")
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("PijulRefUpdate_Languages: map struct too large (%d)", extra)
+	}
+
+	n := extra
+
+	nameBuf := make([]byte, 0)
+	for i := uint64(0); i < n; i++ {
+		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(cr, func(cid.Cid) {}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch string(nameBuf[:nameLen]) {
 
 		default:
 			// Field doesn't exist on this type, so ignore it
@@ -4386,56 +4532,147 @@ func (t *Pipeline_CloneOpts) UnmarshalCBOR(r io.Reader) (err error) {
 
 	return nil
 }
-func (t *Pipeline_ManualTriggerData) MarshalCBOR(w io.Writer) error {
+func (t *Pipeline_DiscussionTriggerData) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
 
 	cw := cbg.NewCborWriter(w)
-	fieldCount := 1
 
-	if t.Inputs == nil {
-		fieldCount--
+	if _, err := cw.Write([]byte{165}); err != nil {
+		return err
 	}
 
-	if _, err := cw.Write(cbg.CborEncodeMajorType(cbg.MajMap, uint64(fieldCount))); err != nil {
+	// t.Action (string) (string)
+	if len("action") > 1000000 {
+		return xerrors.Errorf("Value in field \"action\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("action"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("action")); err != nil {
 		return err
 	}
 
-	// t.Inputs ([]*tangled.Pipeline_Pair) (slice)
-	if t.Inputs != nil {
+	if len(t.Action) > 1000000 {
+		return xerrors.Errorf("Value in field t.Action was too long")
+	}
 
-		if len("inputs") > 1000000 {
-			return xerrors.Errorf("Value in field \"inputs\" was too long")
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Action))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.Action)); err != nil {
+		return err
+	}
+
+	// t.Patches ([]string) (slice)
+	if len("patches") > 1000000 {
+		return xerrors.Errorf("Value in field \"patches\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("patches"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("patches")); err != nil {
+		return err
+	}
+
+	if len(t.Patches) > 8192 {
+		return xerrors.Errorf("Slice value in field t.Patches was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajArray, uint64(len(t.Patches))); err != nil {
+		return err
+	}
+	for _, v := range t.Patches {
+		if len(v) > 1000000 {
+			return xerrors.Errorf("Value in field v was too long")
 		}
 
-		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("inputs"))); err != nil {
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(v))); err != nil {
 			return err
 		}
-		if _, err := cw.WriteString(string("inputs")); err != nil {
+		if _, err := cw.WriteString(string(v)); err != nil {
 			return err
 		}
 
-		if len(t.Inputs) > 8192 {
-			return xerrors.Errorf("Slice value in field t.Inputs was too long")
-		}
+	}
 
-		if err := cw.WriteMajorTypeHeader(cbg.MajArray, uint64(len(t.Inputs))); err != nil {
-			return err
-		}
-		for _, v := range t.Inputs {
-			if err := v.MarshalCBOR(cw); err != nil {
-				return err
-			}
+	// t.PatchHash (string) (string)
+	if len("patchHash") > 1000000 {
+		return xerrors.Errorf("Value in field \"patchHash\" was too long")
+	}
 
-		}
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("patchHash"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("patchHash")); err != nil {
+		return err
+	}
+
+	if len(t.PatchHash) > 1000000 {
+		return xerrors.Errorf("Value in field t.PatchHash was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.PatchHash))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.PatchHash)); err != nil {
+		return err
+	}
+
+	// t.Discussion (string) (string)
+	if len("discussion") > 1000000 {
+		return xerrors.Errorf("Value in field \"discussion\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("discussion"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("discussion")); err != nil {
+		return err
+	}
+
+	if len(t.Discussion) > 1000000 {
+		return xerrors.Errorf("Value in field t.Discussion was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Discussion))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.Discussion)); err != nil {
+		return err
+	}
+
+	// t.TargetChannel (string) (string)
+	if len("targetChannel") > 1000000 {
+		return xerrors.Errorf("Value in field \"targetChannel\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("targetChannel"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("targetChannel")); err != nil {
+		return err
+	}
+
+	if len(t.TargetChannel) > 1000000 {
+		return xerrors.Errorf("Value in field t.TargetChannel was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.TargetChannel))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.TargetChannel)); err != nil {
+		return err
 	}
 	return nil
 }
 
-func (t *Pipeline_ManualTriggerData) UnmarshalCBOR(r io.Reader) (err error) {
-	*t = Pipeline_ManualTriggerData{}
+func (t *Pipeline_DiscussionTriggerData) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = Pipeline_DiscussionTriggerData{}
 
 	cr := cbg.NewCborReader(r)
 
@@ -4454,12 +4691,12 @@ func (t *Pipeline_ManualTriggerData) UnmarshalCBOR(r io.Reader) (err error) {
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("Pipeline_ManualTriggerData: map struct too large (%d)", extra)
+		return fmt.Errorf("Pipeline_DiscussionTriggerData: map struct too large (%d)", extra)
 	}
 
 	n := extra
 
-	nameBuf := make([]byte, 6)
+	nameBuf := make([]byte, 13)
 	for i := uint64(0); i < n; i++ {
 		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
 		if err != nil {
@@ -4475,8 +4712,19 @@ func (t *Pipeline_ManualTriggerData) UnmarshalCBOR(r io.Reader) (err error) {
 		}
 
 		switch string(nameBuf[:nameLen]) {
-		// t.Inputs ([]*tangled.Pipeline_Pair) (slice)
-		case "inputs":
+		// t.Action (string) (string)
+		case "action":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.Action = string(sval)
+			}
+			// t.Patches ([]string) (slice)
+		case "patches":
 
 			maj, extra, err = cr.ReadHeader()
 			if err != nil {
@@ -4484,7 +4732,7 @@ func (t *Pipeline_ManualTriggerData) UnmarshalCBOR(r io.Reader) (err error) {
 			}
 
 			if extra > 8192 {
-				return fmt.Errorf("t.Inputs: array too large (%d)", extra)
+				return fmt.Errorf("t.Patches: array too large (%d)", extra)
 			}
 
 			if maj != cbg.MajArray {
@@ -4492,7 +4740,180 @@ func (t *Pipeline_ManualTriggerData) UnmarshalCBOR(r io.Reader) (err error) {
 			}
 
 			if extra > 0 {
-				t.Inputs = make([]*Pipeline_Pair, extra)
+				t.Patches = make([]string, extra)
+			}
+
+			for i := 0; i < int(extra); i++ {
+				{
+					var maj byte
+					var extra uint64
+					var err error
+					_ = maj
+					_ = extra
+					_ = err
+
+					{
+						sval, err := cbg.ReadStringWithMax(cr, 1000000)
+						if err != nil {
+							return err
+						}
+
+						t.Patches[i] = string(sval)
+					}
+
+				}
+			}
+			// t.PatchHash (string) (string)
+		case "patchHash":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.PatchHash = string(sval)
+			}
+			// t.Discussion (string) (string)
+		case "discussion":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.Discussion = string(sval)
+			}
+			// t.TargetChannel (string) (string)
+		case "targetChannel":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.TargetChannel = string(sval)
+			}
+
+		default:
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(r, func(cid.Cid) {}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+func (t *Pipeline_ManualTriggerData) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+
+	cw := cbg.NewCborWriter(w)
+	fieldCount := 1
+
+	if t.Inputs == nil {
+		fieldCount--
+	}
+
+	if _, err := cw.Write(cbg.CborEncodeMajorType(cbg.MajMap, uint64(fieldCount))); err != nil {
+		return err
+	}
+
+	// t.Inputs ([]*tangled.Pipeline_Pair) (slice)
+	if t.Inputs != nil {
+
+		if len("inputs") > 1000000 {
+			return xerrors.Errorf("Value in field \"inputs\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("inputs"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("inputs")); err != nil {
+			return err
+		}
+
+		if len(t.Inputs) > 8192 {
+			return xerrors.Errorf("Slice value in field t.Inputs was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajArray, uint64(len(t.Inputs))); err != nil {
+			return err
+		}
+		for _, v := range t.Inputs {
+			if err := v.MarshalCBOR(cw); err != nil {
+				return err
+			}
+
+		}
+	}
+	return nil
+}
+
+func (t *Pipeline_ManualTriggerData) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = Pipeline_ManualTriggerData{}
+
+	cr := cbg.NewCborReader(r)
+
+	maj, extra, err := cr.ReadHeader()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	if maj != cbg.MajMap {
+		return fmt.Errorf("cbor input should be of type map")
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("Pipeline_ManualTriggerData: map struct too large (%d)", extra)
+	}
+
+	n := extra
+
+	nameBuf := make([]byte, 6)
+	for i := uint64(0); i < n; i++ {
+		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(cr, func(cid.Cid) {}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch string(nameBuf[:nameLen]) {
+		// t.Inputs ([]*tangled.Pipeline_Pair) (slice)
+		case "inputs":
+
+			maj, extra, err = cr.ReadHeader()
+			if err != nil {
+				return err
+			}
+
+			if extra > 8192 {
+				return fmt.Errorf("t.Inputs: array too large (%d)", extra)
+			}
+
+			if maj != cbg.MajArray {
+				return fmt.Errorf("expected cbor array")
+			}
+
+			if extra > 0 {
+				t.Inputs = make([]*Pipeline_Pair, extra)
 			}
 
 			for i := 0; i < int(extra); i++ {
@@ -5408,7 +5829,11 @@ func (t *Pipeline_TriggerMetadata) MarshalCBOR(w io.Writer) error {
 	}
 
 	cw := cbg.NewCborWriter(w)
-	fieldCount := 5
+	fieldCount := 6
+
+	if t.Discussion == nil {
+		fieldCount--
+	}
 
 	if t.Manual == nil {
 		fieldCount--
@@ -5503,6 +5928,25 @@ func (t *Pipeline_TriggerMetadata) MarshalCBOR(w io.Writer) error {
 		}
 	}
 
+	// t.Discussion (tangled.Pipeline_DiscussionTriggerData) (struct)
+	if t.Discussion != nil {
+
+		if len("discussion") > 1000000 {
+			return xerrors.Errorf("Value in field \"discussion\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("discussion"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("discussion")); err != nil {
+			return err
+		}
+
+		if err := t.Discussion.MarshalCBOR(cw); err != nil {
+			return err
+		}
+	}
+
 	// t.PullRequest (tangled.Pipeline_PullRequestTriggerData) (struct)
 	if t.PullRequest != nil {
 
@@ -5635,6 +6079,26 @@ func (t *Pipeline_TriggerMetadata) UnmarshalCBOR(r io.Reader) (err error) {
 					}
 				}
 
+			}
+			// t.Discussion (tangled.Pipeline_DiscussionTriggerData) (struct)
+		case "discussion":
+
+			{
+
+				b, err := cr.ReadByte()
+				if err != nil {
+					return err
+				}
+				if b != cbg.CborNull[0] {
+					if err := cr.UnreadByte(); err != nil {
+						return err
+					}
+					t.Discussion = new(Pipeline_DiscussionTriggerData)
+					if err := t.Discussion.UnmarshalCBOR(cr); err != nil {
+						return xerrors.Errorf("unmarshaling t.Discussion pointer: %w", err)
+					}
+				}
+
 			}
 			// t.PullRequest (tangled.Pipeline_PullRequestTriggerData) (struct)
 		case "pullRequest":
@@ -5674,8 +6138,13 @@ func (t *Pipeline_TriggerRepo) MarshalCBOR(w io.Writer) error {
 	}
 
 	cw := cbg.NewCborWriter(w)
+	fieldCount := 5
 
-	if _, err := cw.Write([]byte{164}); err != nil {
+	if t.Vcs == nil {
+		fieldCount--
+	}
+
+	if _, err := cw.Write(cbg.CborEncodeMajorType(cbg.MajMap, uint64(fieldCount))); err != nil {
 		return err
 	}
 
@@ -5702,6 +6171,38 @@ func (t *Pipeline_TriggerRepo) MarshalCBOR(w io.Writer) error {
 		return err
 	}
 
+	// t.Vcs (string) (string)
+	if t.Vcs != nil {
+
+		if len("vcs") > 1000000 {
+			return xerrors.Errorf("Value in field \"vcs\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("vcs"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("vcs")); err != nil {
+			return err
+		}
+
+		if t.Vcs == nil {
+			if _, err := cw.Write(cbg.CborNull); err != nil {
+				return err
+			}
+		} else {
+			if len(*t.Vcs) > 1000000 {
+				return xerrors.Errorf("Value in field t.Vcs was too long")
+			}
+
+			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(*t.Vcs))); err != nil {
+				return err
+			}
+			if _, err := cw.WriteString(string(*t.Vcs)); err != nil {
+				return err
+			}
+		}
+	}
+
 	// t.Knot (string) (string)
 	if len("knot") > 1000000 {
 		return xerrors.Errorf("Value in field \"knot\" was too long")
@@ -5825,6 +6326,27 @@ func (t *Pipeline_TriggerRepo) UnmarshalCBOR(r io.Reader) (err error) {
 
 				t.Did = string(sval)
 			}
+			// t.Vcs (string) (string)
+		case "vcs":
+
+			{
+				b, err := cr.ReadByte()
+				if err != nil {
+					return err
+				}
+				if b != cbg.CborNull[0] {
+					if err := cr.UnreadByte(); err != nil {
+						return err
+					}
+
+					sval, err := cbg.ReadStringWithMax(cr, 1000000)
+					if err != nil {
+						return err
+					}
+
+					t.Vcs = (*string)(&sval)
+				}
+			}
 			// t.Knot (string) (string)
 		case "knot":
 
@@ -6865,7 +7387,11 @@ func (t *RepoArtifact) MarshalCBOR(w io.Writer) error {
 	}
 
 	cw := cbg.NewCborWriter(w)
-	fieldCount := 6
+	fieldCount := 7
+
+	if t.PijulState == nil {
+		fieldCount--
+	}
 
 	if t.Tag == nil {
 		fieldCount--
@@ -7006,13 +7532,45 @@ func (t *RepoArtifact) MarshalCBOR(w io.Writer) error {
 	if _, err := cw.WriteString(string(t.CreatedAt)); err != nil {
 		return err
 	}
-	return nil
-}
 
-func (t *RepoArtifact) UnmarshalCBOR(r io.Reader) (err error) {
-	*t = RepoArtifact{}
+	// t.PijulState (string) (string)
+	if t.PijulState != nil {
 
-	cr := cbg.NewCborReader(r)
+		if len("pijulState") > 1000000 {
+			return xerrors.Errorf("Value in field \"pijulState\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("pijulState"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("pijulState")); err != nil {
+			return err
+		}
+
+		if t.PijulState == nil {
+			if _, err := cw.Write(cbg.CborNull); err != nil {
+				return err
+			}
+		} else {
+			if len(*t.PijulState) > 1000000 {
+				return xerrors.Errorf("Value in field t.PijulState was too long")
+			}
+
+			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(*t.PijulState))); err != nil {
+				return err
+			}
+			if _, err := cw.WriteString(string(*t.PijulState)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *RepoArtifact) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = RepoArtifact{}
+
+	cr := cbg.NewCborReader(r)
 
 	maj, extra, err := cr.ReadHeader()
 	if err != nil {
@@ -7034,7 +7592,7 @@ func (t *RepoArtifact) UnmarshalCBOR(r io.Reader) (err error) {
 
 	n := extra
 
-	nameBuf := make([]byte, 9)
+	nameBuf := make([]byte, 10)
 	for i := uint64(0); i < n; i++ {
 		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
 		if err != nil {
@@ -7137,6 +7695,27 @@ func (t *RepoArtifact) UnmarshalCBOR(r io.Reader) (err error) {
 
 				t.CreatedAt = string(sval)
 			}
+			// t.PijulState (string) (string)
+		case "pijulState":
+
+			{
+				b, err := cr.ReadByte()
+				if err != nil {
+					return err
+				}
+				if b != cbg.CborNull[0] {
+					if err := cr.UnreadByte(); err != nil {
+						return err
+					}
+
+					sval, err := cbg.ReadStringWithMax(cr, 1000000)
+					if err != nil {
+						return err
+					}
+
+					t.PijulState = (*string)(&sval)
+				}
+			}
 
 		default:
 			// Field doesn't exist on this type, so ignore it
@@ -8235,7 +8814,7 @@ func (t *RepoDiscussionComment) UnmarshalCBOR(r io.Reader) (err error) {
 
 	return nil
 }
-func (t *RepoDiscussionState) MarshalCBOR(w io.Writer) error {
+func (t *RepoDiscussionPatch) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
@@ -8243,7 +8822,7 @@ func (t *RepoDiscussionState) MarshalCBOR(w io.Writer) error {
 
 	cw := cbg.NewCborWriter(w)
 
-	if _, err := cw.Write([]byte{164}); err != nil {
+	if _, err := cw.Write([]byte{165}); err != nil {
 		return err
 	}
 
@@ -8259,33 +8838,33 @@ func (t *RepoDiscussionState) MarshalCBOR(w io.Writer) error {
 		return err
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.discussion.state"))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.discussion.patch"))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("sh.tangled.repo.discussion.state")); err != nil {
+	if _, err := cw.WriteString(string("sh.tangled.repo.discussion.patch")); err != nil {
 		return err
 	}
 
-	// t.State (string) (string)
-	if len("state") > 1000000 {
-		return xerrors.Errorf("Value in field \"state\" was too long")
+	// t.Patch (string) (string)
+	if len("patch") > 1000000 {
+		return xerrors.Errorf("Value in field \"patch\" was too long")
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("state"))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("patch"))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("state")); err != nil {
+	if _, err := cw.WriteString(string("patch")); err != nil {
 		return err
 	}
 
-	if len(t.State) > 1000000 {
-		return xerrors.Errorf("Value in field t.State was too long")
+	if len(t.Patch) > 1000000 {
+		return xerrors.Errorf("Value in field t.Patch was too long")
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.State))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Patch))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string(t.State)); err != nil {
+	if _, err := cw.WriteString(string(t.Patch)); err != nil {
 		return err
 	}
 
@@ -8312,6 +8891,29 @@ func (t *RepoDiscussionState) MarshalCBOR(w io.Writer) error {
 		return err
 	}
 
+	// t.PatchHash (string) (string)
+	if len("patchHash") > 1000000 {
+		return xerrors.Errorf("Value in field \"patchHash\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("patchHash"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("patchHash")); err != nil {
+		return err
+	}
+
+	if len(t.PatchHash) > 1000000 {
+		return xerrors.Errorf("Value in field t.PatchHash was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.PatchHash))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.PatchHash)); err != nil {
+		return err
+	}
+
 	// t.Discussion (string) (string)
 	if len("discussion") > 1000000 {
 		return xerrors.Errorf("Value in field \"discussion\" was too long")
@@ -8337,8 +8939,8 @@ func (t *RepoDiscussionState) MarshalCBOR(w io.Writer) error {
 	return nil
 }
 
-func (t *RepoDiscussionState) UnmarshalCBOR(r io.Reader) (err error) {
-	*t = RepoDiscussionState{}
+func (t *RepoDiscussionPatch) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = RepoDiscussionPatch{}
 
 	cr := cbg.NewCborReader(r)
 
@@ -8357,7 +8959,7 @@ func (t *RepoDiscussionState) UnmarshalCBOR(r io.Reader) (err error) {
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("RepoDiscussionState: map struct too large (%d)", extra)
+		return fmt.Errorf("RepoDiscussionPatch: map struct too large (%d)", extra)
 	}
 
 	n := extra
@@ -8389,8 +8991,8 @@ func (t *RepoDiscussionState) UnmarshalCBOR(r io.Reader) (err error) {
 
 				t.LexiconTypeID = string(sval)
 			}
-			// t.State (string) (string)
-		case "state":
+			// t.Patch (string) (string)
+		case "patch":
 
 			{
 				sval, err := cbg.ReadStringWithMax(cr, 1000000)
@@ -8398,7 +9000,7 @@ func (t *RepoDiscussionState) UnmarshalCBOR(r io.Reader) (err error) {
 					return err
 				}
 
-				t.State = string(sval)
+				t.Patch = string(sval)
 			}
 			// t.CreatedAt (string) (string)
 		case "createdAt":
@@ -8411,6 +9013,17 @@ func (t *RepoDiscussionState) UnmarshalCBOR(r io.Reader) (err error) {
 
 				t.CreatedAt = string(sval)
 			}
+			// t.PatchHash (string) (string)
+		case "patchHash":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.PatchHash = string(sval)
+			}
 			// t.Discussion (string) (string)
 		case "discussion":
 
@@ -8433,164 +9046,83 @@ func (t *RepoDiscussionState) UnmarshalCBOR(r io.Reader) (err error) {
 
 	return nil
 }
-func (t *RepoIssue) MarshalCBOR(w io.Writer) error {
+func (t *RepoDiscussionPatchState) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
 
 	cw := cbg.NewCborWriter(w)
-	fieldCount := 7
-
-	if t.Body == nil {
-		fieldCount--
-	}
-
-	if t.Mentions == nil {
-		fieldCount--
-	}
-
-	if t.References == nil {
-		fieldCount--
-	}
 
-	if _, err := cw.Write(cbg.CborEncodeMajorType(cbg.MajMap, uint64(fieldCount))); err != nil {
+	if _, err := cw.Write([]byte{164}); err != nil {
 		return err
 	}
 
-	// t.Body (string) (string)
-	if t.Body != nil {
-
-		if len("body") > 1000000 {
-			return xerrors.Errorf("Value in field \"body\" was too long")
-		}
-
-		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("body"))); err != nil {
-			return err
-		}
-		if _, err := cw.WriteString(string("body")); err != nil {
-			return err
-		}
-
-		if t.Body == nil {
-			if _, err := cw.Write(cbg.CborNull); err != nil {
-				return err
-			}
-		} else {
-			if len(*t.Body) > 1000000 {
-				return xerrors.Errorf("Value in field t.Body was too long")
-			}
-
-			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(*t.Body))); err != nil {
-				return err
-			}
-			if _, err := cw.WriteString(string(*t.Body)); err != nil {
-				return err
-			}
-		}
-	}
-
-	// t.Repo (string) (string)
-	if len("repo") > 1000000 {
-		return xerrors.Errorf("Value in field \"repo\" was too long")
+	// t.LexiconTypeID (string) (string)
+	if len("$type") > 1000000 {
+		return xerrors.Errorf("Value in field \"$type\" was too long")
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("repo"))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("$type"))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("repo")); err != nil {
+	if _, err := cw.WriteString(string("$type")); err != nil {
 		return err
 	}
 
-	if len(t.Repo) > 1000000 {
-		return xerrors.Errorf("Value in field t.Repo was too long")
-	}
-
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Repo))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.discussion.patch.state"))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string(t.Repo)); err != nil {
+	if _, err := cw.WriteString(string("sh.tangled.repo.discussion.patch.state")); err != nil {
 		return err
 	}
 
-	// t.LexiconTypeID (string) (string)
-	if len("$type") > 1000000 {
-		return xerrors.Errorf("Value in field \"$type\" was too long")
+	// t.Patch (string) (string)
+	if len("patch") > 1000000 {
+		return xerrors.Errorf("Value in field \"patch\" was too long")
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("$type"))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("patch"))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("$type")); err != nil {
+	if _, err := cw.WriteString(string("patch")); err != nil {
 		return err
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.issue"))); err != nil {
+	if len(t.Patch) > 1000000 {
+		return xerrors.Errorf("Value in field t.Patch was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Patch))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("sh.tangled.repo.issue")); err != nil {
+	if _, err := cw.WriteString(string(t.Patch)); err != nil {
 		return err
 	}
 
-	// t.Title (string) (string)
-	if len("title") > 1000000 {
-		return xerrors.Errorf("Value in field \"title\" was too long")
+	// t.State (string) (string)
+	if len("state") > 1000000 {
+		return xerrors.Errorf("Value in field \"state\" was too long")
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("title"))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("state"))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("title")); err != nil {
+	if _, err := cw.WriteString(string("state")); err != nil {
 		return err
 	}
 
-	if len(t.Title) > 1000000 {
-		return xerrors.Errorf("Value in field t.Title was too long")
+	if len(t.State) > 1000000 {
+		return xerrors.Errorf("Value in field t.State was too long")
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Title))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.State))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string(t.Title)); err != nil {
+	if _, err := cw.WriteString(string(t.State)); err != nil {
 		return err
 	}
 
-	// t.Mentions ([]string) (slice)
-	if t.Mentions != nil {
-
-		if len("mentions") > 1000000 {
-			return xerrors.Errorf("Value in field \"mentions\" was too long")
-		}
-
-		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("mentions"))); err != nil {
-			return err
-		}
-		if _, err := cw.WriteString(string("mentions")); err != nil {
-			return err
-		}
-
-		if len(t.Mentions) > 8192 {
-			return xerrors.Errorf("Slice value in field t.Mentions was too long")
-		}
-
-		if err := cw.WriteMajorTypeHeader(cbg.MajArray, uint64(len(t.Mentions))); err != nil {
-			return err
-		}
-		for _, v := range t.Mentions {
-			if len(v) > 1000000 {
-				return xerrors.Errorf("Value in field v was too long")
-			}
-
-			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(v))); err != nil {
-				return err
-			}
-			if _, err := cw.WriteString(string(v)); err != nil {
-				return err
-			}
-
-		}
-	}
-
 	// t.CreatedAt (string) (string)
 	if len("createdAt") > 1000000 {
 		return xerrors.Errorf("Value in field \"createdAt\" was too long")
@@ -8613,74 +9145,38 @@ func (t *RepoIssue) MarshalCBOR(w io.Writer) error {
 	if _, err := cw.WriteString(string(t.CreatedAt)); err != nil {
 		return err
 	}
+	return nil
+}
 
-	// t.References ([]string) (slice)
-	if t.References != nil {
+func (t *RepoDiscussionPatchState) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = RepoDiscussionPatchState{}
 
-		if len("references") > 1000000 {
-			return xerrors.Errorf("Value in field \"references\" was too long")
-		}
+	cr := cbg.NewCborReader(r)
 
-		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("references"))); err != nil {
-			return err
-		}
-		if _, err := cw.WriteString(string("references")); err != nil {
-			return err
+	maj, extra, err := cr.ReadHeader()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
 		}
+	}()
 
-		if len(t.References) > 8192 {
-			return xerrors.Errorf("Slice value in field t.References was too long")
-		}
+	if maj != cbg.MajMap {
+		return fmt.Errorf("cbor input should be of type map")
+	}
 
-		if err := cw.WriteMajorTypeHeader(cbg.MajArray, uint64(len(t.References))); err != nil {
-			return err
-		}
-		for _, v := range t.References {
-			if len(v) > 1000000 {
-				return xerrors.Errorf("Value in field v was too long")
-			}
-
-			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(v))); err != nil {
-				return err
-			}
-			if _, err := cw.WriteString(string(v)); err != nil {
-				return err
-			}
-
-		}
-	}
-	return nil
-}
-
-func (t *RepoIssue) UnmarshalCBOR(r io.Reader) (err error) {
-	*t = RepoIssue{}
-
-	cr := cbg.NewCborReader(r)
-
-	maj, extra, err := cr.ReadHeader()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err == io.EOF {
-			err = io.ErrUnexpectedEOF
-		}
-	}()
-
-	if maj != cbg.MajMap {
-		return fmt.Errorf("cbor input should be of type map")
-	}
-
-	if extra > cbg.MaxLength {
-		return fmt.Errorf("RepoIssue: map struct too large (%d)", extra)
-	}
-
-	n := extra
-
-	nameBuf := make([]byte, 10)
-	for i := uint64(0); i < n; i++ {
-		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
-		if err != nil {
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("RepoDiscussionPatchState: map struct too large (%d)", extra)
+	}
+
+	n := extra
+
+	nameBuf := make([]byte, 9)
+	for i := uint64(0); i < n; i++ {
+		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
+		if err != nil {
 			return err
 		}
 
@@ -8693,29 +9189,8 @@ func (t *RepoIssue) UnmarshalCBOR(r io.Reader) (err error) {
 		}
 
 		switch string(nameBuf[:nameLen]) {
-		// t.Body (string) (string)
-		case "body":
-
-			{
-				b, err := cr.ReadByte()
-				if err != nil {
-					return err
-				}
-				if b != cbg.CborNull[0] {
-					if err := cr.UnreadByte(); err != nil {
-						return err
-					}
-
-					sval, err := cbg.ReadStringWithMax(cr, 1000000)
-					if err != nil {
-						return err
-					}
-
-					t.Body = (*string)(&sval)
-				}
-			}
-			// t.Repo (string) (string)
-		case "repo":
+		// t.LexiconTypeID (string) (string)
+		case "$type":
 
 			{
 				sval, err := cbg.ReadStringWithMax(cr, 1000000)
@@ -8723,10 +9198,10 @@ func (t *RepoIssue) UnmarshalCBOR(r io.Reader) (err error) {
 					return err
 				}
 
-				t.Repo = string(sval)
+				t.LexiconTypeID = string(sval)
 			}
-			// t.LexiconTypeID (string) (string)
-		case "$type":
+			// t.Patch (string) (string)
+		case "patch":
 
 			{
 				sval, err := cbg.ReadStringWithMax(cr, 1000000)
@@ -8734,10 +9209,10 @@ func (t *RepoIssue) UnmarshalCBOR(r io.Reader) (err error) {
 					return err
 				}
 
-				t.LexiconTypeID = string(sval)
+				t.Patch = string(sval)
 			}
-			// t.Title (string) (string)
-		case "title":
+			// t.State (string) (string)
+		case "state":
 
 			{
 				sval, err := cbg.ReadStringWithMax(cr, 1000000)
@@ -8745,47 +9220,7 @@ func (t *RepoIssue) UnmarshalCBOR(r io.Reader) (err error) {
 					return err
 				}
 
-				t.Title = string(sval)
-			}
-			// t.Mentions ([]string) (slice)
-		case "mentions":
-
-			maj, extra, err = cr.ReadHeader()
-			if err != nil {
-				return err
-			}
-
-			if extra > 8192 {
-				return fmt.Errorf("t.Mentions: array too large (%d)", extra)
-			}
-
-			if maj != cbg.MajArray {
-				return fmt.Errorf("expected cbor array")
-			}
-
-			if extra > 0 {
-				t.Mentions = make([]string, extra)
-			}
-
-			for i := 0; i < int(extra); i++ {
-				{
-					var maj byte
-					var extra uint64
-					var err error
-					_ = maj
-					_ = extra
-					_ = err
-
-					{
-						sval, err := cbg.ReadStringWithMax(cr, 1000000)
-						if err != nil {
-							return err
-						}
-
-						t.Mentions[i] = string(sval)
-					}
-
-				}
+				t.State = string(sval)
 			}
 			// t.CreatedAt (string) (string)
 		case "createdAt":
@@ -8798,46 +9233,6 @@ func (t *RepoIssue) UnmarshalCBOR(r io.Reader) (err error) {
 
 				t.CreatedAt = string(sval)
 			}
-			// t.References ([]string) (slice)
-		case "references":
-
-			maj, extra, err = cr.ReadHeader()
-			if err != nil {
-				return err
-			}
-
-			if extra > 8192 {
-				return fmt.Errorf("t.References: array too large (%d)", extra)
-			}
-
-			if maj != cbg.MajArray {
-				return fmt.Errorf("expected cbor array")
-			}
-
-			if extra > 0 {
-				t.References = make([]string, extra)
-			}
-
-			for i := 0; i < int(extra); i++ {
-				{
-					var maj byte
-					var extra uint64
-					var err error
-					_ = maj
-					_ = extra
-					_ = err
-
-					{
-						sval, err := cbg.ReadStringWithMax(cr, 1000000)
-						if err != nil {
-							return err
-						}
-
-						t.References[i] = string(sval)
-					}
-
-				}
-			}
 
 		default:
 			// Field doesn't exist on this type, so ignore it
@@ -8849,128 +9244,326 @@ func (t *RepoIssue) UnmarshalCBOR(r io.Reader) (err error) {
 
 	return nil
 }
-func (t *RepoIssueComment) MarshalCBOR(w io.Writer) error {
+func (t *RepoDiscussionState) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
 
 	cw := cbg.NewCborWriter(w)
-	fieldCount := 7
 
-	if t.Mentions == nil {
-		fieldCount--
+	if _, err := cw.Write([]byte{164}); err != nil {
+		return err
 	}
 
-	if t.References == nil {
-		fieldCount--
+	// t.LexiconTypeID (string) (string)
+	if len("$type") > 1000000 {
+		return xerrors.Errorf("Value in field \"$type\" was too long")
 	}
 
-	if t.ReplyTo == nil {
-		fieldCount--
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("$type"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("$type")); err != nil {
+		return err
 	}
 
-	if _, err := cw.Write(cbg.CborEncodeMajorType(cbg.MajMap, uint64(fieldCount))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.discussion.state"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("sh.tangled.repo.discussion.state")); err != nil {
 		return err
 	}
 
-	// t.Body (string) (string)
-	if len("body") > 1000000 {
-		return xerrors.Errorf("Value in field \"body\" was too long")
+	// t.State (string) (string)
+	if len("state") > 1000000 {
+		return xerrors.Errorf("Value in field \"state\" was too long")
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("body"))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("state"))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("body")); err != nil {
+	if _, err := cw.WriteString(string("state")); err != nil {
 		return err
 	}
 
-	if len(t.Body) > 1000000 {
-		return xerrors.Errorf("Value in field t.Body was too long")
+	if len(t.State) > 1000000 {
+		return xerrors.Errorf("Value in field t.State was too long")
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Body))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.State))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string(t.Body)); err != nil {
+	if _, err := cw.WriteString(string(t.State)); err != nil {
 		return err
 	}
 
-	// t.LexiconTypeID (string) (string)
-	if len("$type") > 1000000 {
-		return xerrors.Errorf("Value in field \"$type\" was too long")
+	// t.CreatedAt (string) (string)
+	if len("createdAt") > 1000000 {
+		return xerrors.Errorf("Value in field \"createdAt\" was too long")
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("$type"))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("createdAt"))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("$type")); err != nil {
+	if _, err := cw.WriteString(string("createdAt")); err != nil {
 		return err
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.issue.comment"))); err != nil {
+	if len(t.CreatedAt) > 1000000 {
+		return xerrors.Errorf("Value in field t.CreatedAt was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.CreatedAt))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("sh.tangled.repo.issue.comment")); err != nil {
+	if _, err := cw.WriteString(string(t.CreatedAt)); err != nil {
 		return err
 	}
 
-	// t.Issue (string) (string)
-	if len("issue") > 1000000 {
-		return xerrors.Errorf("Value in field \"issue\" was too long")
+	// t.Discussion (string) (string)
+	if len("discussion") > 1000000 {
+		return xerrors.Errorf("Value in field \"discussion\" was too long")
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("issue"))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("discussion"))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("issue")); err != nil {
+	if _, err := cw.WriteString(string("discussion")); err != nil {
 		return err
 	}
 
-	if len(t.Issue) > 1000000 {
-		return xerrors.Errorf("Value in field t.Issue was too long")
+	if len(t.Discussion) > 1000000 {
+		return xerrors.Errorf("Value in field t.Discussion was too long")
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Issue))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Discussion))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string(t.Issue)); err != nil {
+	if _, err := cw.WriteString(string(t.Discussion)); err != nil {
 		return err
 	}
+	return nil
+}
 
-	// t.ReplyTo (string) (string)
-	if t.ReplyTo != nil {
+func (t *RepoDiscussionState) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = RepoDiscussionState{}
 
-		if len("replyTo") > 1000000 {
-			return xerrors.Errorf("Value in field \"replyTo\" was too long")
-		}
+	cr := cbg.NewCborReader(r)
 
-		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("replyTo"))); err != nil {
-			return err
+	maj, extra, err := cr.ReadHeader()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
 		}
-		if _, err := cw.WriteString(string("replyTo")); err != nil {
+	}()
+
+	if maj != cbg.MajMap {
+		return fmt.Errorf("cbor input should be of type map")
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("RepoDiscussionState: map struct too large (%d)", extra)
+	}
+
+	n := extra
+
+	nameBuf := make([]byte, 10)
+	for i := uint64(0); i < n; i++ {
+		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
+		if err != nil {
 			return err
 		}
 
-		if t.ReplyTo == nil {
+		if !ok {
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(cr, func(cid.Cid) {}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch string(nameBuf[:nameLen]) {
+		// t.LexiconTypeID (string) (string)
+		case "$type":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.LexiconTypeID = string(sval)
+			}
+			// t.State (string) (string)
+		case "state":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.State = string(sval)
+			}
+			// t.CreatedAt (string) (string)
+		case "createdAt":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.CreatedAt = string(sval)
+			}
+			// t.Discussion (string) (string)
+		case "discussion":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.Discussion = string(sval)
+			}
+
+		default:
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(r, func(cid.Cid) {}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+func (t *RepoIssue) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+
+	cw := cbg.NewCborWriter(w)
+	fieldCount := 7
+
+	if t.Body == nil {
+		fieldCount--
+	}
+
+	if t.Mentions == nil {
+		fieldCount--
+	}
+
+	if t.References == nil {
+		fieldCount--
+	}
+
+	if _, err := cw.Write(cbg.CborEncodeMajorType(cbg.MajMap, uint64(fieldCount))); err != nil {
+		return err
+	}
+
+	// t.Body (string) (string)
+	if t.Body != nil {
+
+		if len("body") > 1000000 {
+			return xerrors.Errorf("Value in field \"body\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("body"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("body")); err != nil {
+			return err
+		}
+
+		if t.Body == nil {
 			if _, err := cw.Write(cbg.CborNull); err != nil {
 				return err
 			}
 		} else {
-			if len(*t.ReplyTo) > 1000000 {
-				return xerrors.Errorf("Value in field t.ReplyTo was too long")
+			if len(*t.Body) > 1000000 {
+				return xerrors.Errorf("Value in field t.Body was too long")
 			}
 
-			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(*t.ReplyTo))); err != nil {
+			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(*t.Body))); err != nil {
 				return err
 			}
-			if _, err := cw.WriteString(string(*t.ReplyTo)); err != nil {
+			if _, err := cw.WriteString(string(*t.Body)); err != nil {
 				return err
 			}
 		}
 	}
 
+	// t.Repo (string) (string)
+	if len("repo") > 1000000 {
+		return xerrors.Errorf("Value in field \"repo\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("repo"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("repo")); err != nil {
+		return err
+	}
+
+	if len(t.Repo) > 1000000 {
+		return xerrors.Errorf("Value in field t.Repo was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Repo))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.Repo)); err != nil {
+		return err
+	}
+
+	// t.LexiconTypeID (string) (string)
+	if len("$type") > 1000000 {
+		return xerrors.Errorf("Value in field \"$type\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("$type"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("$type")); err != nil {
+		return err
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.issue"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("sh.tangled.repo.issue")); err != nil {
+		return err
+	}
+
+	// t.Title (string) (string)
+	if len("title") > 1000000 {
+		return xerrors.Errorf("Value in field \"title\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("title"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("title")); err != nil {
+		return err
+	}
+
+	if len(t.Title) > 1000000 {
+		return xerrors.Errorf("Value in field t.Title was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Title))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.Title)); err != nil {
+		return err
+	}
+
 	// t.Mentions ([]string) (slice)
 	if t.Mentions != nil {
 
@@ -9068,8 +9661,8 @@ func (t *RepoIssueComment) MarshalCBOR(w io.Writer) error {
 	return nil
 }
 
-func (t *RepoIssueComment) UnmarshalCBOR(r io.Reader) (err error) {
-	*t = RepoIssueComment{}
+func (t *RepoIssue) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = RepoIssue{}
 
 	cr := cbg.NewCborReader(r)
 
@@ -9088,7 +9681,7 @@ func (t *RepoIssueComment) UnmarshalCBOR(r io.Reader) (err error) {
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("RepoIssueComment: map struct too large (%d)", extra)
+		return fmt.Errorf("RepoIssue: map struct too large (%d)", extra)
 	}
 
 	n := extra
@@ -9113,15 +9706,25 @@ func (t *RepoIssueComment) UnmarshalCBOR(r io.Reader) (err error) {
 		case "body":
 
 			{
-				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				b, err := cr.ReadByte()
 				if err != nil {
 					return err
 				}
+				if b != cbg.CborNull[0] {
+					if err := cr.UnreadByte(); err != nil {
+						return err
+					}
 
-				t.Body = string(sval)
+					sval, err := cbg.ReadStringWithMax(cr, 1000000)
+					if err != nil {
+						return err
+					}
+
+					t.Body = (*string)(&sval)
+				}
 			}
-			// t.LexiconTypeID (string) (string)
-		case "$type":
+			// t.Repo (string) (string)
+		case "repo":
 
 			{
 				sval, err := cbg.ReadStringWithMax(cr, 1000000)
@@ -9129,10 +9732,10 @@ func (t *RepoIssueComment) UnmarshalCBOR(r io.Reader) (err error) {
 					return err
 				}
 
-				t.LexiconTypeID = string(sval)
+				t.Repo = string(sval)
 			}
-			// t.Issue (string) (string)
-		case "issue":
+			// t.LexiconTypeID (string) (string)
+		case "$type":
 
 			{
 				sval, err := cbg.ReadStringWithMax(cr, 1000000)
@@ -9140,28 +9743,18 @@ func (t *RepoIssueComment) UnmarshalCBOR(r io.Reader) (err error) {
 					return err
 				}
 
-				t.Issue = string(sval)
+				t.LexiconTypeID = string(sval)
 			}
-			// t.ReplyTo (string) (string)
-		case "replyTo":
+			// t.Title (string) (string)
+		case "title":
 
 			{
-				b, err := cr.ReadByte()
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
 				if err != nil {
 					return err
 				}
-				if b != cbg.CborNull[0] {
-					if err := cr.UnreadByte(); err != nil {
-						return err
-					}
-
-					sval, err := cbg.ReadStringWithMax(cr, 1000000)
-					if err != nil {
-						return err
-					}
 
-					t.ReplyTo = (*string)(&sval)
-				}
+				t.Title = string(sval)
 			}
 			// t.Mentions ([]string) (slice)
 		case "mentions":
@@ -9265,15 +9858,51 @@ func (t *RepoIssueComment) UnmarshalCBOR(r io.Reader) (err error) {
 
 	return nil
 }
-func (t *RepoIssueState) MarshalCBOR(w io.Writer) error {
+func (t *RepoIssueComment) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
 
 	cw := cbg.NewCborWriter(w)
+	fieldCount := 7
 
-	if _, err := cw.Write([]byte{163}); err != nil {
+	if t.Mentions == nil {
+		fieldCount--
+	}
+
+	if t.References == nil {
+		fieldCount--
+	}
+
+	if t.ReplyTo == nil {
+		fieldCount--
+	}
+
+	if _, err := cw.Write(cbg.CborEncodeMajorType(cbg.MajMap, uint64(fieldCount))); err != nil {
+		return err
+	}
+
+	// t.Body (string) (string)
+	if len("body") > 1000000 {
+		return xerrors.Errorf("Value in field \"body\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("body"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("body")); err != nil {
+		return err
+	}
+
+	if len(t.Body) > 1000000 {
+		return xerrors.Errorf("Value in field t.Body was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Body))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.Body)); err != nil {
 		return err
 	}
 
@@ -9289,10 +9918,10 @@ func (t *RepoIssueState) MarshalCBOR(w io.Writer) error {
 		return err
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.issue.state"))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.issue.comment"))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("sh.tangled.repo.issue.state")); err != nil {
+	if _, err := cw.WriteString(string("sh.tangled.repo.issue.comment")); err != nil {
 		return err
 	}
 
@@ -9319,33 +9948,137 @@ func (t *RepoIssueState) MarshalCBOR(w io.Writer) error {
 		return err
 	}
 
-	// t.State (string) (string)
-	if len("state") > 1000000 {
-		return xerrors.Errorf("Value in field \"state\" was too long")
+	// t.ReplyTo (string) (string)
+	if t.ReplyTo != nil {
+
+		if len("replyTo") > 1000000 {
+			return xerrors.Errorf("Value in field \"replyTo\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("replyTo"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("replyTo")); err != nil {
+			return err
+		}
+
+		if t.ReplyTo == nil {
+			if _, err := cw.Write(cbg.CborNull); err != nil {
+				return err
+			}
+		} else {
+			if len(*t.ReplyTo) > 1000000 {
+				return xerrors.Errorf("Value in field t.ReplyTo was too long")
+			}
+
+			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(*t.ReplyTo))); err != nil {
+				return err
+			}
+			if _, err := cw.WriteString(string(*t.ReplyTo)); err != nil {
+				return err
+			}
+		}
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("state"))); err != nil {
+	// t.Mentions ([]string) (slice)
+	if t.Mentions != nil {
+
+		if len("mentions") > 1000000 {
+			return xerrors.Errorf("Value in field \"mentions\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("mentions"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("mentions")); err != nil {
+			return err
+		}
+
+		if len(t.Mentions) > 8192 {
+			return xerrors.Errorf("Slice value in field t.Mentions was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajArray, uint64(len(t.Mentions))); err != nil {
+			return err
+		}
+		for _, v := range t.Mentions {
+			if len(v) > 1000000 {
+				return xerrors.Errorf("Value in field v was too long")
+			}
+
+			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(v))); err != nil {
+				return err
+			}
+			if _, err := cw.WriteString(string(v)); err != nil {
+				return err
+			}
+
+		}
+	}
+
+	// t.CreatedAt (string) (string)
+	if len("createdAt") > 1000000 {
+		return xerrors.Errorf("Value in field \"createdAt\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("createdAt"))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("state")); err != nil {
+	if _, err := cw.WriteString(string("createdAt")); err != nil {
 		return err
 	}
 
-	if len(t.State) > 1000000 {
-		return xerrors.Errorf("Value in field t.State was too long")
+	if len(t.CreatedAt) > 1000000 {
+		return xerrors.Errorf("Value in field t.CreatedAt was too long")
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.State))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.CreatedAt))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string(t.State)); err != nil {
+	if _, err := cw.WriteString(string(t.CreatedAt)); err != nil {
 		return err
 	}
+
+	// t.References ([]string) (slice)
+	if t.References != nil {
+
+		if len("references") > 1000000 {
+			return xerrors.Errorf("Value in field \"references\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("references"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("references")); err != nil {
+			return err
+		}
+
+		if len(t.References) > 8192 {
+			return xerrors.Errorf("Slice value in field t.References was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajArray, uint64(len(t.References))); err != nil {
+			return err
+		}
+		for _, v := range t.References {
+			if len(v) > 1000000 {
+				return xerrors.Errorf("Value in field v was too long")
+			}
+
+			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(v))); err != nil {
+				return err
+			}
+			if _, err := cw.WriteString(string(v)); err != nil {
+				return err
+			}
+
+		}
+	}
 	return nil
 }
 
-func (t *RepoIssueState) UnmarshalCBOR(r io.Reader) (err error) {
-	*t = RepoIssueState{}
+func (t *RepoIssueComment) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = RepoIssueComment{}
 
 	cr := cbg.NewCborReader(r)
 
@@ -9364,12 +10097,12 @@ func (t *RepoIssueState) UnmarshalCBOR(r io.Reader) (err error) {
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("RepoIssueState: map struct too large (%d)", extra)
+		return fmt.Errorf("RepoIssueComment: map struct too large (%d)", extra)
 	}
 
 	n := extra
 
-	nameBuf := make([]byte, 5)
+	nameBuf := make([]byte, 10)
 	for i := uint64(0); i < n; i++ {
 		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
 		if err != nil {
@@ -9385,7 +10118,18 @@ func (t *RepoIssueState) UnmarshalCBOR(r io.Reader) (err error) {
 		}
 
 		switch string(nameBuf[:nameLen]) {
-		// t.LexiconTypeID (string) (string)
+		// t.Body (string) (string)
+		case "body":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.Body = string(sval)
+			}
+			// t.LexiconTypeID (string) (string)
 		case "$type":
 
 			{
@@ -9407,54 +10151,319 @@ func (t *RepoIssueState) UnmarshalCBOR(r io.Reader) (err error) {
 
 				t.Issue = string(sval)
 			}
-			// t.State (string) (string)
-		case "state":
+			// t.ReplyTo (string) (string)
+		case "replyTo":
 
 			{
-				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				b, err := cr.ReadByte()
 				if err != nil {
 					return err
 				}
+				if b != cbg.CborNull[0] {
+					if err := cr.UnreadByte(); err != nil {
+						return err
+					}
 
-				t.State = string(sval)
+					sval, err := cbg.ReadStringWithMax(cr, 1000000)
+					if err != nil {
+						return err
+					}
+
+					t.ReplyTo = (*string)(&sval)
+				}
 			}
+			// t.Mentions ([]string) (slice)
+		case "mentions":
 
-		default:
-			// Field doesn't exist on this type, so ignore it
-			if err := cbg.ScanForLinks(r, func(cid.Cid) {}); err != nil {
+			maj, extra, err = cr.ReadHeader()
+			if err != nil {
 				return err
 			}
-		}
-	}
 
-	return nil
-}
-func (t *RepoPull) MarshalCBOR(w io.Writer) error {
-	if t == nil {
-		_, err := w.Write(cbg.CborNull)
-		return err
-	}
+			if extra > 8192 {
+				return fmt.Errorf("t.Mentions: array too large (%d)", extra)
+			}
 
-	cw := cbg.NewCborWriter(w)
-	fieldCount := 10
+			if maj != cbg.MajArray {
+				return fmt.Errorf("expected cbor array")
+			}
 
-	if t.Body == nil {
-		fieldCount--
-	}
+			if extra > 0 {
+				t.Mentions = make([]string, extra)
+			}
 
-	if t.Mentions == nil {
-		fieldCount--
-	}
+			for i := 0; i < int(extra); i++ {
+				{
+					var maj byte
+					var extra uint64
+					var err error
+					_ = maj
+					_ = extra
+					_ = err
 
-	if t.Patch == nil {
-		fieldCount--
-	}
+					{
+						sval, err := cbg.ReadStringWithMax(cr, 1000000)
+						if err != nil {
+							return err
+						}
 
-	if t.References == nil {
-		fieldCount--
-	}
+						t.Mentions[i] = string(sval)
+					}
 
-	if t.Source == nil {
+				}
+			}
+			// t.CreatedAt (string) (string)
+		case "createdAt":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.CreatedAt = string(sval)
+			}
+			// t.References ([]string) (slice)
+		case "references":
+
+			maj, extra, err = cr.ReadHeader()
+			if err != nil {
+				return err
+			}
+
+			if extra > 8192 {
+				return fmt.Errorf("t.References: array too large (%d)", extra)
+			}
+
+			if maj != cbg.MajArray {
+				return fmt.Errorf("expected cbor array")
+			}
+
+			if extra > 0 {
+				t.References = make([]string, extra)
+			}
+
+			for i := 0; i < int(extra); i++ {
+				{
+					var maj byte
+					var extra uint64
+					var err error
+					_ = maj
+					_ = extra
+					_ = err
+
+					{
+						sval, err := cbg.ReadStringWithMax(cr, 1000000)
+						if err != nil {
+							return err
+						}
+
+						t.References[i] = string(sval)
+					}
+
+				}
+			}
+
+		default:
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(r, func(cid.Cid) {}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+func (t *RepoIssueState) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+
+	cw := cbg.NewCborWriter(w)
+
+	if _, err := cw.Write([]byte{163}); err != nil {
+		return err
+	}
+
+	// t.LexiconTypeID (string) (string)
+	if len("$type") > 1000000 {
+		return xerrors.Errorf("Value in field \"$type\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("$type"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("$type")); err != nil {
+		return err
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.issue.state"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("sh.tangled.repo.issue.state")); err != nil {
+		return err
+	}
+
+	// t.Issue (string) (string)
+	if len("issue") > 1000000 {
+		return xerrors.Errorf("Value in field \"issue\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("issue"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("issue")); err != nil {
+		return err
+	}
+
+	if len(t.Issue) > 1000000 {
+		return xerrors.Errorf("Value in field t.Issue was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Issue))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.Issue)); err != nil {
+		return err
+	}
+
+	// t.State (string) (string)
+	if len("state") > 1000000 {
+		return xerrors.Errorf("Value in field \"state\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("state"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("state")); err != nil {
+		return err
+	}
+
+	if len(t.State) > 1000000 {
+		return xerrors.Errorf("Value in field t.State was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.State))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.State)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *RepoIssueState) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = RepoIssueState{}
+
+	cr := cbg.NewCborReader(r)
+
+	maj, extra, err := cr.ReadHeader()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	if maj != cbg.MajMap {
+		return fmt.Errorf("cbor input should be of type map")
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("RepoIssueState: map struct too large (%d)", extra)
+	}
+
+	n := extra
+
+	nameBuf := make([]byte, 5)
+	for i := uint64(0); i < n; i++ {
+		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(cr, func(cid.Cid) {}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch string(nameBuf[:nameLen]) {
+		// t.LexiconTypeID (string) (string)
+		case "$type":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.LexiconTypeID = string(sval)
+			}
+			// t.Issue (string) (string)
+		case "issue":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.Issue = string(sval)
+			}
+			// t.State (string) (string)
+		case "state":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.State = string(sval)
+			}
+
+		default:
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(r, func(cid.Cid) {}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+func (t *RepoPull) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+
+	cw := cbg.NewCborWriter(w)
+	fieldCount := 10
+
+	if t.Body == nil {
+		fieldCount--
+	}
+
+	if t.Mentions == nil {
+		fieldCount--
+	}
+
+	if t.Patch == nil {
+		fieldCount--
+	}
+
+	if t.References == nil {
+		fieldCount--
+	}
+
+	if t.Source == nil {
 		fieldCount--
 	}
 
@@ -9990,13 +10999,809 @@ func (t *RepoPullComment) MarshalCBOR(w io.Writer) error {
 	}
 
 	cw := cbg.NewCborWriter(w)
-	fieldCount := 6
+	fieldCount := 9
+
+	if t.Anchor == nil {
+		fieldCount--
+	}
 
 	if t.Mentions == nil {
 		fieldCount--
 	}
 
-	if t.References == nil {
+	if t.References == nil {
+		fieldCount--
+	}
+
+	if t.ReplyTo == nil {
+		fieldCount--
+	}
+
+	if t.Resolved == nil {
+		fieldCount--
+	}
+
+	if _, err := cw.Write(cbg.CborEncodeMajorType(cbg.MajMap, uint64(fieldCount))); err != nil {
+		return err
+	}
+
+	// t.Body (string) (string)
+	if len("body") > 1000000 {
+		return xerrors.Errorf("Value in field \"body\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("body"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("body")); err != nil {
+		return err
+	}
+
+	if len(t.Body) > 1000000 {
+		return xerrors.Errorf("Value in field t.Body was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Body))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.Body)); err != nil {
+		return err
+	}
+
+	// t.Pull (string) (string)
+	if len("pull") > 1000000 {
+		return xerrors.Errorf("Value in field \"pull\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("pull"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("pull")); err != nil {
+		return err
+	}
+
+	if len(t.Pull) > 1000000 {
+		return xerrors.Errorf("Value in field t.Pull was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Pull))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.Pull)); err != nil {
+		return err
+	}
+
+	// t.LexiconTypeID (string) (string)
+	if len("$type") > 1000000 {
+		return xerrors.Errorf("Value in field \"$type\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("$type"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("$type")); err != nil {
+		return err
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.pull.comment"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("sh.tangled.repo.pull.comment")); err != nil {
+		return err
+	}
+
+	// t.Anchor (tangled.RepoPullComment_Anchor) (struct)
+	if t.Anchor != nil {
+
+		if len("anchor") > 1000000 {
+			return xerrors.Errorf("Value in field \"anchor\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("anchor"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("anchor")); err != nil {
+			return err
+		}
+
+		if err := t.Anchor.MarshalCBOR(cw); err != nil {
+			return err
+		}
+	}
+
+	// t.ReplyTo (string) (string)
+	if t.ReplyTo != nil {
+
+		if len("replyTo") > 1000000 {
+			return xerrors.Errorf("Value in field \"replyTo\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("replyTo"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("replyTo")); err != nil {
+			return err
+		}
+
+		if t.ReplyTo == nil {
+			if _, err := cw.Write(cbg.CborNull); err != nil {
+				return err
+			}
+		} else {
+			if len(*t.ReplyTo) > 1000000 {
+				return xerrors.Errorf("Value in field t.ReplyTo was too long")
+			}
+
+			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(*t.ReplyTo))); err != nil {
+				return err
+			}
+			if _, err := cw.WriteString(string(*t.ReplyTo)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// t.Mentions ([]string) (slice)
+	if t.Mentions != nil {
+
+		if len("mentions") > 1000000 {
+			return xerrors.Errorf("Value in field \"mentions\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("mentions"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("mentions")); err != nil {
+			return err
+		}
+
+		if len(t.Mentions) > 8192 {
+			return xerrors.Errorf("Slice value in field t.Mentions was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajArray, uint64(len(t.Mentions))); err != nil {
+			return err
+		}
+		for _, v := range t.Mentions {
+			if len(v) > 1000000 {
+				return xerrors.Errorf("Value in field v was too long")
+			}
+
+			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(v))); err != nil {
+				return err
+			}
+			if _, err := cw.WriteString(string(v)); err != nil {
+				return err
+			}
+
+		}
+	}
+
+	// t.Resolved (bool) (bool)
+	if t.Resolved != nil {
+
+		if len("resolved") > 1000000 {
+			return xerrors.Errorf("Value in field \"resolved\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("resolved"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("resolved")); err != nil {
+			return err
+		}
+
+		if t.Resolved == nil {
+			if _, err := cw.Write(cbg.CborNull); err != nil {
+				return err
+			}
+		} else {
+			if err := cbg.WriteBool(w, *t.Resolved); err != nil {
+				return err
+			}
+		}
+	}
+
+	// t.CreatedAt (string) (string)
+	if len("createdAt") > 1000000 {
+		return xerrors.Errorf("Value in field \"createdAt\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("createdAt"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("createdAt")); err != nil {
+		return err
+	}
+
+	if len(t.CreatedAt) > 1000000 {
+		return xerrors.Errorf("Value in field t.CreatedAt was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.CreatedAt))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.CreatedAt)); err != nil {
+		return err
+	}
+
+	// t.References ([]string) (slice)
+	if t.References != nil {
+
+		if len("references") > 1000000 {
+			return xerrors.Errorf("Value in field \"references\" was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("references"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("references")); err != nil {
+			return err
+		}
+
+		if len(t.References) > 8192 {
+			return xerrors.Errorf("Slice value in field t.References was too long")
+		}
+
+		if err := cw.WriteMajorTypeHeader(cbg.MajArray, uint64(len(t.References))); err != nil {
+			return err
+		}
+		for _, v := range t.References {
+			if len(v) > 1000000 {
+				return xerrors.Errorf("Value in field v was too long")
+			}
+
+			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(v))); err != nil {
+				return err
+			}
+			if _, err := cw.WriteString(string(v)); err != nil {
+				return err
+			}
+
+		}
+	}
+	return nil
+}
+
+func (t *RepoPullComment) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = RepoPullComment{}
+
+	cr := cbg.NewCborReader(r)
+
+	maj, extra, err := cr.ReadHeader()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	if maj != cbg.MajMap {
+		return fmt.Errorf("cbor input should be of type map")
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("RepoPullComment: map struct too large (%d)", extra)
+	}
+
+	n := extra
+
+	nameBuf := make([]byte, 10)
+	for i := uint64(0); i < n; i++ {
+		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(cr, func(cid.Cid) {}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch string(nameBuf[:nameLen]) {
+		// t.Body (string) (string)
+		case "body":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.Body = string(sval)
+			}
+			// t.Pull (string) (string)
+		case "pull":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.Pull = string(sval)
+			}
+			// t.LexiconTypeID (string) (string)
+		case "$type":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.LexiconTypeID = string(sval)
+			}
+			// t.Anchor (tangled.RepoPullComment_Anchor) (struct)
+		case "anchor":
+
+			{
+
+				b, err := cr.ReadByte()
+				if err != nil {
+					return err
+				}
+				if b != cbg.CborNull[0] {
+					if err := cr.UnreadByte(); err != nil {
+						return err
+					}
+					t.Anchor = new(RepoPullComment_Anchor)
+					if err := t.Anchor.UnmarshalCBOR(cr); err != nil {
+						return xerrors.Errorf("unmarshaling t.Anchor pointer: %w", err)
+					}
+				}
+
+			}
+			// t.ReplyTo (string) (string)
+		case "replyTo":
+
+			{
+				b, err := cr.ReadByte()
+				if err != nil {
+					return err
+				}
+				if b != cbg.CborNull[0] {
+					if err := cr.UnreadByte(); err != nil {
+						return err
+					}
+
+					sval, err := cbg.ReadStringWithMax(cr, 1000000)
+					if err != nil {
+						return err
+					}
+
+					t.ReplyTo = (*string)(&sval)
+				}
+			}
+			// t.Mentions ([]string) (slice)
+		case "mentions":
+
+			maj, extra, err = cr.ReadHeader()
+			if err != nil {
+				return err
+			}
+
+			if extra > 8192 {
+				return fmt.Errorf("t.Mentions: array too large (%d)", extra)
+			}
+
+			if maj != cbg.MajArray {
+				return fmt.Errorf("expected cbor array")
+			}
+
+			if extra > 0 {
+				t.Mentions = make([]string, extra)
+			}
+
+			for i := 0; i < int(extra); i++ {
+				{
+					var maj byte
+					var extra uint64
+					var err error
+					_ = maj
+					_ = extra
+					_ = err
+
+					{
+						sval, err := cbg.ReadStringWithMax(cr, 1000000)
+						if err != nil {
+							return err
+						}
+
+						t.Mentions[i] = string(sval)
+					}
+
+				}
+			}
+			// t.Resolved (bool) (bool)
+		case "resolved":
+
+			{
+				b, err := cr.ReadByte()
+				if err != nil {
+					return err
+				}
+				if b != cbg.CborNull[0] {
+					if err := cr.UnreadByte(); err != nil {
+						return err
+					}
+
+					maj, extra, err = cr.ReadHeader()
+					if err != nil {
+						return err
+					}
+					if maj != cbg.MajOther {
+						return fmt.Errorf("booleans must be major type 7")
+					}
+
+					var val bool
+					switch extra {
+					case 20:
+						val = false
+					case 21:
+						val = true
+					default:
+						return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+					}
+					t.Resolved = &val
+				}
+			}
+			// t.CreatedAt (string) (string)
+		case "createdAt":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.CreatedAt = string(sval)
+			}
+			// t.References ([]string) (slice)
+		case "references":
+
+			maj, extra, err = cr.ReadHeader()
+			if err != nil {
+				return err
+			}
+
+			if extra > 8192 {
+				return fmt.Errorf("t.References: array too large (%d)", extra)
+			}
+
+			if maj != cbg.MajArray {
+				return fmt.Errorf("expected cbor array")
+			}
+
+			if extra > 0 {
+				t.References = make([]string, extra)
+			}
+
+			for i := 0; i < int(extra); i++ {
+				{
+					var maj byte
+					var extra uint64
+					var err error
+					_ = maj
+					_ = extra
+					_ = err
+
+					{
+						sval, err := cbg.ReadStringWithMax(cr, 1000000)
+						if err != nil {
+							return err
+						}
+
+						t.References[i] = string(sval)
+					}
+
+				}
+			}
+
+		default:
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(r, func(cid.Cid) {}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+func (t *RepoPullComment_Anchor) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+
+	cw := cbg.NewCborWriter(w)
+
+	if _, err := cw.Write([]byte{165}); err != nil {
+		return err
+	}
+
+	// t.Path (string) (string)
+	if len("path") > 1000000 {
+		return xerrors.Errorf("Value in field \"path\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("path"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("path")); err != nil {
+		return err
+	}
+
+	if len(t.Path) > 1000000 {
+		return xerrors.Errorf("Value in field t.Path was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Path))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.Path)); err != nil {
+		return err
+	}
+
+	// t.Side (string) (string)
+	if len("side") > 1000000 {
+		return xerrors.Errorf("Value in field \"side\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("side"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("side")); err != nil {
+		return err
+	}
+
+	if len(t.Side) > 1000000 {
+		return xerrors.Errorf("Value in field t.Side was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Side))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.Side)); err != nil {
+		return err
+	}
+
+	// t.Round (int64) (int64)
+	if len("round") > 1000000 {
+		return xerrors.Errorf("Value in field \"round\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("round"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("round")); err != nil {
+		return err
+	}
+
+	if t.Round >= 0 {
+		if err := cw.WriteMajorTypeHeader(cbg.MajUnsignedInt, uint64(t.Round)); err != nil {
+			return err
+		}
+	} else {
+		if err := cw.WriteMajorTypeHeader(cbg.MajNegativeInt, uint64(-t.Round-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.EndLine (int64) (int64)
+	if len("endLine") > 1000000 {
+		return xerrors.Errorf("Value in field \"endLine\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("endLine"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("endLine")); err != nil {
+		return err
+	}
+
+	if t.EndLine >= 0 {
+		if err := cw.WriteMajorTypeHeader(cbg.MajUnsignedInt, uint64(t.EndLine)); err != nil {
+			return err
+		}
+	} else {
+		if err := cw.WriteMajorTypeHeader(cbg.MajNegativeInt, uint64(-t.EndLine-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.StartLine (int64) (int64)
+	if len("startLine") > 1000000 {
+		return xerrors.Errorf("Value in field \"startLine\" was too long")
+	}
+
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("startLine"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("startLine")); err != nil {
+		return err
+	}
+
+	if t.StartLine >= 0 {
+		if err := cw.WriteMajorTypeHeader(cbg.MajUnsignedInt, uint64(t.StartLine)); err != nil {
+			return err
+		}
+	} else {
+		if err := cw.WriteMajorTypeHeader(cbg.MajNegativeInt, uint64(-t.StartLine-1)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *RepoPullComment_Anchor) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = RepoPullComment_Anchor{}
+
+	cr := cbg.NewCborReader(r)
+
+	maj, extra, err := cr.ReadHeader()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	if maj != cbg.MajMap {
+		return fmt.Errorf("cbor input should be of type map")
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("RepoPullComment_Anchor: map struct too large (%d)", extra)
+	}
+
+	n := extra
+
+	nameBuf := make([]byte, 9)
+	for i := uint64(0); i < n; i++ {
+		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(cr, func(cid.Cid) {}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch string(nameBuf[:nameLen]) {
+		// t.Path (string) (string)
+		case "path":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.Path = string(sval)
+			}
+			// t.Side (string) (string)
+		case "side":
+
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
+				}
+
+				t.Side = string(sval)
+			}
+			// t.Round (int64) (int64)
+		case "round":
+			{
+				maj, extra, err := cr.ReadHeader()
+				if err != nil {
+					return err
+				}
+				var extraI int64
+				switch maj {
+				case cbg.MajUnsignedInt:
+					extraI = int64(extra)
+					if extraI < 0 {
+						return fmt.Errorf("int64 positive overflow")
+					}
+				case cbg.MajNegativeInt:
+					extraI = int64(extra)
+					if extraI < 0 {
+						return fmt.Errorf("int64 negative overflow")
+					}
+					extraI = -1 - extraI
+				default:
+					return fmt.Errorf("wrong type for int64 field: %d", maj)
+				}
+
+				t.Round = int64(extraI)
+			}
+			// t.EndLine (int64) (int64)
+		case "endLine":
+			{
+				maj, extra, err := cr.ReadHeader()
+				if err != nil {
+					return err
+				}
+				var extraI int64
+				switch maj {
+				case cbg.MajUnsignedInt:
+					extraI = int64(extra)
+					if extraI < 0 {
+						return fmt.Errorf("int64 positive overflow")
+					}
+				case cbg.MajNegativeInt:
+					extraI = int64(extra)
+					if extraI < 0 {
+						return fmt.Errorf("int64 negative overflow")
+					}
+					extraI = -1 - extraI
+				default:
+					return fmt.Errorf("wrong type for int64 field: %d", maj)
+				}
+
+				t.EndLine = int64(extraI)
+			}
+			// t.StartLine (int64) (int64)
+		case "startLine":
+			{
+				maj, extra, err := cr.ReadHeader()
+				if err != nil {
+					return err
+				}
+				var extraI int64
+				switch maj {
+				case cbg.MajUnsignedInt:
+					extraI = int64(extra)
+					if extraI < 0 {
+						return fmt.Errorf("int64 positive overflow")
+					}
+				case cbg.MajNegativeInt:
+					extraI = int64(extra)
+					if extraI < 0 {
+						return fmt.Errorf("int64 negative overflow")
+					}
+					extraI = -1 - extraI
+				default:
+					return fmt.Errorf("wrong type for int64 field: %d", maj)
+				}
+
+				t.StartLine = int64(extraI)
+			}
+
+		default:
+			// Field doesn't exist on this type, so ignore it
+			if err := cbg.ScanForLinks(r, func(cid.Cid) {}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+func (t *RepoPullReview) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+
+	cw := cbg.NewCborWriter(w)
+	fieldCount := 6
+
+	if t.Body == nil {
 		fieldCount--
 	}
 
@@ -10005,26 +11810,35 @@ func (t *RepoPullComment) MarshalCBOR(w io.Writer) error {
 	}
 
 	// t.Body (string) (string)
-	if len("body") > 1000000 {
-		return xerrors.Errorf("Value in field \"body\" was too long")
-	}
+	if t.Body != nil {
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("body"))); err != nil {
-		return err
-	}
-	if _, err := cw.WriteString(string("body")); err != nil {
-		return err
-	}
+		if len("body") > 1000000 {
+			return xerrors.Errorf("Value in field \"body\" was too long")
+		}
 
-	if len(t.Body) > 1000000 {
-		return xerrors.Errorf("Value in field t.Body was too long")
-	}
+		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("body"))); err != nil {
+			return err
+		}
+		if _, err := cw.WriteString(string("body")); err != nil {
+			return err
+		}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.Body))); err != nil {
-		return err
-	}
-	if _, err := cw.WriteString(string(t.Body)); err != nil {
-		return err
+		if t.Body == nil {
+			if _, err := cw.Write(cbg.CborNull); err != nil {
+				return err
+			}
+		} else {
+			if len(*t.Body) > 1000000 {
+				return xerrors.Errorf("Value in field t.Body was too long")
+			}
+
+			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(*t.Body))); err != nil {
+				return err
+			}
+			if _, err := cw.WriteString(string(*t.Body)); err != nil {
+				return err
+			}
+		}
 	}
 
 	// t.Pull (string) (string)
@@ -10062,47 +11876,56 @@ func (t *RepoPullComment) MarshalCBOR(w io.Writer) error {
 		return err
 	}
 
-	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.pull.comment"))); err != nil {
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("sh.tangled.repo.pull.review"))); err != nil {
 		return err
 	}
-	if _, err := cw.WriteString(string("sh.tangled.repo.pull.comment")); err != nil {
+	if _, err := cw.WriteString(string("sh.tangled.repo.pull.review")); err != nil {
 		return err
 	}
 
-	// t.Mentions ([]string) (slice)
-	if t.Mentions != nil {
+	// t.Round (int64) (int64)
+	if len("round") > 1000000 {
+		return xerrors.Errorf("Value in field \"round\" was too long")
+	}
 
-		if len("mentions") > 1000000 {
-			return xerrors.Errorf("Value in field \"mentions\" was too long")
-		}
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("round"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("round")); err != nil {
+		return err
+	}
 
-		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("mentions"))); err != nil {
+	if t.Round >= 0 {
+		if err := cw.WriteMajorTypeHeader(cbg.MajUnsignedInt, uint64(t.Round)); err != nil {
 			return err
 		}
-		if _, err := cw.WriteString(string("mentions")); err != nil {
+	} else {
+		if err := cw.WriteMajorTypeHeader(cbg.MajNegativeInt, uint64(-t.Round-1)); err != nil {
 			return err
 		}
+	}
 
-		if len(t.Mentions) > 8192 {
-			return xerrors.Errorf("Slice value in field t.Mentions was too long")
-		}
+	// t.State (string) (string)
+	if len("state") > 1000000 {
+		return xerrors.Errorf("Value in field \"state\" was too long")
+	}
 
-		if err := cw.WriteMajorTypeHeader(cbg.MajArray, uint64(len(t.Mentions))); err != nil {
-			return err
-		}
-		for _, v := range t.Mentions {
-			if len(v) > 1000000 {
-				return xerrors.Errorf("Value in field v was too long")
-			}
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("state"))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string("state")); err != nil {
+		return err
+	}
 
-			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(v))); err != nil {
-				return err
-			}
-			if _, err := cw.WriteString(string(v)); err != nil {
-				return err
-			}
+	if len(t.State) > 1000000 {
+		return xerrors.Errorf("Value in field t.State was too long")
+	}
 
-		}
+	if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(t.State))); err != nil {
+		return err
+	}
+	if _, err := cw.WriteString(string(t.State)); err != nil {
+		return err
 	}
 
 	// t.CreatedAt (string) (string)
@@ -10127,47 +11950,11 @@ func (t *RepoPullComment) MarshalCBOR(w io.Writer) error {
 	if _, err := cw.WriteString(string(t.CreatedAt)); err != nil {
 		return err
 	}
-
-	// t.References ([]string) (slice)
-	if t.References != nil {
-
-		if len("references") > 1000000 {
-			return xerrors.Errorf("Value in field \"references\" was too long")
-		}
-
-		if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len("references"))); err != nil {
-			return err
-		}
-		if _, err := cw.WriteString(string("references")); err != nil {
-			return err
-		}
-
-		if len(t.References) > 8192 {
-			return xerrors.Errorf("Slice value in field t.References was too long")
-		}
-
-		if err := cw.WriteMajorTypeHeader(cbg.MajArray, uint64(len(t.References))); err != nil {
-			return err
-		}
-		for _, v := range t.References {
-			if len(v) > 1000000 {
-				return xerrors.Errorf("Value in field v was too long")
-			}
-
-			if err := cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(v))); err != nil {
-				return err
-			}
-			if _, err := cw.WriteString(string(v)); err != nil {
-				return err
-			}
-
-		}
-	}
 	return nil
 }
 
-func (t *RepoPullComment) UnmarshalCBOR(r io.Reader) (err error) {
-	*t = RepoPullComment{}
+func (t *RepoPullReview) UnmarshalCBOR(r io.Reader) (err error) {
+	*t = RepoPullReview{}
 
 	cr := cbg.NewCborReader(r)
 
@@ -10186,12 +11973,12 @@ func (t *RepoPullComment) UnmarshalCBOR(r io.Reader) (err error) {
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("RepoPullComment: map struct too large (%d)", extra)
+		return fmt.Errorf("RepoPullReview: map struct too large (%d)", extra)
 	}
 
 	n := extra
 
-	nameBuf := make([]byte, 10)
+	nameBuf := make([]byte, 9)
 	for i := uint64(0); i < n; i++ {
 		nameLen, ok, err := cbg.ReadFullStringIntoBuf(cr, nameBuf, 1000000)
 		if err != nil {
@@ -10211,12 +11998,22 @@ func (t *RepoPullComment) UnmarshalCBOR(r io.Reader) (err error) {
 		case "body":
 
 			{
-				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				b, err := cr.ReadByte()
 				if err != nil {
 					return err
 				}
+				if b != cbg.CborNull[0] {
+					if err := cr.UnreadByte(); err != nil {
+						return err
+					}
 
-				t.Body = string(sval)
+					sval, err := cbg.ReadStringWithMax(cr, 1000000)
+					if err != nil {
+						return err
+					}
+
+					t.Body = (*string)(&sval)
+				}
 			}
 			// t.Pull (string) (string)
 		case "pull":
@@ -10240,45 +12037,42 @@ func (t *RepoPullComment) UnmarshalCBOR(r io.Reader) (err error) {
 
 				t.LexiconTypeID = string(sval)
 			}
-			// t.Mentions ([]string) (slice)
-		case "mentions":
-
-			maj, extra, err = cr.ReadHeader()
-			if err != nil {
-				return err
-			}
-
-			if extra > 8192 {
-				return fmt.Errorf("t.Mentions: array too large (%d)", extra)
-			}
-
-			if maj != cbg.MajArray {
-				return fmt.Errorf("expected cbor array")
-			}
+			// t.Round (int64) (int64)
+		case "round":
+			{
+				maj, extra, err := cr.ReadHeader()
+				if err != nil {
+					return err
+				}
+				var extraI int64
+				switch maj {
+				case cbg.MajUnsignedInt:
+					extraI = int64(extra)
+					if extraI < 0 {
+						return fmt.Errorf("int64 positive overflow")
+					}
+				case cbg.MajNegativeInt:
+					extraI = int64(extra)
+					if extraI < 0 {
+						return fmt.Errorf("int64 negative overflow")
+					}
+					extraI = -1 - extraI
+				default:
+					return fmt.Errorf("wrong type for int64 field: %d", maj)
+				}
 
-			if extra > 0 {
-				t.Mentions = make([]string, extra)
+				t.Round = int64(extraI)
 			}
+			// t.State (string) (string)
+		case "state":
 
-			for i := 0; i < int(extra); i++ {
-				{
-					var maj byte
-					var extra uint64
-					var err error
-					_ = maj
-					_ = extra
-					_ = err
-
-					{
-						sval, err := cbg.ReadStringWithMax(cr, 1000000)
-						if err != nil {
-							return err
-						}
-
-						t.Mentions[i] = string(sval)
-					}
-
+			{
+				sval, err := cbg.ReadStringWithMax(cr, 1000000)
+				if err != nil {
+					return err
 				}
+
+				t.State = string(sval)
 			}
 			// t.CreatedAt (string) (string)
 		case "createdAt":
@@ -10291,46 +12085,6 @@ func (t *RepoPullComment) UnmarshalCBOR(r io.Reader) (err error) {
 
 				t.CreatedAt = string(sval)
 			}
-			// t.References ([]string) (slice)
-		case "references":
-
-			maj, extra, err = cr.ReadHeader()
-			if err != nil {
-				return err
-			}
-
-			if extra > 8192 {
-				return fmt.Errorf("t.References: array too large (%d)", extra)
-			}
-
-			if maj != cbg.MajArray {
-				return fmt.Errorf("expected cbor array")
-			}
-
-			if extra > 0 {
-				t.References = make([]string, extra)
-			}
-
-			for i := 0; i < int(extra); i++ {
-				{
-					var maj byte
-					var extra uint64
-					var err error
-					_ = maj
-					_ = extra
-					_ = err
-
-					{
-						sval, err := cbg.ReadStringWithMax(cr, 1000000)
-						if err != nil {
-							return err
-						}
-
-						t.References[i] = string(sval)
-					}
-
-				}
-			}
 
 		default:
 			// Field doesn't exist on this type, so ignore it
@@ -30,6 +30,8 @@ type PijulRefUpdate struct {
 	OldState *string `json:"oldState,omitempty" cborgen:"oldState,omitempty"`
 	// repo: Repository identifier
 	Repo string `json:"repo" cborgen:"repo"`
+	// unrecorded: List of change hashes that were unrecorded
+	Unrecorded []string `json:"unrecorded,omitempty" cborgen:"unrecorded,omitempty"`
 }
 
 // Map of language name to lines of code
@@ -17,10 +17,27 @@ func init() {
 } //
 // RECORDTYPE: RepoPullComment
 type RepoPullComment struct {
-	LexiconTypeID string   `json:"$type,const=sh.tangled.repo.pull.comment" cborgen:"$type,const=sh.tangled.repo.pull.comment"`
-	Body          string   `json:"body" cborgen:"body"`
-	CreatedAt     string   `json:"createdAt" cborgen:"createdAt"`
-	Mentions      []string `json:"mentions,omitempty" cborgen:"mentions,omitempty"`
-	Pull          string   `json:"pull" cborgen:"pull"`
-	References    []string `json:"references,omitempty" cborgen:"references,omitempty"`
+	LexiconTypeID string `json:"$type,const=sh.tangled.repo.pull.comment" cborgen:"$type,const=sh.tangled.repo.pull.comment"`
+	// anchor: lines of a submission's patch that this comment starts a review thread on
+	Anchor     *RepoPullComment_Anchor `json:"anchor,omitempty" cborgen:"anchor,omitempty"`
+	Body       string                  `json:"body" cborgen:"body"`
+	CreatedAt  string                  `json:"createdAt" cborgen:"createdAt"`
+	Mentions   []string                `json:"mentions,omitempty" cborgen:"mentions,omitempty"`
+	Pull       string                  `json:"pull" cborgen:"pull"`
+	References []string                `json:"references,omitempty" cborgen:"references,omitempty"`
+	// replyTo: the anchored comment that starts the thread this comment replies to
+	ReplyTo *string `json:"replyTo,omitempty" cborgen:"replyTo,omitempty"`
+	// resolved: marks the thread this comment replies to as resolved, or reopens it
+	Resolved *bool `json:"resolved,omitempty" cborgen:"resolved,omitempty"`
+}
+
+// RepoPullComment_Anchor is a "anchor" in the sh.tangled.repo.pull.comment schema.
+type RepoPullComment_Anchor struct {
+	EndLine int64  `json:"endLine" cborgen:"endLine"`
+	Path    string `json:"path" cborgen:"path"`
+	// round: round of the pull whose patch the lines belong to
+	Round int64 `json:"round" cborgen:"round"`
+	// side: whether the lines are numbered in the old or the new version of the file
+	Side      string `json:"side" cborgen:"side"`
+	StartLine int64  `json:"startLine" cborgen:"startLine"`
 }
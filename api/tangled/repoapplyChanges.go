@@ -16,6 +16,8 @@ const (
 
 // RepoApplyChanges_Input is the input argument to a sh.tangled.repo.applyChanges call.
 type RepoApplyChanges_Input struct {
+	// atomic: Apply the changes along with their missing dependencies, all or nothing. The channel is rolled back if any of them fails to apply.
+	Atomic *bool `json:"atomic,omitempty" cborgen:"atomic,omitempty"`
 	// changes: List of change hashes to apply (in order)
 	Changes []string `json:"changes" cborgen:"changes"`
 	// channel: Target channel to apply changes to
@@ -24,8 +24,10 @@ type RepoArtifact struct {
 	CreatedAt string `json:"createdAt" cborgen:"createdAt"`
 	// name: name of the artifact
 	Name string `json:"name" cborgen:"name"`
+	// pijulState: state of the Pijul tag that this artifact is attached to, set instead of tag for Pijul repositories
+	PijulState *string `json:"pijulState,omitempty" cborgen:"pijulState,omitempty"`
 	// repo: repo that this artifact is being uploaded to
 	Repo string `json:"repo" cborgen:"repo"`
-	// tag: hash of the tag object that this artifact is attached to (only annotated tags are supported)
+	// tag: hash of the tag object that this artifact is attached to (only annotated tags are supported). Required for git repositories
 	Tag util.LexBytes `json:"tag,omitempty" cborgen:"tag,omitempty"`
 }
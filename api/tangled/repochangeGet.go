@@ -20,17 +20,54 @@ type RepoChangeGet_Author struct {
 	Name  string  `json:"name" cborgen:"name"`
 }
 
+// RepoChangeGet_DiffStats is a "diffStats" in the sh.tangled.repo.changeGet schema.
+type RepoChangeGet_DiffStats struct {
+	Additions    int64 `json:"additions" cborgen:"additions"`
+	Deletions    int64 `json:"deletions" cborgen:"deletions"`
+	FilesChanged int64 `json:"filesChanged" cborgen:"filesChanged"`
+}
+
+// RepoChangeGet_FileDiff is a "fileDiff" in the sh.tangled.repo.changeGet schema.
+type RepoChangeGet_FileDiff struct {
+	Additions int64                 `json:"additions" cborgen:"additions"`
+	Deletions int64                 `json:"deletions" cborgen:"deletions"`
+	Hunks     []*RepoChangeGet_Hunk `json:"hunks,omitempty" cborgen:"hunks,omitempty"`
+	// oldPath: Previous path, for moved files
+	OldPath *string `json:"oldPath,omitempty" cborgen:"oldPath,omitempty"`
+	Path    string  `json:"path" cborgen:"path"`
+	Status  string  `json:"status" cborgen:"status"`
+}
+
+// RepoChangeGet_Hunk is a "hunk" in the sh.tangled.repo.changeGet schema.
+type RepoChangeGet_Hunk struct {
+	Kind string `json:"kind" cborgen:"kind"`
+	// line: First line of the hunk in the new file, when known
+	Line  *int64                    `json:"line,omitempty" cborgen:"line,omitempty"`
+	Lines []*RepoChangeGet_HunkLine `json:"lines,omitempty" cborgen:"lines,omitempty"`
+}
+
+// RepoChangeGet_HunkLine is a "hunkLine" in the sh.tangled.repo.changeGet schema.
+type RepoChangeGet_HunkLine struct {
+	Op   string `json:"op" cborgen:"op"`
+	Text string `json:"text" cborgen:"text"`
+}
+
 // RepoChangeGet_Output is the output of a sh.tangled.repo.changeGet call.
 type RepoChangeGet_Output struct {
 	Authors []*RepoChangeGet_Author `json:"authors" cborgen:"authors"`
+	// description: Longer text recorded after the message
+	Description *string `json:"description,omitempty" cborgen:"description,omitempty"`
 	// dependencies: Hashes of changes this change depends on
 	Dependencies []string `json:"dependencies,omitempty" cborgen:"dependencies,omitempty"`
 	// diff: Raw diff content of the change
 	Diff *string `json:"diff,omitempty" cborgen:"diff,omitempty"`
+	// files: Files touched by the change
+	Files []*RepoChangeGet_FileDiff `json:"files,omitempty" cborgen:"files,omitempty"`
 	// hash: Change hash (base32 encoded)
 	Hash string `json:"hash" cborgen:"hash"`
 	// message: Change description
-	Message string `json:"message" cborgen:"message"`
+	Message string                   `json:"message" cborgen:"message"`
+	Stats   *RepoChangeGet_DiffStats `json:"stats,omitempty" cborgen:"stats,omitempty"`
 	// timestamp: When the change was recorded
 	Timestamp *string `json:"timestamp,omitempty" cborgen:"timestamp,omitempty"`
 }
@@ -18,6 +18,8 @@ const (
 type RepoChannelList_Channel struct {
 	// is_current: Whether this is the currently active channel
 	Is_current *bool `json:"is_current,omitempty" cborgen:"is_current,omitempty"`
+	// is_default: Whether this is the default channel of the repository
+	Is_default *bool `json:"is_default,omitempty" cborgen:"is_default,omitempty"`
 	// name: Channel name
 	Name string `json:"name" cborgen:"name"`
 }
@@ -32,6 +32,12 @@ type RepoMerge_Input struct {
 	Name string `json:"name" cborgen:"name"`
 	// patch: Patch content to merge
 	Patch string `json:"patch" cborgen:"patch"`
+	// sourceBranch: Branch of the repository to fast-forward to, required by the fastForward strategy, which ignores the patch
+	SourceBranch *string `json:"sourceBranch,omitempty" cborgen:"sourceBranch,omitempty"`
+	// sourceRev: Commit the source branch is expected at, so that a fast-forward only lands what was reviewed
+	SourceRev *string `json:"sourceRev,omitempty" cborgen:"sourceRev,omitempty"`
+	// strategy: How the commits of the patch land on the branch, defaults to rebase
+	Strategy *string `json:"strategy,omitempty" cborgen:"strategy,omitempty"`
 }
 
 // RepoMerge calls the XRPC method "sh.tangled.repo.merge".
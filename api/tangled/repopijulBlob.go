@@ -28,13 +28,17 @@ type RepoPijulBlob_Output struct {
 
 // RepoPijulBlob calls the XRPC method "sh.tangled.repo.pijulBlob".
 //
+// change: Change hash to read the channel state at (defaults to the latest state)
 // channel: Pijul channel name (defaults to main channel)
 // path: Path to the file within the repository
 // repo: Repository identifier in format 'did:plc:.../repoName'
-func RepoPijulBlob(ctx context.Context, c util.LexClient, channel string, path string, repo string) (*RepoPijulBlob_Output, error) {
+func RepoPijulBlob(ctx context.Context, c util.LexClient, change string, channel string, path string, repo string) (*RepoPijulBlob_Output, error) {
 	var out RepoPijulBlob_Output
 
 	params := map[string]interface{}{}
+	if change != "" {
+		params["change"] = change
+	}
 	if channel != "" {
 		params["channel"] = channel
 	}
@@ -41,13 +41,17 @@ type RepoPijulTree_TreeEntry struct {
 
 // RepoPijulTree calls the XRPC method "sh.tangled.repo.pijulTree".
 //
+// change: Change hash to read the channel state at (defaults to the latest state)
 // channel: Pijul channel name (defaults to main channel)
 // path: Path within the repository (defaults to root)
 // repo: Repository identifier in format 'did:plc:.../repoName'
-func RepoPijulTree(ctx context.Context, c util.LexClient, channel string, path string, repo string) (*RepoPijulTree_Output, error) {
+func RepoPijulTree(ctx context.Context, c util.LexClient, change string, channel string, path string, repo string) (*RepoPijulTree_Output, error) {
 	var out RepoPijulTree_Output
 
 	params := map[string]interface{}{}
+	if change != "" {
+		params["change"] = change
+	}
 	if channel != "" {
 		params["channel"] = channel
 	}
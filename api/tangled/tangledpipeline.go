@@ -29,6 +29,19 @@ type Pipeline_CloneOpts struct {
 	Submodules bool  `json:"submodules" cborgen:"submodules"`
 }
 
+// Pipeline_DiscussionTriggerData is a "discussionTriggerData" in the sh.tangled.pipeline schema.
+type Pipeline_DiscussionTriggerData struct {
+	Action string `json:"action" cborgen:"action"`
+	// discussion: The discussion the patches belong to
+	Discussion string `json:"discussion" cborgen:"discussion"`
+	// patchHash: Hash of the change that triggered this pipeline
+	PatchHash string `json:"patchHash" cborgen:"patchHash"`
+	// patches: Hashes of the active patches of the discussion, in the order they were added
+	Patches []string `json:"patches" cborgen:"patches"`
+	// targetChannel: Pijul channel the patches are applied on top of
+	TargetChannel string `json:"targetChannel" cborgen:"targetChannel"`
+}
+
 // Pipeline_ManualTriggerData is a "manualTriggerData" in the sh.tangled.pipeline schema.
 type Pipeline_ManualTriggerData struct {
 	Inputs []*Pipeline_Pair `json:"inputs,omitempty" cborgen:"inputs,omitempty"`
@@ -57,6 +70,7 @@ type Pipeline_PushTriggerData struct {
 
 // Pipeline_TriggerMetadata is a "triggerMetadata" in the sh.tangled.pipeline schema.
 type Pipeline_TriggerMetadata struct {
+	Discussion  *Pipeline_DiscussionTriggerData  `json:"discussion,omitempty" cborgen:"discussion,omitempty"`
 	Kind        string                           `json:"kind" cborgen:"kind"`
 	Manual      *Pipeline_ManualTriggerData      `json:"manual,omitempty" cborgen:"manual,omitempty"`
 	PullRequest *Pipeline_PullRequestTriggerData `json:"pullRequest,omitempty" cborgen:"pullRequest,omitempty"`
@@ -70,6 +84,8 @@ type Pipeline_TriggerRepo struct {
 	Did           string `json:"did" cborgen:"did"`
 	Knot          string `json:"knot" cborgen:"knot"`
 	Repo          string `json:"repo" cborgen:"repo"`
+	// vcs: Version control system of the repository, git if not set
+	Vcs *string `json:"vcs,omitempty" cborgen:"vcs,omitempty"`
 }
 
 // Pipeline_Workflow is a "workflow" in the sh.tangled.pipeline schema.
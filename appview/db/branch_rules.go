@@ -0,0 +1,97 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tangled.org/core/appview/models"
+	"tangled.org/core/orm"
+)
+
+// AddBranchRule adds a branch rule to a repo, replacing an existing rule with
+// the same pattern
+func AddBranchRule(e Execer, rule *models.BranchRule) error {
+	_, err := e.Exec(
+		`insert into repo_branch_rules (repo_at, pattern, required_workflows, created)
+		values (?, ?, ?, ?)
+		on conflict(repo_at, pattern) do update set
+			required_workflows = excluded.required_workflows`,
+		rule.RepoAt.String(),
+		rule.Pattern,
+		strings.Join(rule.RequiredWorkflows, ","),
+		rule.Created.Format(time.RFC3339),
+	)
+	return err
+}
+
+func GetBranchRules(e Execer, filters ...orm.Filter) ([]models.BranchRule, error) {
+	var conditions []string
+	var args []any
+	for _, filter := range filters {
+		conditions = append(conditions, filter.Condition())
+		args = append(args, filter.Arg()...)
+	}
+
+	whereClause := ""
+	if conditions != nil {
+		whereClause = " where " + strings.Join(conditions, " and ")
+	}
+
+	query := fmt.Sprintf(`
+		select id, repo_at, pattern, required_workflows, created
+		from repo_branch_rules
+		%s
+		order by pattern asc
+		`, whereClause)
+
+	rows, err := e.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.BranchRule
+	for rows.Next() {
+		var rule models.BranchRule
+		var workflows, created string
+		if err := rows.Scan(
+			&rule.ID,
+			&rule.RepoAt,
+			&rule.Pattern,
+			&workflows,
+			&created,
+		); err != nil {
+			return nil, err
+		}
+
+		if workflows != "" {
+			rule.RequiredWorkflows = strings.Split(workflows, ",")
+		}
+
+		if t, err := time.Parse(time.RFC3339, created); err == nil {
+			rule.Created = t
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func DeleteBranchRule(e Execer, filters ...orm.Filter) error {
+	var conditions []string
+	var args []any
+	for _, filter := range filters {
+		conditions = append(conditions, filter.Condition())
+		args = append(args, filter.Arg()...)
+	}
+
+	whereClause := ""
+	if conditions != nil {
+		whereClause = " where " + strings.Join(conditions, " and ")
+	}
+
+	_, err := e.Exec(fmt.Sprintf(`delete from repo_branch_rules %s`, whereClause), args...)
+	return err
+}
@@ -1351,6 +1351,216 @@ func Make(ctx context.Context, dbPath string) (*DB, error) {
 		return err
 	})
 
+	// Patches are published as records, keyed by the pusher's did and an rkey
+	orm.RunMigration(conn, logger, "add-rkey-to-discussion-patches", func(tx *sql.Tx) error {
+		colExists, colErr := columnExists(tx, "discussion_patches", "rkey")
+		if colErr != nil {
+			return colErr
+		}
+		if !colExists {
+			if _, err := tx.Exec(`alter table discussion_patches add column rkey text;`); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(`
+			create unique index if not exists idx_discussion_patches_did_rkey on discussion_patches(pushed_by_did, rkey);
+		`)
+		return err
+	})
+
+	// Pipelines can be triggered by discussions on pijul repos, whose
+	// changes and states are not 40 character shas
+	//
+	// disable foreign-keys for the next migration
+	conn.ExecContext(ctx, "pragma foreign_keys = off;")
+	orm.RunMigration(conn, logger, "add-discussion-triggers", func(tx *sql.Tx) error {
+		for _, col := range []string{"discussion_at", "discussion_channel", "discussion_patch", "discussion_action"} {
+			colExists, colErr := columnExists(tx, "triggers", col)
+			if colErr != nil {
+				return colErr
+			}
+			if colExists {
+				continue
+			}
+			if _, err := tx.Exec(fmt.Sprintf(`alter table triggers add column %s text;`, col)); err != nil {
+				return err
+			}
+		}
+
+		// pipeline_statuses references pipelines, so both tables are copied
+		// before the old ones are dropped
+		_, err := tx.Exec(`
+		create table pipelines_new (
+			-- identifiers
+			id integer primary key autoincrement,
+			knot text not null,
+			rkey text not null,
+
+			repo_owner text not null,
+			repo_name text not null,
+
+			-- every pipeline must be associated with exactly one commit or change
+			sha text not null,
+			created text not null default (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+
+			-- trigger data
+			trigger_id integer not null,
+
+			unique(knot, rkey),
+			foreign key (trigger_id) references triggers(id) on delete cascade
+		);
+
+		insert into pipelines_new (id, knot, rkey, repo_owner, repo_name, sha, created, trigger_id)
+		select id, knot, rkey, repo_owner, repo_name, sha, created, trigger_id
+		from pipelines;
+
+		create table pipeline_statuses_new (
+			-- identifiers
+			id integer primary key autoincrement,
+			spindle text not null,
+			rkey text not null,
+
+			-- referenced pipeline. these form the (did, rkey) pair
+			pipeline_knot text not null,
+			pipeline_rkey text not null,
+
+			-- content
+			created text not null default (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+			workflow text not null,
+			status text not null,
+			error text,
+			exit_code integer not null default 0,
+
+			unique (spindle, rkey),
+			foreign key (pipeline_knot, pipeline_rkey)
+				references pipelines_new (knot, rkey)
+				on delete cascade
+		);
+
+		insert into pipeline_statuses_new (id, spindle, rkey, pipeline_knot, pipeline_rkey, created, workflow, status, error, exit_code)
+		select id, spindle, rkey, pipeline_knot, pipeline_rkey, created, workflow, status, error, exit_code
+		from pipeline_statuses;
+
+		drop table pipeline_statuses;
+		drop table pipelines;
+
+		-- renaming also updates the reference in pipeline_statuses_new
+		alter table pipelines_new rename to pipelines;
+		alter table pipeline_statuses_new rename to pipeline_statuses;
+		`)
+		return err
+	})
+	conn.ExecContext(ctx, "pragma foreign_keys = on;")
+
+	// Pull comments can start review threads anchored to lines of a round,
+	// or reply to one. Anchors are copied to every later round the thread
+	// is carried to.
+	orm.RunMigration(conn, logger, "add-pull-review-threads", func(tx *sql.Tx) error {
+		for _, def := range []string{"reply_to text", "resolved integer"} {
+			col, _, _ := strings.Cut(def, " ")
+			colExists, colErr := columnExists(tx, "pull_comments", col)
+			if colErr != nil {
+				return colErr
+			}
+			if colExists {
+				continue
+			}
+			if _, err := tx.Exec(fmt.Sprintf(`alter table pull_comments add column %s;`, def)); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.Exec(`
+		create table if not exists pull_comment_anchors (
+			-- identifiers
+			id integer primary key autoincrement,
+			comment_at text not null,
+			round_number integer not null,
+
+			-- content
+			path text not null,
+			side text not null check (side in ('old', 'new')),
+			start_line integer not null,
+			end_line integer not null,
+			outdated integer not null default 0,
+
+			unique(comment_at, round_number)
+		);
+		`)
+		return err
+	})
+
+	// Reviews are verdicts on a round of a pull, one per reviewer per round.
+	// Repos can require a number of approvals before pulls are merged.
+	orm.RunMigration(conn, logger, "add-pull-reviews", func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+		create table if not exists pull_reviews (
+			-- identifiers
+			id integer primary key autoincrement,
+			did text not null,
+			rkey text not null,
+
+			-- reviewed pull and round
+			pull_at text not null,
+			round_number integer not null,
+
+			-- content
+			state text not null check (state in ('approve', 'requestChanges', 'comment')),
+			body text not null default '',
+
+			-- meta
+			created text not null default (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+
+			unique(did, rkey),
+			unique(pull_at, round_number, did)
+		);
+
+		create table if not exists repo_pull_settings (
+			repo_at text primary key,
+			required_approvals integer not null default 0 check (required_approvals >= 0)
+		);
+		`)
+		return err
+	})
+
+	// empty strategy columns fall back to the defaults of models.PullSettings
+	orm.RunMigration(conn, logger, "add-pull-merge-strategies", func(tx *sql.Tx) error {
+		for _, def := range []string{"merge_strategies text not null default ''", "default_merge_strategy text not null default ''"} {
+			col, _, _ := strings.Cut(def, " ")
+			colExists, colErr := columnExists(tx, "repo_pull_settings", col)
+			if colErr != nil {
+				return colErr
+			}
+			if colExists {
+				continue
+			}
+			if _, err := tx.Exec(fmt.Sprintf(`alter table repo_pull_settings add column %s;`, def)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	orm.RunMigration(conn, logger, "add-repo-branch-rules", func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+		create table if not exists repo_branch_rules (
+			-- identifiers
+			id integer primary key autoincrement,
+			repo_at text not null,
+
+			-- content
+			pattern text not null,
+			required_workflows text not null default '',
+
+			-- meta
+			created text not null default (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+
+			unique(repo_at, pattern)
+		);
+		`)
+		return err
+	})
+
 	return &DB{
 		db,
 		logger,
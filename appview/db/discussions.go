@@ -15,6 +15,55 @@ import (
 	"tangled.org/core/orm"
 )
 
+// PutDiscussion creates a discussion, or updates the content of the existing
+// discussion with the same did and rkey
+func PutDiscussion(tx *sql.Tx, discussion *models.Discussion) error {
+	discussions, err := GetDiscussions(
+		tx,
+		orm.FilterEq("did", discussion.Did),
+		orm.FilterEq("rkey", discussion.Rkey),
+	)
+	switch {
+	case err != nil:
+		return err
+	case len(discussions) == 0:
+		return NewDiscussion(tx, discussion)
+	case len(discussions) != 1: // should be unreachable
+		return fmt.Errorf("invalid number of discussions returned: %d", len(discussions))
+	default:
+		existing := discussions[0]
+		discussion.Id = existing.Id
+		discussion.DiscussionId = existing.DiscussionId
+		discussion.State = existing.State
+
+		// if content is identical, do not edit
+		if existing.Title == discussion.Title &&
+			existing.Body == discussion.Body &&
+			existing.TargetChannel == discussion.TargetChannel {
+			return nil
+		}
+
+		_, err := tx.Exec(`
+			update discussions
+			set title = ?, body = ?, target_channel = ?, edited = ?
+			where did = ? and rkey = ?
+		`, discussion.Title, discussion.Body, discussion.TargetChannel, time.Now().Format(time.RFC3339), discussion.Did, discussion.Rkey)
+		return err
+	}
+}
+
+// DeleteDiscussion deletes a discussion along with its patches and comments
+func DeleteDiscussion(tx *sql.Tx, did, rkey string) error {
+	_, err := tx.Exec(`
+		delete from discussions
+		where did = ? and rkey = ?
+	`, did, rkey)
+	if err != nil {
+		return fmt.Errorf("delete discussion: %w", err)
+	}
+	return nil
+}
+
 // NewDiscussion creates a new discussion in a Pijul repository
 func NewDiscussion(tx *sql.Tx, discussion *models.Discussion) error {
 	// ensure sequence exists
@@ -40,10 +89,10 @@ func NewDiscussion(tx *sql.Tx, discussion *models.Discussion) error {
 
 	// insert new discussion
 	row := tx.QueryRow(`
-		insert into discussions (repo_at, did, rkey, discussion_id, title, body, target_channel, state)
-		values (?, ?, ?, ?, ?, ?, ?, ?)
+		insert into discussions (repo_at, did, rkey, discussion_id, title, body, target_channel, state, created)
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?)
 		returning id, discussion_id
-	`, discussion.RepoAt, discussion.Did, discussion.Rkey, newDiscussionId, discussion.Title, discussion.Body, discussion.TargetChannel, discussion.State)
+	`, discussion.RepoAt, discussion.Did, discussion.Rkey, newDiscussionId, discussion.Title, discussion.Body, discussion.TargetChannel, discussion.State, discussion.Created.Format(time.RFC3339))
 
 	err = row.Scan(&discussion.Id, &discussion.DiscussionId)
 	if err != nil {
@@ -248,16 +297,30 @@ func GetDiscussions(e Execer, filters ...orm.Filter) ([]models.Discussion, error
 
 // AddDiscussionPatch adds a patch to a discussion
 // Anyone can add patches - the key feature of the Nest model
+//
+// Patches are immutable, so adding the same record again, as when the
+// appview ingests a patch it created itself, leaves the row as it is.
 func AddDiscussionPatch(tx *sql.Tx, patch *models.DiscussionPatch) error {
 	row := tx.QueryRow(`
-		insert into discussion_patches (discussion_at, pushed_by_did, patch_hash, patch)
-		values (?, ?, ?, ?)
+		insert into discussion_patches (discussion_at, pushed_by_did, rkey, patch_hash, patch, added)
+		values (?, ?, ?, ?, ?, ?)
+		on conflict(pushed_by_did, rkey) do update set
+			rkey = discussion_patches.rkey
 		returning id
-	`, patch.DiscussionAt, patch.PushedByDid, patch.PatchHash, patch.Patch)
+	`, patch.DiscussionAt, patch.PushedByDid, patch.Rkey, patch.PatchHash, patch.Patch, patch.Added.Format(time.RFC3339))
 
 	return row.Scan(&patch.Id)
 }
 
+// DeleteDiscussionPatch deletes the patch added with the given record
+func DeleteDiscussionPatch(e Execer, did, rkey string) error {
+	_, err := e.Exec(`
+		delete from discussion_patches
+		where pushed_by_did = ? and rkey = ?
+	`, did, rkey)
+	return err
+}
+
 // PatchExists checks if a patch with the given hash already exists in the discussion
 func PatchExists(e Execer, discussionAt syntax.ATURI, patchHash string) (bool, error) {
 	var count int
@@ -271,6 +334,21 @@ func PatchExists(e Execer, discussionAt syntax.ATURI, patchHash string) (bool, e
 	return count > 0, nil
 }
 
+// PatchAddedByOtherRecord checks if a patch with the given hash was added to
+// the discussion by a record other than the given one
+func PatchAddedByOtherRecord(e Execer, discussionAt syntax.ATURI, patchHash, did, rkey string) (bool, error) {
+	var count int
+	err := e.QueryRow(`
+		select count(1) from discussion_patches
+		where discussion_at = ? and patch_hash = ?
+		and not (pushed_by_did = ? and rkey = ?)
+	`, discussionAt, patchHash, did, rkey).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // RemovePatch marks a patch as removed (soft delete)
 func RemovePatch(e Execer, patchId int64) error {
 	_, err := e.Exec(`
@@ -308,6 +386,7 @@ func GetDiscussionPatches(e Execer, filters ...orm.Filter) ([]*models.Discussion
 	query := fmt.Sprintf(`
 		select
 			id,
+			rkey,
 			discussion_at,
 			pushed_by_did,
 			patch_hash,
@@ -330,9 +409,10 @@ func GetDiscussionPatches(e Execer, filters ...orm.Filter) ([]*models.Discussion
 	for rows.Next() {
 		var patch models.DiscussionPatch
 		var addedAt string
-		var removedAt sql.Null[string]
+		var rkey, removedAt sql.Null[string]
 		err := rows.Scan(
 			&patch.Id,
+			&rkey,
 			&patch.DiscussionAt,
 			&patch.PushedByDid,
 			&patch.PatchHash,
@@ -344,6 +424,10 @@ func GetDiscussionPatches(e Execer, filters ...orm.Filter) ([]*models.Discussion
 			return nil, err
 		}
 
+		if rkey.Valid {
+			patch.Rkey = rkey.V
+		}
+
 		if t, err := time.Parse(time.RFC3339, addedAt); err == nil {
 			patch.Added = t
 		}
@@ -137,11 +137,13 @@ func GetNotificationsWithEntities(e Execer, page pagination.Page, filters ...orm
 			n.read, n.created, n.repo_id, n.issue_id, n.pull_id,
 			r.id as r_id, r.did as r_did, r.name as r_name, r.description as r_description, r.website as r_website, r.topics as r_topics,
 			i.id as i_id, i.did as i_did, i.issue_id as i_issue_id, i.title as i_title, i.open as i_open,
-			p.id as p_id, p.owner_did as p_owner_did, p.pull_id as p_pull_id, p.title as p_title, p.state as p_state
+			p.id as p_id, p.owner_did as p_owner_did, p.pull_id as p_pull_id, p.title as p_title, p.state as p_state,
+			d.id as d_id, d.did as d_did, d.discussion_id as d_discussion_id, d.title as d_title, d.state as d_state
 		from notifications n
 		left join repos r on n.repo_id = r.id
 		left join issues i on n.issue_id = i.id
 		left join pulls p on n.pull_id = p.id
+		left join discussions d on n.entity_type = 'discussion' and n.entity_id = d.at_uri
 		%s
 		order by n.created desc
 		limit ? offset ?
@@ -163,7 +165,8 @@ func GetNotificationsWithEntities(e Execer, page pagination.Page, filters ...orm
 		var repo models.Repo
 		var issue models.Issue
 		var pull models.Pull
-		var rId, iId, pId sql.NullInt64
+		var discussion models.Discussion
+		var rId, iId, pId, dId sql.NullInt64
 		var rDid, rName, rDescription, rWebsite, rTopicStr sql.NullString
 		var iDid sql.NullString
 		var iIssueId sql.NullInt64
@@ -173,6 +176,10 @@ func GetNotificationsWithEntities(e Execer, page pagination.Page, filters ...orm
 		var pPullId sql.NullInt64
 		var pTitle sql.NullString
 		var pState sql.NullInt64
+		var dDid sql.NullString
+		var dDiscussionId sql.NullInt64
+		var dTitle sql.NullString
+		var dState sql.NullInt64
 
 		err := rows.Scan(
 			&n.ID, &n.RecipientDid, &n.ActorDid, &typeStr, &n.EntityType, &n.EntityId,
@@ -180,6 +187,7 @@ func GetNotificationsWithEntities(e Execer, page pagination.Page, filters ...orm
 			&rId, &rDid, &rName, &rDescription, &rWebsite, &rTopicStr,
 			&iId, &iDid, &iIssueId, &iTitle, &iOpen,
 			&pId, &pOwnerDid, &pPullId, &pTitle, &pState,
+			&dId, &dDid, &dDiscussionId, &dTitle, &dState,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan notification with entities: %w", err)
@@ -250,6 +258,24 @@ func GetNotificationsWithEntities(e Execer, page pagination.Page, filters ...orm
 			nwe.Pull = &pull
 		}
 
+		// populate discussion if present
+		if dId.Valid {
+			discussion.Id = dId.Int64
+			if dDid.Valid {
+				discussion.Did = dDid.String
+			}
+			if dDiscussionId.Valid {
+				discussion.DiscussionId = int(dDiscussionId.Int64)
+			}
+			if dTitle.Valid {
+				discussion.Title = dTitle.String
+			}
+			if dState.Valid {
+				discussion.State = models.DiscussionState(dState.Int64)
+			}
+			nwe.Discussion = &discussion
+		}
+
 		notifications = append(notifications, nwe)
 	}
 
@@ -106,6 +106,10 @@ func AddTrigger(e Execer, trigger models.Trigger) (int64, error) {
 		trigger.PRTargetBranch,
 		trigger.PRSourceSha,
 		trigger.PRAction,
+		trigger.DiscussionAt,
+		trigger.DiscussionChannel,
+		trigger.DiscussionPatch,
+		trigger.DiscussionAction,
 	}
 
 	placeholders := make([]string, len(args))
@@ -121,7 +125,11 @@ func AddTrigger(e Execer, trigger models.Trigger) (int64, error) {
 		pr_source_branch,
 		pr_target_branch,
 		pr_source_sha,
-		pr_action
+		pr_action,
+		discussion_at,
+		discussion_channel,
+		discussion_patch,
+		discussion_action
 	) values (%s)`, strings.Join(placeholders, ","))
 
 	res, err := e.Exec(query, args...)
@@ -203,7 +211,11 @@ func GetPipelineStatuses(e Execer, limit int, filters ...orm.Filter) ([]models.P
 			t.pr_source_branch,
 			t.pr_target_branch,
 			t.pr_source_sha,
-			t.pr_action
+			t.pr_action,
+			t.discussion_at,
+			t.discussion_channel,
+			t.discussion_patch,
+			t.discussion_action
 		from
 			pipelines p
 		join
@@ -242,6 +254,10 @@ func GetPipelineStatuses(e Execer, limit int, filters ...orm.Filter) ([]models.P
 			&t.PRTargetBranch,
 			&t.PRSourceSha,
 			&t.PRAction,
+			&t.DiscussionAt,
+			&t.DiscussionChannel,
+			&t.DiscussionPatch,
+			&t.DiscussionAction,
 		)
 		if err != nil {
 			return nil, err
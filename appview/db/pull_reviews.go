@@ -0,0 +1,147 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"tangled.org/core/appview/models"
+	"tangled.org/core/orm"
+	"tangled.org/core/types"
+)
+
+// PutPullReview records a review, replacing the reviewer's earlier review of
+// the same round
+func PutPullReview(e Execer, review *models.PullReview) error {
+	_, err := e.Exec(
+		`insert into pull_reviews (did, rkey, pull_at, round_number, state, body, created)
+		values (?, ?, ?, ?, ?, ?, ?)
+		on conflict(did, rkey) do update set
+			state = excluded.state,
+			body = excluded.body,
+			created = excluded.created`,
+		review.Did,
+		review.Rkey,
+		review.PullAt.String(),
+		review.Round,
+		review.State,
+		review.Body,
+		review.Created.Format(time.RFC3339),
+	)
+	return err
+}
+
+func GetPullReviews(e Execer, filters ...orm.Filter) ([]models.PullReview, error) {
+	var conditions []string
+	var args []any
+	for _, filter := range filters {
+		conditions = append(conditions, filter.Condition())
+		args = append(args, filter.Arg()...)
+	}
+
+	whereClause := ""
+	if conditions != nil {
+		whereClause = " where " + strings.Join(conditions, " and ")
+	}
+
+	query := fmt.Sprintf(`
+		select id, did, rkey, pull_at, round_number, state, body, created
+		from pull_reviews
+		%s
+		order by created asc
+		`, whereClause)
+
+	rows, err := e.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []models.PullReview
+	for rows.Next() {
+		var review models.PullReview
+		var created string
+		if err := rows.Scan(
+			&review.ID,
+			&review.Did,
+			&review.Rkey,
+			&review.PullAt,
+			&review.Round,
+			&review.State,
+			&review.Body,
+			&created,
+		); err != nil {
+			return nil, err
+		}
+
+		if t, err := time.Parse(time.RFC3339, created); err == nil {
+			review.Created = t
+		}
+
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+// GetPullSettings returns the pull settings of a repo, or the defaults if the
+// repo has none
+func GetPullSettings(e Execer, repoAt syntax.ATURI) (models.PullSettings, error) {
+	settings := models.DefaultPullSettings(repoAt)
+
+	var strategies, defaultStrategy string
+	err := e.QueryRow(
+		`select required_approvals, merge_strategies, default_merge_strategy
+		from repo_pull_settings
+		where repo_at = ?`,
+		repoAt.String(),
+	).Scan(&settings.RequiredApprovals, &strategies, &defaultStrategy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+
+	// strategies that were dropped since the settings were saved are skipped
+	var allowed []types.MergeStrategy
+	for s := range strings.SplitSeq(strategies, ",") {
+		if strategy := types.MergeStrategy(s); strategy.IsValid() {
+			allowed = append(allowed, strategy)
+		}
+	}
+	if len(allowed) > 0 {
+		settings.MergeStrategies = allowed
+	}
+	if strategy := types.MergeStrategy(defaultStrategy); strategy.IsValid() && settings.AllowsMergeStrategy(strategy) {
+		settings.DefaultMergeStrategy = strategy
+	} else if len(settings.MergeStrategies) > 0 {
+		settings.DefaultMergeStrategy = settings.MergeStrategies[0]
+	}
+
+	return settings, nil
+}
+
+func SetPullSettings(e Execer, settings models.PullSettings) error {
+	var strategies []string
+	for _, s := range settings.MergeStrategies {
+		strategies = append(strategies, string(s))
+	}
+
+	_, err := e.Exec(
+		`insert into repo_pull_settings (repo_at, required_approvals, merge_strategies, default_merge_strategy)
+		values (?, ?, ?, ?)
+		on conflict(repo_at) do update set
+			required_approvals = excluded.required_approvals,
+			merge_strategies = excluded.merge_strategies,
+			default_merge_strategy = excluded.default_merge_strategy`,
+		settings.RepoAt.String(),
+		settings.RequiredApprovals,
+		strings.Join(strategies, ","),
+		string(settings.DefaultMergeStrategy),
+	)
+	return err
+}
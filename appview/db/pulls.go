@@ -441,6 +441,8 @@ func GetPullComments(e Execer, filters ...orm.Filter) ([]models.PullComment, err
 			owner_did,
 			comment_at,
 			body,
+			reply_to,
+			resolved,
 			created
 		from
 			pull_comments
@@ -459,6 +461,8 @@ func GetPullComments(e Execer, filters ...orm.Filter) ([]models.PullComment, err
 	for rows.Next() {
 		var comment models.PullComment
 		var createdAt string
+		var replyTo sql.NullString
+		var resolved sql.NullBool
 		err := rows.Scan(
 			&comment.ID,
 			&comment.PullId,
@@ -467,12 +471,19 @@ func GetPullComments(e Execer, filters ...orm.Filter) ([]models.PullComment, err
 			&comment.OwnerDid,
 			&comment.CommentAt,
 			&comment.Body,
+			&replyTo,
+			&resolved,
 			&createdAt,
 		)
 		if err != nil {
 			return nil, err
 		}
 
+		comment.ReplyTo = replyTo.String
+		if resolved.Valid {
+			comment.Resolved = &resolved.Bool
+		}
+
 		if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
 			comment.Created = t
 		}
@@ -497,6 +508,16 @@ func GetPullComments(e Execer, filters ...orm.Filter) ([]models.PullComment, err
 		}
 	}
 
+	anchors, err := GetPullCommentAnchors(e, orm.FilterIn("comment_at", commentAts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pull_comment_anchors: %w", err)
+	}
+	for commentAt, a := range anchors {
+		if comment, ok := commentMap[commentAt]; ok {
+			comment.Anchors = a
+		}
+	}
+
 	var comments []models.PullComment
 	for _, c := range commentMap {
 		comments = append(comments, *c)
@@ -586,7 +607,12 @@ func GetPullsByOwnerDid(e Execer, did, timeframe string) ([]models.Pull, error)
 }
 
 func NewPullComment(tx *sql.Tx, comment *models.PullComment) (int64, error) {
-	query := `insert into pull_comments (owner_did, repo_at, submission_id, comment_at, pull_id, body) values (?, ?, ?, ?, ?, ?)`
+	var replyTo *string
+	if comment.ReplyTo != "" {
+		replyTo = &comment.ReplyTo
+	}
+
+	query := `insert into pull_comments (owner_did, repo_at, submission_id, comment_at, pull_id, body, reply_to, resolved) values (?, ?, ?, ?, ?, ?, ?, ?)`
 	res, err := tx.Exec(
 		query,
 		comment.OwnerDid,
@@ -595,6 +621,8 @@ func NewPullComment(tx *sql.Tx, comment *models.PullComment) (int64, error) {
 		comment.CommentAt,
 		comment.PullId,
 		comment.Body,
+		replyTo,
+		comment.Resolved,
 	)
 	if err != nil {
 		return 0, err
@@ -609,9 +637,76 @@ func NewPullComment(tx *sql.Tx, comment *models.PullComment) (int64, error) {
 		return 0, fmt.Errorf("put reference_links: %w", err)
 	}
 
+	if err := AddPullCommentAnchors(tx, comment.CommentAt, comment.Anchors...); err != nil {
+		return 0, fmt.Errorf("put pull_comment_anchors: %w", err)
+	}
+
 	return i, nil
 }
 
+// AddPullCommentAnchors anchors the review thread started by a comment to
+// lines of one or more rounds
+func AddPullCommentAnchors(e Execer, commentAt string, anchors ...models.PullCommentAnchor) error {
+	for _, a := range anchors {
+		_, err := e.Exec(
+			`insert or replace into pull_comment_anchors (comment_at, round_number, path, side, start_line, end_line, outdated)
+			values (?, ?, ?, ?, ?, ?, ?)`,
+			commentAt,
+			a.Round,
+			a.Path,
+			a.Side,
+			a.StartLine,
+			a.EndLine,
+			a.Outdated,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPullCommentAnchors returns the anchors of review threads, keyed by the
+// at-uri of the comment that started each thread and ordered by round
+func GetPullCommentAnchors(e Execer, filters ...orm.Filter) (map[string][]models.PullCommentAnchor, error) {
+	var conditions []string
+	var args []any
+	for _, filter := range filters {
+		conditions = append(conditions, filter.Condition())
+		args = append(args, filter.Arg()...)
+	}
+
+	whereClause := ""
+	if conditions != nil {
+		whereClause = " where " + strings.Join(conditions, " and ")
+	}
+
+	query := fmt.Sprintf(`
+		select comment_at, round_number, path, side, start_line, end_line, outdated
+		from pull_comment_anchors
+		%s
+		order by round_number asc
+		`, whereClause)
+
+	rows, err := e.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	anchors := make(map[string][]models.PullCommentAnchor)
+	for rows.Next() {
+		var commentAt string
+		var a models.PullCommentAnchor
+		if err := rows.Scan(&commentAt, &a.Round, &a.Path, &a.Side, &a.StartLine, &a.EndLine, &a.Outdated); err != nil {
+			return nil, err
+		}
+		anchors[commentAt] = append(anchors[commentAt], a)
+	}
+
+	return anchors, rows.Err()
+}
+
 func SetPullState(e Execer, repoAt syntax.ATURI, pullId int, pullState models.PullState) error {
 	_, err := e.Exec(
 		`update pulls set state = ? where repo_at = ? and pull_id = ? and (state <> ? or state <> ?)`,
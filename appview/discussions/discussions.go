@@ -2,18 +2,25 @@ package discussions
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	comatproto "github.com/bluesky-social/indigo/api/atproto"
+	atpclient "github.com/bluesky-social/indigo/atproto/client"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	lexutil "github.com/bluesky-social/indigo/lex/util"
 	"github.com/bluesky-social/indigo/xrpc"
 	"github.com/go-chi/chi/v5"
 
 	tangled "tangled.org/core/api/tangled"
 	"tangled.org/core/appview/config"
 	"tangled.org/core/appview/db"
+	discussions_indexer "tangled.org/core/appview/indexer/discussions"
 	"tangled.org/core/appview/mentions"
 	"tangled.org/core/appview/models"
 	"tangled.org/core/appview/notify"
@@ -22,10 +29,13 @@ import (
 	"tangled.org/core/appview/pagination"
 	"tangled.org/core/appview/reporesolver"
 	"tangled.org/core/appview/validator"
+	"tangled.org/core/appview/xrpcclient"
 	"tangled.org/core/idresolver"
 	"tangled.org/core/orm"
+	"tangled.org/core/patchutil"
 	"tangled.org/core/rbac"
 	"tangled.org/core/tid"
+	"tangled.org/core/types"
 )
 
 // Discussions handles the discussions feature for Pijul repositories
@@ -41,6 +51,7 @@ type Discussions struct {
 	notifier         notify.Notifier
 	logger           *slog.Logger
 	validator        *validator.Validator
+	indexer          *discussions_indexer.Indexer
 }
 
 func New(
@@ -54,6 +65,7 @@ func New(
 	config *config.Config,
 	notifier notify.Notifier,
 	validator *validator.Validator,
+	indexer *discussions_indexer.Indexer,
 	logger *slog.Logger,
 ) *Discussions {
 	return &Discussions{
@@ -68,6 +80,7 @@ func New(
 		notifier:         notifier,
 		logger:           logger,
 		validator:        validator,
+		indexer:          indexer,
 	}
 }
 
@@ -92,31 +105,83 @@ func (d *Discussions) RepoDiscussionsList(w http.ResponseWriter, r *http.Request
 
 	repoAt := repo.RepoAt()
 	page := pagination.Page{Limit: 50}
+	params := r.URL.Query()
 
 	// Filter by state
-	filter := r.URL.Query().Get("filter")
-	filters := []orm.Filter{orm.FilterEq("repo_at", repoAt)}
+	filter := params.Get("filter")
+	state := models.DiscussionOpen
 	switch filter {
 	case "closed":
-		filters = append(filters, orm.FilterEq("state", models.DiscussionClosed))
+		state = models.DiscussionClosed
 	case "merged":
-		filters = append(filters, orm.FilterEq("state", models.DiscussionMerged))
+		state = models.DiscussionMerged
 	default:
 		// Default to open
-		filters = append(filters, orm.FilterEq("state", models.DiscussionOpen))
 		filter = "open"
 	}
 
-	discussions, err := db.GetDiscussionsPaginated(d.db, page, filters...)
-	if err != nil {
-		l.Error("failed to fetch discussions", "err", err)
-		d.pages.Error503(w)
-		return
-	}
+	keyword := params.Get("q")
+	labels := params["label"]
 
-	count, err := db.GetDiscussionCount(d.db, repoAt)
-	if err != nil {
-		l.Error("failed to get discussion count", "err", err)
+	var discussions []models.Discussion
+	var count models.DiscussionCount
+	if keyword != "" || len(labels) > 0 {
+		searchOpts := models.DiscussionSearchOptions{
+			Keyword: keyword,
+			RepoAt:  repoAt.String(),
+			State:   state,
+			Labels:  labels,
+			Page:    page,
+		}
+		res, err := d.indexer.Search(r.Context(), searchOpts)
+		if err != nil {
+			l.Error("failed to search for discussions", "err", err)
+			d.pages.Error503(w)
+			return
+		}
+		l.Debug("searched discussions with indexer", "count", len(res.Hits))
+
+		// count matching discussions in the other states to display correct counts
+		totals := map[models.DiscussionState]int{state: int(res.Total)}
+		for _, s := range []models.DiscussionState{models.DiscussionOpen, models.DiscussionMerged, models.DiscussionClosed} {
+			if s == state {
+				continue
+			}
+			searchOpts.State = s
+			searchOpts.Page = pagination.Page{Limit: 1}
+			if countRes, err := d.indexer.Search(r.Context(), searchOpts); err == nil {
+				totals[s] = int(countRes.Total)
+			}
+		}
+		count = models.DiscussionCount{
+			Open:   totals[models.DiscussionOpen],
+			Merged: totals[models.DiscussionMerged],
+			Closed: totals[models.DiscussionClosed],
+		}
+
+		discussions, err = db.GetDiscussions(d.db, orm.FilterIn("id", res.Hits))
+		if err != nil {
+			l.Error("failed to fetch discussions", "err", err)
+			d.pages.Error503(w)
+			return
+		}
+	} else {
+		discussions, err = db.GetDiscussionsPaginated(
+			d.db,
+			page,
+			orm.FilterEq("repo_at", repoAt),
+			orm.FilterEq("state", state),
+		)
+		if err != nil {
+			l.Error("failed to fetch discussions", "err", err)
+			d.pages.Error503(w)
+			return
+		}
+
+		count, err = db.GetDiscussionCount(d.db, repoAt)
+		if err != nil {
+			l.Error("failed to get discussion count", "err", err)
+		}
 	}
 
 	d.pages.RepoDiscussionsList(w, pages.RepoDiscussionsListParams{
@@ -124,6 +189,8 @@ func (d *Discussions) RepoDiscussionsList(w http.ResponseWriter, r *http.Request
 		RepoInfo:        d.repoResolver.GetRepoInfo(r, user),
 		Discussions:     discussions,
 		Filter:          filter,
+		FilterQuery:     keyword,
+		FilterLabels:    labels,
 		DiscussionCount: count,
 	})
 }
@@ -150,9 +217,15 @@ func (d *Discussions) NewDiscussion(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodGet:
+		targetChannel := r.URL.Query().Get("target_channel")
+		if targetChannel == "" {
+			targetChannel = d.defaultChannel(r, repo)
+		}
 		d.pages.NewDiscussion(w, pages.NewDiscussionParams{
-			LoggedInUser: user,
-			RepoInfo:     repoInfo,
+			LoggedInUser:  user,
+			RepoInfo:      repoInfo,
+			TargetChannel: targetChannel,
+			SourceChannel: r.URL.Query().Get("channel"),
 		})
 
 	case http.MethodPost:
@@ -162,7 +235,7 @@ func (d *Discussions) NewDiscussion(w http.ResponseWriter, r *http.Request) {
 		body := r.FormValue("body")
 		targetChannel := r.FormValue("target_channel")
 		if targetChannel == "" {
-			targetChannel = "main"
+			targetChannel = d.defaultChannel(r, repo)
 		}
 
 		if title == "" {
@@ -170,6 +243,8 @@ func (d *Discussions) NewDiscussion(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		mentions, references := d.mentionsResolver.Resolve(r.Context(), body)
+
 		discussion := &models.Discussion{
 			Did:           user.Active.Did,
 			Rkey:          tid.TID(),
@@ -179,8 +254,44 @@ func (d *Discussions) NewDiscussion(w http.ResponseWriter, r *http.Request) {
 			TargetChannel: targetChannel,
 			State:         models.DiscussionOpen,
 			Created:       time.Now(),
+			Mentions:      mentions,
+			References:    references,
+		}
+		if err := d.validator.ValidateDiscussion(discussion); err != nil {
+			l.Error("validation error", "err", err)
+			d.pages.Notice(w, noticeId, fmt.Sprintf("Failed to create discussion: %s", err))
+			return
+		}
+		record := discussion.AsRecord()
+
+		client, err := d.oauth.AuthorizedClient(r)
+		if err != nil {
+			l.Error("failed to get authorized client", "err", err)
+			d.pages.Notice(w, noticeId, "Failed to create discussion")
+			return
 		}
 
+		// create a record first
+		resp, err := comatproto.RepoPutRecord(r.Context(), client, &comatproto.RepoPutRecord_Input{
+			Collection: tangled.RepoDiscussionNSID,
+			Repo:       user.Active.Did,
+			Rkey:       discussion.Rkey,
+			Record: &lexutil.LexiconTypeDecoder{
+				Val: &record,
+			},
+		})
+		if err != nil {
+			l.Error("failed to create discussion record", "err", err)
+			d.pages.Notice(w, noticeId, "Failed to create discussion")
+			return
+		}
+		atUri := resp.Uri
+		defer func() {
+			if err := xrpcclient.RollbackRecord(context.Background(), atUri, client); err != nil {
+				l.Error("rollback failed", "err", err)
+			}
+		}()
+
 		tx, err := d.db.BeginTx(r.Context(), nil)
 		if err != nil {
 			l.Error("failed to begin transaction", "err", err)
@@ -189,7 +300,7 @@ func (d *Discussions) NewDiscussion(w http.ResponseWriter, r *http.Request) {
 		}
 		defer tx.Rollback()
 
-		if err := db.NewDiscussion(tx, discussion); err != nil {
+		if err := db.PutDiscussion(tx, discussion); err != nil {
 			l.Error("failed to create discussion", "err", err)
 			d.pages.Notice(w, noticeId, "Failed to create discussion")
 			return
@@ -201,11 +312,24 @@ func (d *Discussions) NewDiscussion(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// reset atUri to make rollback a no-op
+		atUri = ""
+
 		// Subscribe the creator to the discussion
 		db.SubscribeToDiscussion(d.db, discussion.AtUri(), user.Active.Did)
 
+		d.notifier.NewDiscussion(r.Context(), discussion, mentions)
+
 		l.Info("discussion created", "discussion_id", discussion.DiscussionId)
 
+		// opened from a comparison, propose the changes of the compared
+		// channel right away
+		if sourceChannel := r.FormValue("source_channel"); sourceChannel != "" {
+			if err := d.addChannelPatches(r.Context(), client, repo, discussion, user.Active.Did, sourceChannel); err != nil {
+				l.Error("failed to add channel patches", "channel", sourceChannel, "err", err)
+			}
+		}
+
 		d.pages.HxLocation(w, fmt.Sprintf("/%s/%s/discussions/%d",
 			user.Active.Did, repo.Name, discussion.DiscussionId))
 	}
@@ -237,16 +361,134 @@ func (d *Discussions) RepoSingleDiscussion(w http.ResponseWriter, r *http.Reques
 		}
 	}
 
+	var applyCheck types.MergeCheckResponse
+	var channelConflicts []types.ConflictInfo
+	if discussion.State.IsOpen() {
+		if repo, err := d.repoResolver.Resolve(r); err == nil {
+			// the knot only runs apply checks for those who can apply
+			if canManage && len(discussion.ActivePatches()) > 0 {
+				applyCheck = d.applyCheck(r, repo, discussion)
+			}
+			channelConflicts = d.channelConflicts(r, repo, discussion.TargetChannel)
+		}
+	}
+
+	pipelines := make(map[string]models.Pipeline)
+	if len(discussion.Patches) > 0 {
+		var hashes []string
+		for _, p := range discussion.Patches {
+			hashes = append(hashes, p.PatchHash)
+		}
+
+		ps, err := db.GetPipelineStatuses(
+			d.db,
+			len(hashes),
+			orm.FilterEq("p.repo_owner", repoInfo.OwnerDid),
+			orm.FilterEq("p.repo_name", repoInfo.Name),
+			orm.FilterEq("p.knot", repoInfo.Knot),
+			orm.FilterEq("t.discussion_at", discussion.AtUri()),
+			orm.FilterIn("p.sha", hashes),
+		)
+		if err != nil {
+			l.Error("failed to fetch pipeline statuses", "err", err)
+			// non-fatal
+		}
+
+		// pipelines are ordered newest first, keep the latest run of each patch
+		for _, p := range ps {
+			if _, ok := pipelines[p.Sha]; !ok {
+				pipelines[p.Sha] = p
+			}
+		}
+	}
+
 	d.pages.RepoSingleDiscussion(w, pages.RepoSingleDiscussionParams{
-		LoggedInUser:  user,
-		RepoInfo:      repoInfo,
-		Discussion:    discussion,
-		CommentList:   discussion.CommentList(),
-		CanManage:     canManage,
-		ActivePatches: discussion.ActivePatches(),
+		LoggedInUser:     user,
+		RepoInfo:         repoInfo,
+		Discussion:       discussion,
+		CommentList:      discussion.CommentList(),
+		CanManage:        canManage,
+		ActivePatches:    discussion.ActivePatches(),
+		ApplyCheck:       applyCheck,
+		ChannelConflicts: channelConflicts,
+		Pipelines:        pipelines,
+	})
+}
+
+// RepoDiscussionInterdiff shows what changed between two patches of a
+// discussion, typically a patch and the one that replaced it
+func (d *Discussions) RepoDiscussionInterdiff(w http.ResponseWriter, r *http.Request) {
+	l := d.logger.With("handler", "RepoDiscussionInterdiff")
+	user := d.oauth.GetMultiAccountUser(r)
+
+	discussion, ok := r.Context().Value("discussion").(*models.Discussion)
+	if !ok {
+		l.Error("failed to get discussion from context")
+		d.pages.Error404(w)
+		return
+	}
+
+	from := discussionPatchById(discussion, r.URL.Query().Get("from"))
+	to := discussionPatchById(discussion, r.URL.Query().Get("to"))
+	if from == nil || to == nil {
+		http.Error(w, "bad patch id", http.StatusBadRequest)
+		return
+	}
+
+	repo, err := d.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to resolve repo", "err", err)
+		d.pages.Error503(w)
+		return
+	}
+
+	repoIdentifier := fmt.Sprintf("%s/%s", repo.Did, repo.Name)
+	fromChange, err := d.getChangeFromKnot(r.Context(), repo.Knot, repoIdentifier, from.PatchHash)
+	if err != nil {
+		l.Error("failed to get change from knot", "hash", from.PatchHash, "err", err)
+		d.pages.Error503(w)
+		return
+	}
+	toChange, err := d.getChangeFromKnot(r.Context(), repo.Knot, repoIdentifier, to.PatchHash)
+	if err != nil {
+		l.Error("failed to get change from knot", "hash", to.PatchHash, "err", err)
+		d.pages.Error503(w)
+		return
+	}
+
+	var diffOpts types.DiffOpts
+	if r.URL.Query().Get("diff") == "split" {
+		diffOpts.Split = true
+	}
+
+	interdiff := patchutil.PijulInterdiff(patchutil.FromPijulChange(fromChange), patchutil.FromPijulChange(toChange))
+
+	d.pages.RepoDiscussionInterdiff(w, pages.RepoDiscussionInterdiffParams{
+		LoggedInUser: user,
+		RepoInfo:     d.repoResolver.GetRepoInfo(r, user),
+		Discussion:   discussion,
+		From:         from,
+		To:           to,
+		Interdiff:    interdiff,
+		DiffOpts:     diffOpts,
 	})
 }
 
+// discussionPatchById finds a patch of the discussion by its id, removed
+// patches included
+func discussionPatchById(discussion *models.Discussion, id string) *models.DiscussionPatch {
+	patchId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil
+	}
+	for _, p := range discussion.Patches {
+		if p.Id == patchId {
+			return p
+		}
+	}
+	return nil
+}
+
 // AddPatch allows anyone to add a patch to a discussion
 func (d *Discussions) AddPatch(w http.ResponseWriter, r *http.Request) {
 	l := d.logger.With("handler", "AddPatch")
@@ -312,42 +554,117 @@ func (d *Discussions) AddPatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	client, err := d.oauth.AuthorizedClient(r)
+	if err != nil {
+		l.Error("failed to get authorized client", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to add patch")
+		return
+	}
+
+	if _, err := d.putPatch(r.Context(), client, discussion, user.Active.Did, patchHash, patch); err != nil {
+		l.Error("failed to add patch", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to add patch")
+		return
+	}
+
+	l.Info("patch added", "patch_hash", patchHash, "pushed_by", user.Active.Did)
+
+	// Reload the page to show the new patch
+	d.pages.HxLocation(w, fmt.Sprintf("/%s/%s/discussions/%d",
+		repo.Did, repo.Name, discussion.DiscussionId))
+}
+
+// putPatch records a patch on a discussion, both on the PDS of the user who
+// pushed it and in the database, and subscribes them to the discussion
+func (d *Discussions) putPatch(ctx context.Context, client *atpclient.APIClient, discussion *models.Discussion, did, patchHash, patch string) (*models.DiscussionPatch, error) {
 	discussionPatch := &models.DiscussionPatch{
+		Rkey:         tid.TID(),
 		DiscussionAt: discussion.AtUri(),
-		PushedByDid:  user.Active.Did,
+		PushedByDid:  did,
 		PatchHash:    patchHash,
 		Patch:        patch,
 		Added:        time.Now(),
 	}
+	record := discussionPatch.AsRecord()
 
-	tx, err := d.db.BeginTx(r.Context(), nil)
+	resp, err := comatproto.RepoPutRecord(ctx, client, &comatproto.RepoPutRecord_Input{
+		Collection: tangled.RepoDiscussionPatchNSID,
+		Repo:       did,
+		Rkey:       discussionPatch.Rkey,
+		Record: &lexutil.LexiconTypeDecoder{
+			Val: &record,
+		},
+	})
 	if err != nil {
-		l.Error("failed to begin transaction", "err", err)
-		d.pages.Notice(w, noticeId, "Failed to add patch")
-		return
+		return nil, fmt.Errorf("creating patch record: %w", err)
+	}
+	atUri := resp.Uri
+	defer func() {
+		if err := xrpcclient.RollbackRecord(context.Background(), atUri, client); err != nil {
+			d.logger.Error("rollback failed", "err", err)
+		}
+	}()
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
 	}
 	defer tx.Rollback()
 
 	if err := db.AddDiscussionPatch(tx, discussionPatch); err != nil {
-		l.Error("failed to add patch", "err", err)
-		d.pages.Notice(w, noticeId, "Failed to add patch")
-		return
+		return nil, err
 	}
 
 	if err := tx.Commit(); err != nil {
-		l.Error("failed to commit transaction", "err", err)
-		d.pages.Notice(w, noticeId, "Failed to add patch")
-		return
+		return nil, err
 	}
 
+	// reset atUri to make rollback a no-op
+	atUri = ""
+
 	// Subscribe the patch contributor to the discussion
-	db.SubscribeToDiscussion(d.db, discussion.AtUri(), user.Active.Did)
+	db.SubscribeToDiscussion(d.db, discussion.AtUri(), did)
 
-	l.Info("patch added", "patch_hash", patchHash, "pushed_by", user.Active.Did)
+	d.notifier.NewDiscussionPatch(ctx, discussionPatch)
 
-	// Reload the page to show the new patch
-	d.pages.HxLocation(w, fmt.Sprintf("/%s/%s/discussions/%d",
-		repo.Did, repo.Name, discussion.DiscussionId))
+	return discussionPatch, nil
+}
+
+// addChannelPatches adds the changes of a channel that the target channel of
+// a discussion lacks as patches of the discussion, oldest first
+func (d *Discussions) addChannelPatches(ctx context.Context, client *atpclient.APIClient, repo *models.Repo, discussion *models.Discussion, did, channel string) error {
+	scheme := "http"
+	if d.config.Core.UseTLS() {
+		scheme = "https"
+	}
+
+	xrpcc := &xrpc.Client{
+		Host: fmt.Sprintf("%s://%s", scheme, repo.Knot),
+	}
+
+	repoIdentifier := fmt.Sprintf("%s/%s", repo.Did, repo.Name)
+	comparison, err := tangled.RepoCompareChannels(ctx, xrpcc, discussion.TargetChannel, channel, repoIdentifier)
+	if err := xrpcclient.HandleXrpcErr(err); err != nil {
+		return fmt.Errorf("comparing channels: %w", err)
+	}
+
+	for _, entry := range comparison.Ahead {
+		change, err := d.getChangeFromKnot(ctx, repo.Knot, repoIdentifier, entry.Hash)
+		if err != nil {
+			return fmt.Errorf("fetching change %s: %w", entry.Hash, err)
+		}
+
+		patch := change.Message
+		if change.Diff != nil && *change.Diff != "" {
+			patch = *change.Diff
+		}
+
+		if _, err := d.putPatch(ctx, client, discussion, did, change.Hash, patch); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // RemovePatch removes a patch from a discussion (soft delete)
@@ -411,9 +728,34 @@ func (d *Discussions) RemovePatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	client, err := d.oauth.AuthorizedClient(r)
+	if err != nil {
+		l.Error("failed to get authorized client", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to remove patch")
+		return
+	}
+
+	resp, err := comatproto.RepoPutRecord(r.Context(), client, &comatproto.RepoPutRecord_Input{
+		Collection: tangled.RepoDiscussionPatchStateNSID,
+		Repo:       user.Active.Did,
+		Rkey:       tid.TID(),
+		Record: &lexutil.LexiconTypeDecoder{
+			Val: patchStateRecord(patch, models.DiscussionPatchRemoved),
+		},
+	})
+	if err != nil {
+		l.Error("failed to create patch state record", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to remove patch")
+		return
+	}
+	atUri := resp.Uri
+
 	if err := db.RemovePatch(d.db, patchId); err != nil {
 		l.Error("failed to remove patch", "err", err)
 		d.pages.Notice(w, noticeId, "Failed to remove patch")
+		if err := xrpcclient.RollbackRecord(context.Background(), atUri, client); err != nil {
+			l.Error("rollback failed", "err", err)
+		}
 		return
 	}
 
@@ -468,9 +810,34 @@ func (d *Discussions) ReaddPatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	client, err := d.oauth.AuthorizedClient(r)
+	if err != nil {
+		l.Error("failed to get authorized client", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to re-add patch")
+		return
+	}
+
+	resp, err := comatproto.RepoPutRecord(r.Context(), client, &comatproto.RepoPutRecord_Input{
+		Collection: tangled.RepoDiscussionPatchStateNSID,
+		Repo:       user.Active.Did,
+		Rkey:       tid.TID(),
+		Record: &lexutil.LexiconTypeDecoder{
+			Val: patchStateRecord(patch, models.DiscussionPatchActive),
+		},
+	})
+	if err != nil {
+		l.Error("failed to create patch state record", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to re-add patch")
+		return
+	}
+	atUri := resp.Uri
+
 	if err := db.ReaddPatch(d.db, patchId); err != nil {
 		l.Error("failed to re-add patch", "err", err)
 		d.pages.Notice(w, noticeId, "Failed to re-add patch")
+		if err := xrpcclient.RollbackRecord(context.Background(), atUri, client); err != nil {
+			l.Error("rollback failed", "err", err)
+		}
 		return
 	}
 
@@ -502,18 +869,57 @@ func (d *Discussions) NewComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	mentions, references := d.mentionsResolver.Resolve(r.Context(), body)
+
 	comment := models.DiscussionComment{
 		Did:          user.Active.Did,
 		Rkey:         tid.TID(),
 		DiscussionAt: discussion.AtUri().String(),
 		Body:         body,
 		Created:      time.Now(),
+		Mentions:     mentions,
+		References:   references,
 	}
 
 	if replyTo != "" {
 		comment.ReplyTo = &replyTo
 	}
 
+	if err := d.validator.ValidateDiscussionComment(&comment); err != nil {
+		l.Error("failed to validate comment", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to add comment")
+		return
+	}
+	record := comment.AsRecord()
+
+	client, err := d.oauth.AuthorizedClient(r)
+	if err != nil {
+		l.Error("failed to get authorized client", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to add comment")
+		return
+	}
+
+	// create a record first
+	resp, err := comatproto.RepoPutRecord(r.Context(), client, &comatproto.RepoPutRecord_Input{
+		Collection: tangled.RepoDiscussionCommentNSID,
+		Repo:       user.Active.Did,
+		Rkey:       comment.Rkey,
+		Record: &lexutil.LexiconTypeDecoder{
+			Val: &record,
+		},
+	})
+	if err != nil {
+		l.Error("failed to create comment record", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to add comment")
+		return
+	}
+	atUri := resp.Uri
+	defer func() {
+		if err := xrpcclient.RollbackRecord(context.Background(), atUri, client); err != nil {
+			l.Error("rollback failed", "err", err)
+		}
+	}()
+
 	tx, err := d.db.BeginTx(r.Context(), nil)
 	if err != nil {
 		l.Error("failed to begin transaction", "err", err)
@@ -534,9 +940,14 @@ func (d *Discussions) NewComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// reset atUri to make rollback a no-op
+	atUri = ""
+
 	// Subscribe the commenter to the discussion
 	db.SubscribeToDiscussion(d.db, discussion.AtUri(), user.Active.Did)
 
+	d.notifier.NewDiscussionComment(r.Context(), &comment, mentions)
+
 	l.Info("comment added", "discussion_id", discussion.DiscussionId)
 
 	repo, _ := d.repoResolver.Resolve(r)
@@ -575,12 +986,40 @@ func (d *Discussions) CloseDiscussion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	client, err := d.oauth.AuthorizedClient(r)
+	if err != nil {
+		l.Error("failed to get authorized client", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to close discussion")
+		return
+	}
+
+	resp, err := comatproto.RepoPutRecord(r.Context(), client, &comatproto.RepoPutRecord_Input{
+		Collection: tangled.RepoDiscussionStateNSID,
+		Repo:       user.Active.Did,
+		Rkey:       tid.TID(),
+		Record: &lexutil.LexiconTypeDecoder{
+			Val: stateRecord(discussion, models.DiscussionClosed),
+		},
+	})
+	if err != nil {
+		l.Error("failed to create state record", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to close discussion")
+		return
+	}
+	atUri := resp.Uri
+
 	if err := db.CloseDiscussion(d.db, discussion.RepoAt, discussion.DiscussionId); err != nil {
 		l.Error("failed to close discussion", "err", err)
 		d.pages.Notice(w, noticeId, "Failed to close discussion")
+		if err := xrpcclient.RollbackRecord(context.Background(), atUri, client); err != nil {
+			l.Error("rollback failed", "err", err)
+		}
 		return
 	}
 
+	discussion.State = models.DiscussionClosed
+	d.notifier.NewDiscussionState(r.Context(), syntax.DID(user.Active.Did), discussion)
+
 	l.Info("discussion closed", "discussion_id", discussion.DiscussionId)
 
 	repo, _ := d.repoResolver.Resolve(r)
@@ -619,12 +1058,40 @@ func (d *Discussions) ReopenDiscussion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	client, err := d.oauth.AuthorizedClient(r)
+	if err != nil {
+		l.Error("failed to get authorized client", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to reopen discussion")
+		return
+	}
+
+	resp, err := comatproto.RepoPutRecord(r.Context(), client, &comatproto.RepoPutRecord_Input{
+		Collection: tangled.RepoDiscussionStateNSID,
+		Repo:       user.Active.Did,
+		Rkey:       tid.TID(),
+		Record: &lexutil.LexiconTypeDecoder{
+			Val: stateRecord(discussion, models.DiscussionOpen),
+		},
+	})
+	if err != nil {
+		l.Error("failed to create state record", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to reopen discussion")
+		return
+	}
+	atUri := resp.Uri
+
 	if err := db.ReopenDiscussion(d.db, discussion.RepoAt, discussion.DiscussionId); err != nil {
 		l.Error("failed to reopen discussion", "err", err)
 		d.pages.Notice(w, noticeId, "Failed to reopen discussion")
+		if err := xrpcclient.RollbackRecord(context.Background(), atUri, client); err != nil {
+			l.Error("rollback failed", "err", err)
+		}
 		return
 	}
 
+	discussion.State = models.DiscussionOpen
+	d.notifier.NewDiscussionState(r.Context(), syntax.DID(user.Active.Did), discussion)
+
 	l.Info("discussion reopened", "discussion_id", discussion.DiscussionId)
 
 	repo, _ := d.repoResolver.Resolve(r)
@@ -676,50 +1143,89 @@ func (d *Discussions) MergeDiscussion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Apply patches via knotserver
-	scheme := "http"
-	if d.config.Core.UseTLS() {
-		scheme = "https"
-	}
-	host := fmt.Sprintf("%s://%s", scheme, repo.Knot)
-
-	xrpcc := &xrpc.Client{
-		Host: host,
-	}
-
 	// Collect patch hashes in order
 	changeHashes := make([]string, len(activePatches))
 	for i, patch := range activePatches {
 		changeHashes[i] = patch.PatchHash
 	}
 
-	repoIdentifier := fmt.Sprintf("%s/%s", repo.Did, repo.Name)
+	// dry run first, so conflicts are reported instead of recorded
+	check := d.applyCheck(r, repo, discussion)
+	if check.Error != "" {
+		d.pages.Notice(w, noticeId, check.Error)
+		return
+	}
+	if check.IsConflicted {
+		files := make([]string, len(check.Conflicts))
+		for i, c := range check.Conflicts {
+			files[i] = c.Filename
+		}
+		d.pages.Notice(w, noticeId, fmt.Sprintf("Merging would leave conflicts in: %s", strings.Join(files, ", ")))
+		return
+	}
+
+	client, err := d.oauth.ServiceClient(
+		r,
+		oauth.WithService(repo.Knot),
+		oauth.WithLxm(tangled.RepoApplyChangesNSID),
+		oauth.WithDev(d.config.Core.Dev),
+	)
+	if err != nil {
+		l.Error("failed to connect to knot server", "err", err)
+		d.pages.Notice(w, noticeId, "Failed to merge discussion. Try again later.")
+		return
+	}
+
+	atomic := true
 	applyInput := &tangled.RepoApplyChanges_Input{
-		Repo:    repoIdentifier,
+		Repo:    repo.DidSlashRepo(),
 		Channel: discussion.TargetChannel,
 		Changes: changeHashes,
+		Atomic:  &atomic,
 	}
 
-	applyResult, err := tangled.RepoApplyChanges(r.Context(), xrpcc, applyInput)
+	applyResult, err := tangled.RepoApplyChanges(r.Context(), client, applyInput)
 	if err != nil {
 		l.Error("failed to apply changes", "err", err)
-		d.pages.Notice(w, noticeId, "Failed to apply patches: "+err.Error())
-		return
-	}
-
-	// Check if all patches were applied
-	if len(applyResult.Failed) > 0 {
-		failedHashes := make([]string, len(applyResult.Failed))
-		for i, f := range applyResult.Failed {
-			failedHashes[i] = f.Hash[:12]
+		var xe *xrpc.XRPCError
+		if errors.As(err, &xe) && xe.Message != "" {
+			d.pages.Notice(w, noticeId, "Failed to apply patches, the channel was left unchanged: "+xe.Message)
+			return
+		}
+		if err := xrpcclient.HandleXrpcErr(err); err != nil {
+			d.pages.Notice(w, noticeId, "Failed to apply patches: "+err.Error())
 		}
-		l.Warn("some patches failed to apply", "failed", failedHashes)
-		d.pages.Notice(w, noticeId, fmt.Sprintf("Some patches failed to apply: %v", failedHashes))
 		return
 	}
 
 	l.Info("patches applied successfully", "count", len(applyResult.Applied))
 
+	// the channel has already moved, so a missing record should not keep
+	// the discussion open here
+	var atUri string
+	if pdsClient, err := d.oauth.AuthorizedClient(r); err != nil {
+		l.Error("failed to get authorized client", "err", err)
+	} else {
+		resp, err := comatproto.RepoPutRecord(r.Context(), pdsClient, &comatproto.RepoPutRecord_Input{
+			Collection: tangled.RepoDiscussionStateNSID,
+			Repo:       user.Active.Did,
+			Rkey:       tid.TID(),
+			Record: &lexutil.LexiconTypeDecoder{
+				Val: stateRecord(discussion, models.DiscussionMerged),
+			},
+		})
+		if err != nil {
+			l.Error("failed to create state record", "err", err)
+		} else {
+			atUri = resp.Uri
+		}
+		defer func() {
+			if err := xrpcclient.RollbackRecord(context.Background(), atUri, pdsClient); err != nil {
+				l.Error("rollback failed", "err", err)
+			}
+		}()
+	}
+
 	// Mark discussion as merged
 	if err := db.MergeDiscussion(d.db, discussion.RepoAt, discussion.DiscussionId); err != nil {
 		l.Error("failed to merge discussion", "err", err)
@@ -727,6 +1233,12 @@ func (d *Discussions) MergeDiscussion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// reset atUri to make rollback a no-op
+	atUri = ""
+
+	discussion.State = models.DiscussionMerged
+	d.notifier.NewDiscussionState(r.Context(), syntax.DID(user.Active.Did), discussion)
+
 	l.Info("discussion merged", "discussion_id", discussion.DiscussionId)
 
 	repo, _ = d.repoResolver.Resolve(r)
@@ -734,6 +1246,136 @@ func (d *Discussions) MergeDiscussion(w http.ResponseWriter, r *http.Request) {
 		repo.Did, repo.Name, discussion.DiscussionId))
 }
 
+// stateRecord builds the record announcing a new state of a discussion
+func stateRecord(discussion *models.Discussion, state models.DiscussionState) *tangled.RepoDiscussionState {
+	return &tangled.RepoDiscussionState{
+		Discussion: discussion.AtUri().String(),
+		State:      state.String(),
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+}
+
+// patchStateRecord builds the record announcing that a patch was removed
+// from or re-added to its discussion
+func patchStateRecord(patch *models.DiscussionPatch, state string) *tangled.RepoDiscussionPatchState {
+	return &tangled.RepoDiscussionPatchState{
+		Patch:     patch.AtUri().String(),
+		State:     state,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+}
+
+// applyCheck asks the knot whether the active patches of a discussion apply
+// cleanly to its target channel
+func (d *Discussions) applyCheck(r *http.Request, repo *models.Repo, discussion *models.Discussion) types.MergeCheckResponse {
+	l := d.logger.With("handler", "applyCheck", "discussion_id", discussion.DiscussionId)
+
+	activePatches := discussion.ActivePatches()
+	changeHashes := make([]string, len(activePatches))
+	for i, patch := range activePatches {
+		changeHashes[i] = patch.PatchHash
+	}
+
+	xrpcc, err := d.oauth.ServiceClient(
+		r,
+		oauth.WithService(repo.Knot),
+		oauth.WithLxm(tangled.RepoApplyCheckNSID),
+		oauth.WithDev(d.config.Core.Dev),
+	)
+	if err != nil {
+		l.Error("failed to connect to knot server", "err", err)
+		return types.MergeCheckResponse{
+			Error: "failed to check merge status: could not connect to knot",
+		}
+	}
+
+	resp, xe := tangled.RepoApplyCheck(r.Context(), xrpcc, &tangled.RepoApplyCheck_Input{
+		Did:     repo.Did,
+		Name:    repo.Name,
+		Channel: discussion.TargetChannel,
+		Changes: changeHashes,
+	})
+	if err := xrpcclient.HandleXrpcErr(xe); err != nil {
+		l.Error("failed to check patches", "err", err)
+		return types.MergeCheckResponse{
+			Error: fmt.Sprintf("failed to check merge status: %s", err.Error()),
+		}
+	}
+
+	result := types.MergeCheckResponse{
+		IsConflicted: resp.Is_conflicted,
+		Conflicts:    make([]types.ConflictInfo, len(resp.Conflicts)),
+	}
+	for i, c := range resp.Conflicts {
+		result.Conflicts[i] = types.ConflictInfo{
+			Filename: c.Filename,
+			Reason:   c.Reason,
+		}
+	}
+	if resp.Message != nil {
+		result.Message = *resp.Message
+	}
+	if resp.Error != nil {
+		result.Error = *resp.Error
+	}
+
+	return result
+}
+
+// defaultChannel asks the knot for the default channel of a repo, falling
+// back to "main" if it cannot be reached
+func (d *Discussions) defaultChannel(r *http.Request, repo *models.Repo) string {
+	scheme := "http"
+	if d.config.Core.UseTLS() {
+		scheme = "https"
+	}
+
+	xrpcc := &xrpc.Client{
+		Host: fmt.Sprintf("%s://%s", scheme, repo.Knot),
+	}
+
+	resp, err := tangled.RepoGetDefaultChannel(r.Context(), xrpcc, repo.DidSlashRepo())
+	if err != nil || resp.Channel == "" {
+		d.logger.Warn("failed to fetch default channel", "err", err, "repo", repo.DidSlashRepo())
+		return "main"
+	}
+
+	return resp.Channel
+}
+
+// channelConflicts lists the unresolved conflicts already present on a
+// channel, so they are not mistaken for ones introduced by the discussion
+func (d *Discussions) channelConflicts(r *http.Request, repo *models.Repo, channel string) []types.ConflictInfo {
+	scheme := "http"
+	if d.config.Core.UseTLS() {
+		scheme = "https"
+	}
+
+	xrpcc := &xrpc.Client{
+		Host: fmt.Sprintf("%s://%s", scheme, repo.Knot),
+	}
+
+	resp, err := tangled.RepoChannelConflicts(r.Context(), xrpcc, channel, repo.DidSlashRepo())
+	if err != nil {
+		d.logger.Warn("failed to fetch channel conflicts", "err", err, "channel", channel)
+		return nil
+	}
+
+	conflicts := make([]types.ConflictInfo, len(resp.Conflicts))
+	for i, c := range resp.Conflicts {
+		var reason string
+		if c.Reason != nil {
+			reason = *c.Reason
+		}
+		conflicts[i] = types.ConflictInfo{
+			Filename: c.Path,
+			Reason:   reason,
+			Changes:  c.Changes,
+		}
+	}
+	return conflicts
+}
+
 // getChangeFromKnot fetches change details (including dependencies) from knotserver
 func (d *Discussions) getChangeFromKnot(ctx context.Context, knot, repo, hash string) (*tangled.RepoChangeGet_Output, error) {
 	scheme := "http"
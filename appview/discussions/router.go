@@ -24,6 +24,7 @@ func (d *Discussions) Router(mw *middleware.Middleware) http.Handler {
 		r.Route("/{discussion}", func(r chi.Router) {
 			r.Use(mw.ResolveDiscussion)
 			r.Get("/", d.RepoSingleDiscussion)
+			r.Get("/interdiff", d.RepoDiscussionInterdiff)
 
 			// Authenticated routes
 			r.Group(func(r chi.Router) {
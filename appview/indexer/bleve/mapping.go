@@ -0,0 +1,92 @@
+package bleveutil
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/token/camelcase"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/token/unicodenorm"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/index/upsidedown"
+	"github.com/blevesearch/bleve/v2/mapping"
+	tlog "tangled.org/core/log"
+)
+
+const unicodeNormalizeName = "unicodeNormalize"
+
+// NewIndexMapping builds an index holding documents of a single type. Text
+// is split into unicode words, normalized to NFC, split on camel case and
+// lowercased by the named analyzer; only the fields of docMapping are
+// indexed.
+func NewIndexMapping(analyzer, docType string, docMapping *mapping.DocumentMapping) (mapping.IndexMapping, error) {
+	m := bleve.NewIndexMapping()
+
+	err := m.AddCustomTokenFilter(unicodeNormalizeName, map[string]any{
+		"type": unicodenorm.Name,
+		"form": unicodenorm.NFC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.AddCustomAnalyzer(analyzer, map[string]any{
+		"type":          custom.Name,
+		"char_filters":  []string{},
+		"tokenizer":     unicode.Name,
+		"token_filters": []string{unicodeNormalizeName, camelcase.Name, lowercase.Name},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.DefaultAnalyzer = analyzer
+	m.AddDocumentMapping(docType, docMapping)
+	m.AddDocumentMapping("_all", bleve.NewDocumentDisabledMapping())
+	m.DefaultMapping = bleve.NewDocumentDisabledMapping()
+
+	return m, nil
+}
+
+// TextFieldMapping is an analyzed field that is searched but not stored
+func TextFieldMapping() *mapping.FieldMapping {
+	f := bleve.NewTextFieldMapping()
+	f.Store = false
+	f.IncludeInAll = false
+	return f
+}
+
+// KeywordFieldMapping is a field matched as a whole, not stored
+func KeywordFieldMapping() *mapping.FieldMapping {
+	f := bleve.NewKeywordFieldMapping()
+	f.Store = false
+	f.IncludeInAll = false
+	return f
+}
+
+// BoolFieldMapping is a boolean field, not stored
+func BoolFieldMapping() *mapping.FieldMapping {
+	f := bleve.NewBooleanFieldMapping()
+	f.Store = false
+	f.IncludeInAll = false
+	return f
+}
+
+// OpenIndex opens the index at path. It returns a nil index if there is
+// none, or if it was built by an older version of bleve, in which case it is
+// deleted to be built again.
+func OpenIndex(ctx context.Context, path string) (bleve.Index, error) {
+	l := tlog.FromContext(ctx)
+	index, err := bleve.Open(path)
+	if err != nil {
+		if errors.Is(err, upsidedown.IncompatibleVersion) {
+			l.Info("Indexer was built with a previous version of bleve, deleting and rebuilding")
+			return nil, os.RemoveAll(path)
+		}
+		return nil, nil
+	}
+	return index, nil
+}
@@ -0,0 +1,238 @@
+package discussions_indexer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"tangled.org/core/appview/db"
+	"tangled.org/core/appview/indexer/base36"
+	"tangled.org/core/appview/indexer/bleve"
+	"tangled.org/core/appview/models"
+	"tangled.org/core/appview/pagination"
+	tlog "tangled.org/core/log"
+)
+
+const (
+	discussionIndexerAnalyzer = "discussionIndexer"
+	discussionIndexerDocType  = "discussionIndexerDocType"
+)
+
+type Indexer struct {
+	indexer bleve.Index
+	path    string
+}
+
+func NewIndexer(indexDir string) *Indexer {
+	return &Indexer{
+		path: indexDir,
+	}
+}
+
+// Init initializes the indexer
+func (ix *Indexer) Init(ctx context.Context, e db.Execer) {
+	l := tlog.FromContext(ctx)
+	existed, err := ix.intialize(ctx)
+	if err != nil {
+		log.Fatalln("failed to initialize discussion indexer", err)
+	}
+	if !existed {
+		l.Debug("Populating the discussion indexer")
+		err := PopulateIndexer(ctx, ix, e)
+		if err != nil {
+			log.Fatalln("failed to populate discussion indexer", err)
+		}
+	}
+
+	count, _ := ix.indexer.DocCount()
+	l.Info("Initialized the discussion indexer", "docCount", count)
+}
+
+func generateDiscussionIndexMapping() (mapping.IndexMapping, error) {
+	docMapping := bleve.NewDocumentMapping()
+	textFieldMapping := bleveutil.TextFieldMapping()
+	keywordFieldMapping := bleveutil.KeywordFieldMapping()
+
+	docMapping.AddFieldMappingsAt("title", textFieldMapping)
+	docMapping.AddFieldMappingsAt("body", textFieldMapping)
+	docMapping.AddFieldMappingsAt("comments", textFieldMapping)
+	docMapping.AddFieldMappingsAt("patches", textFieldMapping)
+
+	docMapping.AddFieldMappingsAt("repo_at", keywordFieldMapping)
+	docMapping.AddFieldMappingsAt("state", keywordFieldMapping)
+	docMapping.AddFieldMappingsAt("labels", keywordFieldMapping)
+
+	return bleveutil.NewIndexMapping(discussionIndexerAnalyzer, discussionIndexerDocType, docMapping)
+}
+
+func (ix *Indexer) intialize(ctx context.Context) (bool, error) {
+	if ix.indexer != nil {
+		return false, errors.New("indexer is already initialized")
+	}
+
+	indexer, err := bleveutil.OpenIndex(ctx, ix.path)
+	if err != nil {
+		return false, err
+	}
+	if indexer != nil {
+		ix.indexer = indexer
+		return true, nil
+	}
+
+	mapping, err := generateDiscussionIndexMapping()
+	if err != nil {
+		return false, err
+	}
+	indexer, err = bleve.New(ix.path, mapping)
+	if err != nil {
+		return false, err
+	}
+
+	ix.indexer = indexer
+
+	return false, nil
+}
+
+func PopulateIndexer(ctx context.Context, ix *Indexer, e db.Execer) error {
+	l := tlog.FromContext(ctx)
+	count := 0
+	err := pagination.IterateAll(
+		func(page pagination.Page) ([]models.Discussion, error) {
+			return db.GetDiscussionsPaginated(e, page)
+		},
+		func(discussions []models.Discussion) error {
+			count += len(discussions)
+			return ix.Index(ctx, discussions...)
+		},
+	)
+	l.Info("discussions indexed", "count", count)
+	return err
+}
+
+// discussionData data stored and will be indexed
+type discussionData struct {
+	ID           int64  `json:"id"`
+	RepoAt       string `json:"repo_at"`
+	DiscussionID int    `json:"discussion_id"`
+	Title        string `json:"title"`
+	Body         string `json:"body"`
+	State        string `json:"state"`
+
+	Labels   []string `json:"labels"`
+	Comments []string `json:"comments"`
+	Patches  []string `json:"patches"`
+}
+
+func makeDiscussionData(discussion *models.Discussion) *discussionData {
+	data := &discussionData{
+		ID:           discussion.Id,
+		RepoAt:       discussion.RepoAt.String(),
+		DiscussionID: discussion.DiscussionId,
+		Title:        discussion.Title,
+		Body:         discussion.Body,
+		State:        discussion.State.String(),
+	}
+	for label := range discussion.Labels.Inner() {
+		data.Labels = append(data.Labels, label)
+	}
+	for _, c := range discussion.Comments {
+		if c.Deleted == nil {
+			data.Comments = append(data.Comments, c.Body)
+		}
+	}
+	for _, p := range discussion.ActivePatches() {
+		if msg := patchMessage(p.Patch); msg != "" {
+			data.Patches = append(data.Patches, msg)
+		}
+	}
+	return data
+}
+
+// patchMessage extracts the message from the header of a pijul change
+func patchMessage(patch string) string {
+	scanner := bufio.NewScanner(strings.NewReader(patch))
+	for scanner.Scan() {
+		line := scanner.Text()
+		// the header ends where the first table or section begins
+		if strings.HasPrefix(line, "[") || strings.HasPrefix(line, "#") {
+			break
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "message" {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		return strings.Trim(val, `'"`)
+	}
+	return ""
+}
+
+// Type returns the document type, for bleve's mapping.Classifier interface.
+func (d *discussionData) Type() string {
+	return discussionIndexerDocType
+}
+
+type SearchResult struct {
+	Hits  []int64
+	Total uint64
+}
+
+const maxBatchSize = 20
+
+func (ix *Indexer) Index(ctx context.Context, discussions ...models.Discussion) error {
+	batch := bleveutil.NewFlushingBatch(ix.indexer, maxBatchSize)
+	for _, discussion := range discussions {
+		discussionData := makeDiscussionData(&discussion)
+		if err := batch.Index(base36.Encode(discussion.Id), discussionData); err != nil {
+			return err
+		}
+	}
+	return batch.Flush()
+}
+
+func (ix *Indexer) Delete(ctx context.Context, discussionId int64) error {
+	return ix.indexer.Delete(base36.Encode(discussionId))
+}
+
+// Search searches for discussions
+func (ix *Indexer) Search(ctx context.Context, opts models.DiscussionSearchOptions) (*SearchResult, error) {
+	var queries []query.Query
+
+	if opts.Keyword != "" {
+		queries = append(queries, bleve.NewDisjunctionQuery(
+			bleveutil.MatchAndQuery("title", opts.Keyword, discussionIndexerAnalyzer, 0),
+			bleveutil.MatchAndQuery("body", opts.Keyword, discussionIndexerAnalyzer, 0),
+			bleveutil.MatchAndQuery("comments", opts.Keyword, discussionIndexerAnalyzer, 0),
+			bleveutil.MatchAndQuery("patches", opts.Keyword, discussionIndexerAnalyzer, 0),
+		))
+	}
+	queries = append(queries, bleveutil.KeywordFieldQuery("repo_at", opts.RepoAt))
+	queries = append(queries, bleveutil.KeywordFieldQuery("state", opts.State.String()))
+	for _, label := range opts.Labels {
+		queries = append(queries, bleveutil.KeywordFieldQuery("labels", label))
+	}
+
+	var indexerQuery query.Query = bleve.NewConjunctionQuery(queries...)
+	searchReq := bleve.NewSearchRequestOptions(indexerQuery, opts.Page.Limit, opts.Page.Offset, false)
+	res, err := ix.indexer.SearchInContext(ctx, searchReq)
+	if err != nil {
+		return nil, err
+	}
+	ret := &SearchResult{
+		Total: res.Total,
+		Hits:  make([]int64, len(res.Hits)),
+	}
+	for i, hit := range res.Hits {
+		id, err := base36.Decode(hit.ID)
+		if err != nil {
+			return nil, err
+		}
+		ret.Hits[i] = id
+	}
+	return ret, nil
+}
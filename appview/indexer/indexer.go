@@ -5,6 +5,7 @@ import (
 	"log/slog"
 
 	"tangled.org/core/appview/db"
+	discussions_indexer "tangled.org/core/appview/indexer/discussions"
 	issues_indexer "tangled.org/core/appview/indexer/issues"
 	pulls_indexer "tangled.org/core/appview/indexer/pulls"
 	"tangled.org/core/appview/notify"
@@ -12,9 +13,11 @@ import (
 )
 
 type Indexer struct {
-	Issues *issues_indexer.Indexer
-	Pulls  *pulls_indexer.Indexer
-	logger *slog.Logger
+	Issues      *issues_indexer.Indexer
+	Pulls       *pulls_indexer.Indexer
+	Discussions *discussions_indexer.Indexer
+	db          *db.DB
+	logger      *slog.Logger
 	notify.BaseNotifier
 }
 
@@ -22,6 +25,8 @@ func New(logger *slog.Logger) *Indexer {
 	return &Indexer{
 		issues_indexer.NewIndexer("indexes/issues.bleve"),
 		pulls_indexer.NewIndexer("indexes/pulls.bleve"),
+		discussions_indexer.NewIndexer("indexes/discussions.bleve"),
+		nil,
 		logger,
 		notify.BaseNotifier{},
 	}
@@ -32,5 +37,10 @@ func (ix *Indexer) Init(ctx context.Context, db *db.DB) error {
 	ctx = tlog.IntoContext(ctx, ix.logger)
 	ix.Issues.Init(ctx, db)
 	ix.Pulls.Init(ctx, db)
+	ix.Discussions.Init(ctx, db)
+
+	// discussion documents include comments and patches, so they are
+	// re-read from the db whenever one of those changes
+	ix.db = db
 	return nil
 }
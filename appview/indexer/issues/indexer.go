@@ -5,15 +5,8 @@ import (
 	"context"
 	"errors"
 	"log"
-	"os"
 
 	"github.com/blevesearch/bleve/v2"
-	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
-	"github.com/blevesearch/bleve/v2/analysis/token/camelcase"
-	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
-	"github.com/blevesearch/bleve/v2/analysis/token/unicodenorm"
-	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
-	"github.com/blevesearch/bleve/v2/index/upsidedown"
 	"github.com/blevesearch/bleve/v2/mapping"
 	"github.com/blevesearch/bleve/v2/search/query"
 	"tangled.org/core/appview/db"
@@ -27,8 +20,6 @@ import (
 const (
 	issueIndexerAnalyzer = "issueIndexer"
 	issueIndexerDocType  = "issueIndexerDocType"
-
-	unicodeNormalizeName = "uicodeNormalize"
 )
 
 type Indexer struct {
@@ -62,22 +53,10 @@ func (ix *Indexer) Init(ctx context.Context, e db.Execer) {
 }
 
 func generateIssueIndexMapping() (mapping.IndexMapping, error) {
-	mapping := bleve.NewIndexMapping()
 	docMapping := bleve.NewDocumentMapping()
-
-	textFieldMapping := bleve.NewTextFieldMapping()
-	textFieldMapping.Store = false
-	textFieldMapping.IncludeInAll = false
-
-	boolFieldMapping := bleve.NewBooleanFieldMapping()
-	boolFieldMapping.Store = false
-	boolFieldMapping.IncludeInAll = false
-
-	keywordFieldMapping := bleve.NewKeywordFieldMapping()
-	keywordFieldMapping.Store = false
-	keywordFieldMapping.IncludeInAll = false
-
-	// numericFieldMapping := bleve.NewNumericFieldMapping()
+	textFieldMapping := bleveutil.TextFieldMapping()
+	boolFieldMapping := bleveutil.BoolFieldMapping()
+	keywordFieldMapping := bleveutil.KeywordFieldMapping()
 
 	docMapping.AddFieldMappingsAt("title", textFieldMapping)
 	docMapping.AddFieldMappingsAt("body", textFieldMapping)
@@ -85,30 +64,7 @@ func generateIssueIndexMapping() (mapping.IndexMapping, error) {
 	docMapping.AddFieldMappingsAt("repo_at", keywordFieldMapping)
 	docMapping.AddFieldMappingsAt("is_open", boolFieldMapping)
 
-	err := mapping.AddCustomTokenFilter(unicodeNormalizeName, map[string]any{
-		"type": unicodenorm.Name,
-		"form": unicodenorm.NFC,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	err = mapping.AddCustomAnalyzer(issueIndexerAnalyzer, map[string]any{
-		"type":          custom.Name,
-		"char_filters":  []string{},
-		"tokenizer":     unicode.Name,
-		"token_filters": []string{unicodeNormalizeName, camelcase.Name, lowercase.Name},
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	mapping.DefaultAnalyzer = issueIndexerAnalyzer
-	mapping.AddDocumentMapping(issueIndexerDocType, docMapping)
-	mapping.AddDocumentMapping("_all", bleve.NewDocumentDisabledMapping())
-	mapping.DefaultMapping = bleve.NewDocumentDisabledMapping()
-
-	return mapping, nil
+	return bleveutil.NewIndexMapping(issueIndexerAnalyzer, issueIndexerDocType, docMapping)
 }
 
 func (ix *Indexer) intialize(ctx context.Context) (bool, error) {
@@ -116,7 +72,7 @@ func (ix *Indexer) intialize(ctx context.Context) (bool, error) {
 		return false, errors.New("indexer is already initialized")
 	}
 
-	indexer, err := openIndexer(ctx, ix.path)
+	indexer, err := bleveutil.OpenIndex(ctx, ix.path)
 	if err != nil {
 		return false, err
 	}
@@ -139,19 +95,6 @@ func (ix *Indexer) intialize(ctx context.Context) (bool, error) {
 	return false, nil
 }
 
-func openIndexer(ctx context.Context, path string) (bleve.Index, error) {
-	l := tlog.FromContext(ctx)
-	indexer, err := bleve.Open(path)
-	if err != nil {
-		if errors.Is(err, upsidedown.IncompatibleVersion) {
-			l.Info("Indexer was built with a previous version of bleve, deleting and rebuilding")
-			return nil, os.RemoveAll(path)
-		}
-		return nil, nil
-	}
-	return indexer, nil
-}
-
 func PopulateIndexer(ctx context.Context, ix *Indexer, e db.Execer) error {
 	l := tlog.FromContext(ctx)
 	count := 0
@@ -4,9 +4,11 @@ import (
 	"context"
 
 	"github.com/bluesky-social/indigo/atproto/syntax"
+	"tangled.org/core/appview/db"
 	"tangled.org/core/appview/models"
 	"tangled.org/core/appview/notify"
 	"tangled.org/core/log"
+	"tangled.org/core/orm"
 )
 
 var _ notify.Notifier = &Indexer{}
@@ -55,3 +57,42 @@ func (ix *Indexer) NewPullState(ctx context.Context, actor syntax.DID, pull *mod
 		l.Error("failed to index a pr", "err", err)
 	}
 }
+
+func (ix *Indexer) NewDiscussion(ctx context.Context, discussion *models.Discussion, mentions []syntax.DID) {
+	l := log.FromContext(ctx).With("notifier", "indexer", "discussion", discussion.AtUri())
+	l.Debug("indexing new discussion")
+	ix.reindexDiscussion(ctx, discussion.AtUri().String())
+}
+
+func (ix *Indexer) NewDiscussionComment(ctx context.Context, comment *models.DiscussionComment, mentions []syntax.DID) {
+	l := log.FromContext(ctx).With("notifier", "indexer", "discussion", comment.DiscussionAt)
+	l.Debug("updating a discussion")
+	ix.reindexDiscussion(ctx, comment.DiscussionAt)
+}
+
+func (ix *Indexer) NewDiscussionPatch(ctx context.Context, patch *models.DiscussionPatch) {
+	l := log.FromContext(ctx).With("notifier", "indexer", "discussion", patch.DiscussionAt)
+	l.Debug("updating a discussion")
+	ix.reindexDiscussion(ctx, patch.DiscussionAt.String())
+}
+
+func (ix *Indexer) NewDiscussionState(ctx context.Context, actor syntax.DID, discussion *models.Discussion) {
+	l := log.FromContext(ctx).With("notifier", "indexer", "discussion", discussion.AtUri())
+	l.Debug("updating a discussion")
+	ix.reindexDiscussion(ctx, discussion.AtUri().String())
+}
+
+func (ix *Indexer) reindexDiscussion(ctx context.Context, discussionAt string) {
+	l := log.FromContext(ctx)
+
+	discussions, err := db.GetDiscussions(ix.db, orm.FilterEq("at_uri", discussionAt))
+	if err != nil {
+		l.Error("failed to fetch discussion", "err", err)
+		return
+	}
+
+	err = ix.Discussions.Index(ctx, discussions...)
+	if err != nil {
+		l.Error("failed to index a discussion", "err", err)
+	}
+}
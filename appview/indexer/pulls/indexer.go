@@ -5,15 +5,8 @@ import (
 	"context"
 	"errors"
 	"log"
-	"os"
 
 	"github.com/blevesearch/bleve/v2"
-	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
-	"github.com/blevesearch/bleve/v2/analysis/token/camelcase"
-	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
-	"github.com/blevesearch/bleve/v2/analysis/token/unicodenorm"
-	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
-	"github.com/blevesearch/bleve/v2/index/upsidedown"
 	"github.com/blevesearch/bleve/v2/mapping"
 	"github.com/blevesearch/bleve/v2/search/query"
 	"tangled.org/core/appview/db"
@@ -26,8 +19,6 @@ import (
 const (
 	pullIndexerAnalyzer = "pullIndexer"
 	pullIndexerDocType  = "pullIndexerDocType"
-
-	unicodeNormalizeName = "uicodeNormalize"
 )
 
 type Indexer struct {
@@ -61,18 +52,9 @@ func (ix *Indexer) Init(ctx context.Context, e db.Execer) {
 }
 
 func generatePullIndexMapping() (mapping.IndexMapping, error) {
-	mapping := bleve.NewIndexMapping()
 	docMapping := bleve.NewDocumentMapping()
-
-	textFieldMapping := bleve.NewTextFieldMapping()
-	textFieldMapping.Store = false
-	textFieldMapping.IncludeInAll = false
-
-	keywordFieldMapping := bleve.NewKeywordFieldMapping()
-	keywordFieldMapping.Store = false
-	keywordFieldMapping.IncludeInAll = false
-
-	// numericFieldMapping := bleve.NewNumericFieldMapping()
+	textFieldMapping := bleveutil.TextFieldMapping()
+	keywordFieldMapping := bleveutil.KeywordFieldMapping()
 
 	docMapping.AddFieldMappingsAt("title", textFieldMapping)
 	docMapping.AddFieldMappingsAt("body", textFieldMapping)
@@ -80,30 +62,7 @@ func generatePullIndexMapping() (mapping.IndexMapping, error) {
 	docMapping.AddFieldMappingsAt("repo_at", keywordFieldMapping)
 	docMapping.AddFieldMappingsAt("state", keywordFieldMapping)
 
-	err := mapping.AddCustomTokenFilter(unicodeNormalizeName, map[string]any{
-		"type": unicodenorm.Name,
-		"form": unicodenorm.NFC,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	err = mapping.AddCustomAnalyzer(pullIndexerAnalyzer, map[string]any{
-		"type":          custom.Name,
-		"char_filters":  []string{},
-		"tokenizer":     unicode.Name,
-		"token_filters": []string{unicodeNormalizeName, camelcase.Name, lowercase.Name},
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	mapping.DefaultAnalyzer = pullIndexerAnalyzer
-	mapping.AddDocumentMapping(pullIndexerDocType, docMapping)
-	mapping.AddDocumentMapping("_all", bleve.NewDocumentDisabledMapping())
-	mapping.DefaultMapping = bleve.NewDocumentDisabledMapping()
-
-	return mapping, nil
+	return bleveutil.NewIndexMapping(pullIndexerAnalyzer, pullIndexerDocType, docMapping)
 }
 
 func (ix *Indexer) intialize(ctx context.Context) (bool, error) {
@@ -111,7 +70,7 @@ func (ix *Indexer) intialize(ctx context.Context) (bool, error) {
 		return false, errors.New("indexer is already initialized")
 	}
 
-	indexer, err := openIndexer(ctx, ix.path)
+	indexer, err := bleveutil.OpenIndex(ctx, ix.path)
 	if err != nil {
 		return false, err
 	}
@@ -134,19 +93,6 @@ func (ix *Indexer) intialize(ctx context.Context) (bool, error) {
 	return false, nil
 }
 
-func openIndexer(ctx context.Context, path string) (bleve.Index, error) {
-	l := tlog.FromContext(ctx)
-	indexer, err := bleve.Open(path)
-	if err != nil {
-		if errors.Is(err, upsidedown.IncompatibleVersion) {
-			l.Info("Indexer was built with a previous version of bleve, deleting and rebuilding")
-			return nil, os.RemoveAll(path)
-		}
-		return nil, nil
-	}
-	return indexer, nil
-}
-
 func PopulateIndexer(ctx context.Context, ix *Indexer, e db.Execer) error {
 	l := tlog.FromContext(ctx)
 
@@ -81,6 +81,16 @@ func (i *Ingester) Ingest() processFunc {
 				err = i.ingestIssue(ctx, e)
 			case tangled.RepoIssueCommentNSID:
 				err = i.ingestIssueComment(e)
+			case tangled.RepoDiscussionNSID:
+				err = i.ingestDiscussion(ctx, e)
+			case tangled.RepoDiscussionPatchNSID:
+				err = i.ingestDiscussionPatch(ctx, e)
+			case tangled.RepoDiscussionPatchStateNSID:
+				err = i.ingestDiscussionPatchState(e)
+			case tangled.RepoDiscussionCommentNSID:
+				err = i.ingestDiscussionComment(e)
+			case tangled.RepoDiscussionStateNSID:
+				err = i.ingestDiscussionState(e)
 			case tangled.LabelDefinitionNSID:
 				err = i.ingestLabelDefinition(e)
 			case tangled.LabelOpNSID:
@@ -230,11 +240,25 @@ func (i *Ingester) ingestArtifact(e *jmodels.Event) error {
 			return err
 		}
 
-		ok, err := i.Enforcer.E.Enforce(did, repo.Knot, repo.DidSlashRepo(), "repo:push")
+		perm := "repo:push"
+		if repo.IsPijul() {
+			perm = "pijul:edit_tags"
+		}
+		ok, err := i.Enforcer.E.Enforce(did, repo.Knot, repo.DidSlashRepo(), perm)
 		if err != nil || !ok {
 			return err
 		}
 
+		var tag plumbing.Hash
+		switch {
+		case record.PijulState != nil:
+			tag = models.PijulTagKey(*record.PijulState)
+		case len(record.Tag) == len(tag):
+			tag = plumbing.Hash(record.Tag)
+		default:
+			return fmt.Errorf("artifact has neither a tag nor a pijul state")
+		}
+
 		createdAt, err := time.Parse(time.RFC3339, record.CreatedAt)
 		if err != nil {
 			createdAt = time.Now()
@@ -244,7 +268,7 @@ func (i *Ingester) ingestArtifact(e *jmodels.Event) error {
 			Did:       did,
 			Rkey:      e.Commit.RKey,
 			RepoAt:    repoAt,
-			Tag:       plumbing.Hash(record.Tag),
+			Tag:       tag,
 			CreatedAt: createdAt,
 			BlobCid:   cid.Cid(record.Artifact.Ref),
 			Name:      record.Name,
@@ -934,6 +958,324 @@ func (i *Ingester) ingestIssueComment(e *jmodels.Event) error {
 	return nil
 }
 
+func (i *Ingester) ingestDiscussion(ctx context.Context, e *jmodels.Event) error {
+	did := e.Did
+	rkey := e.Commit.RKey
+
+	var err error
+
+	l := i.Logger.With("handler", "ingestDiscussion", "nsid", e.Commit.Collection, "did", did, "rkey", rkey)
+	l.Info("ingesting record")
+
+	ddb, ok := i.Db.Execer.(*db.DB)
+	if !ok {
+		return fmt.Errorf("failed to index discussion record, invalid db cast")
+	}
+
+	switch e.Commit.Operation {
+	case jmodels.CommitOperationCreate, jmodels.CommitOperationUpdate:
+		raw := json.RawMessage(e.Commit.Record)
+		record := tangled.RepoDiscussion{}
+		err = json.Unmarshal(raw, &record)
+		if err != nil {
+			l.Error("invalid record", "err", err)
+			return err
+		}
+
+		discussion := models.DiscussionFromRecord(did, rkey, record)
+
+		if err := i.Validator.ValidateDiscussion(&discussion); err != nil {
+			return fmt.Errorf("failed to validate discussion: %w", err)
+		}
+
+		tx, err := ddb.BeginTx(ctx, nil)
+		if err != nil {
+			l.Error("failed to begin transaction", "err", err)
+			return err
+		}
+		defer tx.Rollback()
+
+		err = db.PutDiscussion(tx, &discussion)
+		if err != nil {
+			l.Error("failed to create discussion", "err", err)
+			return err
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			l.Error("failed to commit txn", "err", err)
+			return err
+		}
+
+		return nil
+
+	case jmodels.CommitOperationDelete:
+		tx, err := ddb.BeginTx(ctx, nil)
+		if err != nil {
+			l.Error("failed to begin transaction", "err", err)
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := db.DeleteDiscussion(tx, did, rkey); err != nil {
+			l.Error("failed to delete", "err", err)
+			return fmt.Errorf("failed to delete discussion record: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			l.Error("failed to commit txn", "err", err)
+			return err
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+func (i *Ingester) ingestDiscussionPatch(ctx context.Context, e *jmodels.Event) error {
+	did := e.Did
+	rkey := e.Commit.RKey
+
+	var err error
+
+	l := i.Logger.With("handler", "ingestDiscussionPatch", "nsid", e.Commit.Collection, "did", did, "rkey", rkey)
+	l.Info("ingesting record")
+
+	ddb, ok := i.Db.Execer.(*db.DB)
+	if !ok {
+		return fmt.Errorf("failed to index discussion patch record, invalid db cast")
+	}
+
+	switch e.Commit.Operation {
+	// patches are immutable once added, updates are not ingested
+	case jmodels.CommitOperationCreate:
+		raw := json.RawMessage(e.Commit.Record)
+		record := tangled.RepoDiscussionPatch{}
+		err = json.Unmarshal(raw, &record)
+		if err != nil {
+			return fmt.Errorf("invalid record: %w", err)
+		}
+
+		patch, err := models.DiscussionPatchFromRecord(did, rkey, record)
+		if err != nil {
+			return fmt.Errorf("failed to parse patch from record: %w", err)
+		}
+
+		if err := i.Validator.ValidateDiscussionPatch(patch); err != nil {
+			return fmt.Errorf("failed to validate patch: %w", err)
+		}
+
+		tx, err := ddb.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to start transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		if err := db.AddDiscussionPatch(tx, patch); err != nil {
+			return fmt.Errorf("failed to add discussion patch: %w", err)
+		}
+
+		return tx.Commit()
+
+	case jmodels.CommitOperationDelete:
+		if err := db.DeleteDiscussionPatch(ddb, did, rkey); err != nil {
+			return fmt.Errorf("failed to delete discussion patch record: %w", err)
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+func (i *Ingester) ingestDiscussionPatchState(e *jmodels.Event) error {
+	did := e.Did
+	rkey := e.Commit.RKey
+
+	var err error
+
+	l := i.Logger.With("handler", "ingestDiscussionPatchState", "nsid", e.Commit.Collection, "did", did, "rkey", rkey)
+	l.Info("ingesting record")
+
+	ddb, ok := i.Db.Execer.(*db.DB)
+	if !ok {
+		return fmt.Errorf("failed to index discussion patch state record, invalid db cast")
+	}
+
+	// state records are a log of changes, deleting one does not undo it
+	if e.Commit.Operation != jmodels.CommitOperationCreate {
+		return nil
+	}
+
+	raw := json.RawMessage(e.Commit.Record)
+	record := tangled.RepoDiscussionPatchState{}
+	err = json.Unmarshal(raw, &record)
+	if err != nil {
+		return fmt.Errorf("invalid record: %w", err)
+	}
+
+	patchAt, err := syntax.ParseATURI(record.Patch)
+	if err != nil {
+		return err
+	}
+
+	patches, err := db.GetDiscussionPatches(
+		ddb,
+		orm.FilterEq("pushed_by_did", patchAt.Authority().String()),
+		orm.FilterEq("rkey", patchAt.RecordKey().String()),
+	)
+	if err != nil || len(patches) != 1 {
+		return fmt.Errorf("failed to find patch: %w || patch count %d", err, len(patches))
+	}
+	patch := patches[0]
+
+	discussions, err := db.GetDiscussions(ddb, orm.FilterEq("at_uri", patch.DiscussionAt))
+	if err != nil || len(discussions) != 1 {
+		return fmt.Errorf("failed to find discussion: %w || discussion count %d", err, len(discussions))
+	}
+	repo := discussions[0].Repo
+
+	// the pusher can always withdraw their own patch
+	if patch.PushedByDid != did {
+		ok, err := i.Enforcer.E.Enforce(did, repo.Knot, repo.DidSlashRepo(), rbac.PijulEditDiscussion)
+		if err != nil || !ok {
+			return fmt.Errorf("%s is not allowed to change the state of patch %s: %w", did, record.Patch, err)
+		}
+	}
+
+	switch record.State {
+	case models.DiscussionPatchRemoved:
+		err = db.RemovePatch(ddb, patch.Id)
+	case models.DiscussionPatchActive:
+		err = db.ReaddPatch(ddb, patch.Id)
+	default:
+		return fmt.Errorf("unknown patch state: %q", record.State)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update patch state: %w", err)
+	}
+
+	return nil
+}
+
+func (i *Ingester) ingestDiscussionComment(e *jmodels.Event) error {
+	did := e.Did
+	rkey := e.Commit.RKey
+
+	var err error
+
+	l := i.Logger.With("handler", "ingestDiscussionComment", "nsid", e.Commit.Collection, "did", did, "rkey", rkey)
+	l.Info("ingesting record")
+
+	ddb, ok := i.Db.Execer.(*db.DB)
+	if !ok {
+		return fmt.Errorf("failed to index discussion comment record, invalid db cast")
+	}
+
+	switch e.Commit.Operation {
+	case jmodels.CommitOperationCreate, jmodels.CommitOperationUpdate:
+		raw := json.RawMessage(e.Commit.Record)
+		record := tangled.RepoDiscussionComment{}
+		err = json.Unmarshal(raw, &record)
+		if err != nil {
+			return fmt.Errorf("invalid record: %w", err)
+		}
+
+		comment, err := models.DiscussionCommentFromRecord(did, rkey, record)
+		if err != nil {
+			return fmt.Errorf("failed to parse comment from record: %w", err)
+		}
+
+		if err := i.Validator.ValidateDiscussionComment(comment); err != nil {
+			return fmt.Errorf("failed to validate comment: %w", err)
+		}
+
+		tx, err := ddb.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to start transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		_, err = db.AddDiscussionComment(tx, *comment)
+		if err != nil {
+			return fmt.Errorf("failed to create discussion comment: %w", err)
+		}
+
+		return tx.Commit()
+
+	case jmodels.CommitOperationDelete:
+		if err := db.DeleteDiscussionComment(
+			ddb,
+			orm.FilterEq("did", did),
+			orm.FilterEq("rkey", rkey),
+		); err != nil {
+			return fmt.Errorf("failed to delete discussion comment record: %w", err)
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+func (i *Ingester) ingestDiscussionState(e *jmodels.Event) error {
+	did := e.Did
+	rkey := e.Commit.RKey
+
+	var err error
+
+	l := i.Logger.With("handler", "ingestDiscussionState", "nsid", e.Commit.Collection, "did", did, "rkey", rkey)
+	l.Info("ingesting record")
+
+	ddb, ok := i.Db.Execer.(*db.DB)
+	if !ok {
+		return fmt.Errorf("failed to index discussion state record, invalid db cast")
+	}
+
+	// state records are a log of changes, deleting one does not undo it
+	if e.Commit.Operation != jmodels.CommitOperationCreate {
+		return nil
+	}
+
+	raw := json.RawMessage(e.Commit.Record)
+	record := tangled.RepoDiscussionState{}
+	err = json.Unmarshal(raw, &record)
+	if err != nil {
+		return fmt.Errorf("invalid record: %w", err)
+	}
+
+	state, err := models.ParseDiscussionState(record.State)
+	if err != nil {
+		return err
+	}
+
+	discussions, err := db.GetDiscussions(ddb, orm.FilterEq("at_uri", record.Discussion))
+	if err != nil || len(discussions) != 1 {
+		return fmt.Errorf("failed to find discussion: %w || discussion count %d", err, len(discussions))
+	}
+	discussion := discussions[0]
+	repo := discussion.Repo
+
+	// the author can close and reopen their own discussion, merging and
+	// everything else is up to collaborators
+	perm := rbac.PijulEditDiscussion
+	if state.IsMerged() {
+		perm = rbac.PijulApply
+	}
+	if discussion.Did != did || state.IsMerged() {
+		ok, err := i.Enforcer.E.Enforce(did, repo.Knot, repo.DidSlashRepo(), perm)
+		if err != nil || !ok {
+			return fmt.Errorf("%s is not allowed to set discussion %s to %s: %w", did, record.Discussion, state, err)
+		}
+	}
+
+	if err := db.SetDiscussionState(ddb, discussion.RepoAt, discussion.DiscussionId, state); err != nil {
+		return fmt.Errorf("failed to update discussion state: %w", err)
+	}
+
+	return nil
+}
+
 func (i *Ingester) ingestLabelDefinition(e *jmodels.Event) error {
 	did := e.Did
 	rkey := e.Commit.RKey
@@ -10,7 +10,6 @@ import (
 	"time"
 
 	comatproto "github.com/bluesky-social/indigo/api/atproto"
-	atpclient "github.com/bluesky-social/indigo/atproto/client"
 	"github.com/bluesky-social/indigo/atproto/syntax"
 	lexutil "github.com/bluesky-social/indigo/lex/util"
 	"github.com/go-chi/chi/v5"
@@ -28,6 +27,7 @@ import (
 	"tangled.org/core/appview/pagination"
 	"tangled.org/core/appview/reporesolver"
 	"tangled.org/core/appview/validator"
+	"tangled.org/core/appview/xrpcclient"
 	"tangled.org/core/idresolver"
 	"tangled.org/core/orm"
 	"tangled.org/core/rbac"
@@ -454,7 +454,7 @@ func (rp *Issues) NewIssueComment(w http.ResponseWriter, r *http.Request) {
 	}
 	atUri := resp.Uri
 	defer func() {
-		if err := rollbackRecord(context.Background(), atUri, client); err != nil {
+		if err := xrpcclient.RollbackRecord(context.Background(), atUri, client); err != nil {
 			l.Error("rollback failed", "err", err)
 		}
 	}()
@@ -981,7 +981,7 @@ func (rp *Issues) NewIssue(w http.ResponseWriter, r *http.Request) {
 		}
 		rollback := func() {
 			err1 := tx.Rollback()
-			err2 := rollbackRecord(context.Background(), atUri, client)
+			err2 := xrpcclient.RollbackRecord(context.Background(), atUri, client)
 
 			if errors.Is(err1, sql.ErrTxDone) {
 				err1 = nil
@@ -1016,25 +1016,3 @@ func (rp *Issues) NewIssue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
-
-// this is used to rollback changes made to the PDS
-//
-// it is a no-op if the provided ATURI is empty
-func rollbackRecord(ctx context.Context, aturi string, client *atpclient.APIClient) error {
-	if aturi == "" {
-		return nil
-	}
-
-	parsed := syntax.ATURI(aturi)
-
-	collection := parsed.Collection().String()
-	repo := parsed.Authority().String()
-	rkey := parsed.RecordKey().String()
-
-	_, err := comatproto.RepoDeleteRecord(ctx, client, &comatproto.RepoDeleteRecord_Input{
-		Collection: collection,
-		Repo:       repo,
-		Rkey:       rkey,
-	})
-	return err
-}
@@ -16,12 +16,12 @@ import (
 	"tangled.org/core/appview/oauth"
 	"tangled.org/core/appview/pages"
 	"tangled.org/core/appview/validator"
+	"tangled.org/core/appview/xrpcclient"
 	"tangled.org/core/orm"
 	"tangled.org/core/rbac"
 	"tangled.org/core/tid"
 
 	comatproto "github.com/bluesky-social/indigo/api/atproto"
-	atpclient "github.com/bluesky-social/indigo/atproto/client"
 	"github.com/bluesky-social/indigo/atproto/syntax"
 	lexutil "github.com/bluesky-social/indigo/lex/util"
 	"github.com/go-chi/chi/v5"
@@ -217,7 +217,7 @@ func (l *Labels) PerformLabelOp(w http.ResponseWriter, r *http.Request) {
 
 	rollback := func() {
 		err1 := tx.Rollback()
-		err2 := rollbackRecord(context.Background(), atUri, client)
+		err2 := xrpcclient.RollbackRecord(context.Background(), atUri, client)
 
 		// ignore txn complete errors, this is okay
 		if errors.Is(err1, sql.ErrTxDone) {
@@ -247,25 +247,3 @@ func (l *Labels) PerformLabelOp(w http.ResponseWriter, r *http.Request) {
 
 	l.pages.HxRefresh(w)
 }
-
-// this is used to rollback changes made to the PDS
-//
-// it is a no-op if the provided ATURI is empty
-func rollbackRecord(ctx context.Context, aturi string, client *atpclient.APIClient) error {
-	if aturi == "" {
-		return nil
-	}
-
-	parsed := syntax.ATURI(aturi)
-
-	collection := parsed.Collection().String()
-	repo := parsed.Authority().String()
-	rkey := parsed.RecordKey().String()
-
-	_, err := comatproto.RepoDeleteRecord(ctx, client, &comatproto.RepoDeleteRecord_Input{
-		Collection: collection,
-		Repo:       repo,
-		Rkey:       rkey,
-	})
-	return err
-}
@@ -13,6 +13,7 @@ import (
 	"github.com/bluesky-social/indigo/atproto/identity"
 	"github.com/go-chi/chi/v5"
 	"tangled.org/core/appview/db"
+	"tangled.org/core/appview/models"
 	"tangled.org/core/appview/oauth"
 	"tangled.org/core/appview/pages"
 	"tangled.org/core/appview/pagination"
@@ -145,6 +146,21 @@ func (mw Middleware) KnotOwner() middlewareFunc {
 }
 
 func (mw Middleware) RepoPermissionMiddleware(requiredPerm string) middlewareFunc {
+	return mw.repoPermissionMiddleware(func(*models.Repo) string { return requiredPerm })
+}
+
+// RepoVcsPermissionMiddleware is RepoPermissionMiddleware for routes shared by
+// git and pijul repos that are guarded by a different permission in each
+func (mw Middleware) RepoVcsPermissionMiddleware(gitPerm, pijulPerm string) middlewareFunc {
+	return mw.repoPermissionMiddleware(func(f *models.Repo) string {
+		if f.IsPijul() {
+			return pijulPerm
+		}
+		return gitPerm
+	})
+}
+
+func (mw Middleware) repoPermissionMiddleware(permFor func(*models.Repo) string) middlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// requires auth also
@@ -161,6 +177,7 @@ func (mw Middleware) RepoPermissionMiddleware(requiredPerm string) middlewareFun
 				return
 			}
 
+			requiredPerm := permFor(f)
 			ok, err := mw.enforcer.E.Enforce(actor.Active.Did, f.Knot, f.DidSlashRepo(), requiredPerm)
 			if err != nil || !ok {
 				log.Printf("%s does not have perms of a %s in repo %s", actor.Active.Did, requiredPerm, f.DidSlashRepo())
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"crypto/sha1"
 	"fmt"
 	"time"
 
@@ -28,3 +29,9 @@ type Artifact struct {
 func (a *Artifact) ArtifactAt() syntax.ATURI {
 	return syntax.ATURI(fmt.Sprintf("at://%s/%s/%s", a.Did, tangled.RepoArtifactNSID, a.Rkey))
 }
+
+// PijulTagKey derives a git-sized hash from a pijul state, which artifacts
+// of pijul tags are stored under in place of the hash of a tag object
+func PijulTagKey(state string) plumbing.Hash {
+	return plumbing.Hash(sha1.Sum([]byte(state)))
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	spindle "tangled.org/core/spindle/models"
+	"tangled.org/core/types"
+)
+
+// BranchRule lists the workflows that must succeed before pulls targeting
+// the branches matching Pattern can be merged
+type BranchRule struct {
+	ID                int64
+	RepoAt            syntax.ATURI
+	Pattern           string
+	RequiredWorkflows []string
+	Created           time.Time
+}
+
+// Matches reports whether the rule applies to a branch, see
+// types.MatchBranch
+func (r BranchRule) Matches(branch string) bool {
+	return types.MatchBranch(r.Pattern, branch)
+}
+
+func (r BranchRule) Validate() error {
+	if err := types.ValidateBranchPattern(r.Pattern); err != nil {
+		return err
+	}
+	if len(r.RequiredWorkflows) == 0 {
+		return fmt.Errorf("at least one workflow must be required")
+	}
+	return nil
+}
+
+// RequiredWorkflows collects the workflows that the rules matching a branch
+// require, sorted by name
+func RequiredWorkflows(rules []BranchRule, branch string) []string {
+	var workflows []string
+	for _, r := range rules {
+		if r.Matches(branch) {
+			workflows = append(workflows, r.RequiredWorkflows...)
+		}
+	}
+	slices.Sort(workflows)
+	return slices.Compact(workflows)
+}
+
+// RequiredCheck is the state of a required workflow on a commit. The status
+// is empty while no pipeline has reported the workflow yet.
+type RequiredCheck struct {
+	Workflow string
+	Status   spindle.StatusKind
+}
+
+func (c RequiredCheck) IsSuccess() bool {
+	return c.Status == spindle.StatusKindSuccess
+}
+
+func (c RequiredCheck) IsPending() bool {
+	return c.Status == "" || c.Status.IsStart()
+}
+
+func (c RequiredCheck) IsFailing() bool {
+	return c.Status.IsFinish() && !c.IsSuccess()
+}
+
+// ChecksStatus is the state of the workflows a pull must pass before it can
+// be merged
+type ChecksStatus struct {
+	Checks []RequiredCheck
+
+	// NoCommit is set for patch based pulls, which have no commit for
+	// pipelines to run on, so their required workflows can never run
+	NoCommit bool
+}
+
+// NewChecksStatus looks up the required workflows in the pipelines that ran
+// on a commit. When a workflow ran in several pipelines, the most recent one
+// wins.
+func NewChecksStatus(required []string, pipelines []Pipeline) ChecksStatus {
+	pipelines = slices.Clone(pipelines)
+	slices.SortStableFunc(pipelines, func(a, b Pipeline) int {
+		return b.Created.Compare(a.Created)
+	})
+
+	var status ChecksStatus
+	for _, workflow := range required {
+		check := RequiredCheck{Workflow: workflow}
+		for _, p := range pipelines {
+			if w, ok := p.Statuses[workflow]; ok && len(w.Data) > 0 {
+				check.Status = w.Latest().Status
+				break
+			}
+		}
+		status.Checks = append(status.Checks, check)
+	}
+
+	return status
+}
+
+// IsSatisfied reports whether every required workflow succeeded
+func (s ChecksStatus) IsSatisfied() bool {
+	if s.NoCommit && len(s.Checks) > 0 {
+		return false
+	}
+	for _, c := range s.Checks {
+		if !c.IsSuccess() {
+			return false
+		}
+	}
+	return true
+}
+
+func (s ChecksStatus) Pending() []RequiredCheck {
+	var pending []RequiredCheck
+	for _, c := range s.Checks {
+		if c.IsPending() {
+			pending = append(pending, c)
+		}
+	}
+	return pending
+}
+
+func (s ChecksStatus) Failing() []RequiredCheck {
+	var failing []RequiredCheck
+	for _, c := range s.Checks {
+		if c.IsFailing() {
+			failing = append(failing, c)
+		}
+	}
+	return failing
+}
+
+// Explain describes why the required workflows keep a pull from being
+// merged. It is empty if they all succeeded.
+func (s ChecksStatus) Explain() string {
+	if s.NoCommit && len(s.Checks) > 0 {
+		return "required checks cannot run on patch-based pulls"
+	}
+
+	var reasons []string
+
+	if failing := s.Failing(); len(failing) > 0 {
+		var names []string
+		for _, c := range failing {
+			names = append(names, fmt.Sprintf("%s (%s)", c.Workflow, c.Status))
+		}
+		reasons = append(reasons, fmt.Sprintf("required workflows did not succeed: %s", strings.Join(names, ", ")))
+	}
+
+	if pending := s.Pending(); len(pending) > 0 {
+		var names []string
+		for _, c := range pending {
+			names = append(names, c.Workflow)
+		}
+		reasons = append(reasons, fmt.Sprintf("waiting for required workflows: %s", strings.Join(names, ", ")))
+	}
+
+	return strings.Join(reasons, "; ")
+}
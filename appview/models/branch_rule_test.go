@@ -0,0 +1,93 @@
+package models
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	spindle "tangled.org/core/spindle/models"
+)
+
+func TestRequiredWorkflows(t *testing.T) {
+	rules := []BranchRule{
+		{Pattern: "main", RequiredWorkflows: []string{"test.yml", "build.yml"}},
+		{Pattern: "release/*", RequiredWorkflows: []string{"release.yml"}},
+		{Pattern: "*", RequiredWorkflows: []string{"build.yml"}},
+	}
+
+	tests := []struct {
+		branch string
+		want   []string
+	}{
+		{"main", []string{"build.yml", "test.yml"}},
+		{"release/v1", []string{"release.yml"}},
+		{"feature", []string{"build.yml"}},
+	}
+
+	for _, tt := range tests {
+		if got := RequiredWorkflows(rules, tt.branch); !slices.Equal(got, tt.want) {
+			t.Errorf("RequiredWorkflows(%q) = %v, want %v", tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestNewChecksStatus(t *testing.T) {
+	workflow := func(kinds ...spindle.StatusKind) WorkflowStatus {
+		var w WorkflowStatus
+		for _, k := range kinds {
+			w.Data = append(w.Data, PipelineStatus{Status: k})
+		}
+		return w
+	}
+
+	older := Pipeline{
+		Created: time.Unix(0, 0),
+		Statuses: map[string]WorkflowStatus{
+			"build.yml": workflow(spindle.StatusKindPending, spindle.StatusKindFailed),
+			"lint.yml":  workflow(spindle.StatusKindPending, spindle.StatusKindSuccess),
+		},
+	}
+	newer := Pipeline{
+		Created: time.Unix(60, 0),
+		Statuses: map[string]WorkflowStatus{
+			"build.yml": workflow(spindle.StatusKindPending, spindle.StatusKindSuccess),
+			"test.yml":  workflow(spindle.StatusKindPending, spindle.StatusKindRunning),
+		},
+	}
+
+	status := NewChecksStatus([]string{"build.yml", "lint.yml", "test.yml", "docs.yml"}, []Pipeline{older, newer})
+
+	want := []RequiredCheck{
+		{Workflow: "build.yml", Status: spindle.StatusKindSuccess},
+		{Workflow: "lint.yml", Status: spindle.StatusKindSuccess},
+		{Workflow: "test.yml", Status: spindle.StatusKindRunning},
+		{Workflow: "docs.yml"},
+	}
+	if !slices.Equal(status.Checks, want) {
+		t.Fatalf("checks = %v, want %v", status.Checks, want)
+	}
+	if status.IsSatisfied() {
+		t.Error("expected pending workflows to block the merge")
+	}
+	if got := len(status.Pending()); got != 2 {
+		t.Errorf("pending = %d, want 2", got)
+	}
+
+	failed := NewChecksStatus([]string{"build.yml"}, []Pipeline{older})
+	if got := failed.Explain(); got != "required workflows did not succeed: build.yml (failed)" {
+		t.Errorf("explain = %q", got)
+	}
+
+	if !NewChecksStatus(nil, nil).IsSatisfied() {
+		t.Error("no required workflows should be satisfied")
+	}
+
+	patch := NewChecksStatus([]string{"build.yml"}, nil)
+	patch.NoCommit = true
+	if patch.IsSatisfied() {
+		t.Error("expected required workflows to block a patch based pull")
+	}
+	if got := patch.Explain(); got != "required checks cannot run on patch-based pulls" {
+		t.Errorf("explain = %q", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/bluesky-social/indigo/atproto/syntax"
+	"tangled.org/core/api/tangled"
 )
 
 // DiscussionState represents the state of a discussion
@@ -30,6 +31,20 @@ func (s DiscussionState) String() string {
 	}
 }
 
+// ParseDiscussionState is the inverse of DiscussionState.String
+func ParseDiscussionState(s string) (DiscussionState, error) {
+	switch s {
+	case "open":
+		return DiscussionOpen, nil
+	case "merged":
+		return DiscussionMerged, nil
+	case "closed":
+		return DiscussionClosed, nil
+	default:
+		return DiscussionClosed, fmt.Errorf("unknown discussion state: %q", s)
+	}
+}
+
 func (s DiscussionState) IsOpen() bool   { return s == DiscussionOpen }
 func (s DiscussionState) IsMerged() bool { return s == DiscussionMerged }
 func (s DiscussionState) IsClosed() bool { return s == DiscussionClosed }
@@ -49,6 +64,8 @@ type Discussion struct {
 	Body          string
 	TargetChannel string
 	State         DiscussionState
+	Mentions      []syntax.DID
+	References    []syntax.ATURI
 
 	// meta
 	Created time.Time
@@ -67,6 +84,70 @@ func (d *Discussion) AtUri() syntax.ATURI {
 	return syntax.ATURI(fmt.Sprintf("at://%s/%s/%s", d.Did, DiscussionNSID, d.Rkey))
 }
 
+func (d *Discussion) AsRecord() tangled.RepoDiscussion {
+	mentions := make([]string, len(d.Mentions))
+	for i, did := range d.Mentions {
+		mentions[i] = string(did)
+	}
+	references := make([]string, len(d.References))
+	for i, uri := range d.References {
+		references[i] = string(uri)
+	}
+	return tangled.RepoDiscussion{
+		Repo:          d.RepoAt.String(),
+		Title:         d.Title,
+		Body:          &d.Body,
+		TargetChannel: &d.TargetChannel,
+		Mentions:      mentions,
+		References:    references,
+		CreatedAt:     d.Created.Format(time.RFC3339),
+	}
+}
+
+func DiscussionFromRecord(did, rkey string, record tangled.RepoDiscussion) Discussion {
+	created, err := time.Parse(time.RFC3339, record.CreatedAt)
+	if err != nil {
+		created = time.Now()
+	}
+
+	body := ""
+	if record.Body != nil {
+		body = *record.Body
+	}
+
+	targetChannel := "main"
+	if record.TargetChannel != nil && *record.TargetChannel != "" {
+		targetChannel = *record.TargetChannel
+	}
+
+	mentions, references := parseMentions(record.Mentions, record.References)
+
+	return Discussion{
+		RepoAt:        syntax.ATURI(record.Repo),
+		Did:           did,
+		Rkey:          rkey,
+		Created:       created,
+		Title:         record.Title,
+		Body:          body,
+		TargetChannel: targetChannel,
+		State:         DiscussionOpen, // new discussions are open by default
+		Mentions:      mentions,
+		References:    references,
+	}
+}
+
+func parseMentions(dids, uris []string) ([]syntax.DID, []syntax.ATURI) {
+	mentions := make([]syntax.DID, len(dids))
+	for i, did := range dids {
+		mentions[i] = syntax.DID(did)
+	}
+	references := make([]syntax.ATURI, len(uris))
+	for i, uri := range uris {
+		references[i] = syntax.ATURI(uri)
+	}
+	return mentions, references
+}
+
 // ActivePatches returns only the patches that haven't been removed
 func (d *Discussion) ActivePatches() []*DiscussionPatch {
 	var active []*DiscussionPatch
@@ -156,10 +237,18 @@ func (d *Discussion) TotalComments() int {
 	return len(d.Comments)
 }
 
+// States of a patch, as published in sh.tangled.repo.discussion.patch.state
+// records
+const (
+	DiscussionPatchActive  = "active"
+	DiscussionPatchRemoved = "removed"
+)
+
 // DiscussionPatch represents a patch added to a discussion
 // Key difference from PullSubmission: it has pushed_by_did
 type DiscussionPatch struct {
 	Id           int64
+	Rkey         string
 	DiscussionAt syntax.ATURI
 	PushedByDid  string
 	PatchHash    string
@@ -168,6 +257,41 @@ type DiscussionPatch struct {
 	Removed      *time.Time
 }
 
+// AtUri returns the uri of the record the patch was added with
+func (p *DiscussionPatch) AtUri() syntax.ATURI {
+	return syntax.ATURI(fmt.Sprintf("at://%s/%s/%s", p.PushedByDid, tangled.RepoDiscussionPatchNSID, p.Rkey))
+}
+
+func (p *DiscussionPatch) AsRecord() tangled.RepoDiscussionPatch {
+	return tangled.RepoDiscussionPatch{
+		Discussion: p.DiscussionAt.String(),
+		PatchHash:  p.PatchHash,
+		Patch:      p.Patch,
+		CreatedAt:  p.Added.Format(time.RFC3339),
+	}
+}
+
+func DiscussionPatchFromRecord(did, rkey string, record tangled.RepoDiscussionPatch) (*DiscussionPatch, error) {
+	discussionAt, err := syntax.ParseATURI(record.Discussion)
+	if err != nil {
+		return nil, err
+	}
+
+	added, err := time.Parse(time.RFC3339, record.CreatedAt)
+	if err != nil {
+		added = time.Now()
+	}
+
+	return &DiscussionPatch{
+		Rkey:         rkey,
+		DiscussionAt: discussionAt,
+		PushedByDid:  did,
+		PatchHash:    record.PatchHash,
+		Patch:        record.Patch,
+		Added:        added,
+	}, nil
+}
+
 // IsActive returns true if the patch hasn't been removed
 func (p *DiscussionPatch) IsActive() bool {
 	return p.Removed == nil
@@ -192,6 +316,8 @@ type DiscussionComment struct {
 	Created      time.Time
 	Edited       *time.Time
 	Deleted      *time.Time
+	Mentions     []syntax.DID
+	References   []syntax.ATURI
 }
 
 const DiscussionCommentNSID = "sh.tangled.repo.discussion.comment"
@@ -200,6 +326,54 @@ func (c *DiscussionComment) AtUri() syntax.ATURI {
 	return syntax.ATURI(fmt.Sprintf("at://%s/%s/%s", c.Did, DiscussionCommentNSID, c.Rkey))
 }
 
+func (c *DiscussionComment) AsRecord() tangled.RepoDiscussionComment {
+	mentions := make([]string, len(c.Mentions))
+	for i, did := range c.Mentions {
+		mentions[i] = string(did)
+	}
+	references := make([]string, len(c.References))
+	for i, uri := range c.References {
+		references[i] = string(uri)
+	}
+	return tangled.RepoDiscussionComment{
+		Discussion: c.DiscussionAt,
+		Body:       c.Body,
+		ReplyTo:    c.ReplyTo,
+		Mentions:   mentions,
+		References: references,
+		CreatedAt:  c.Created.Format(time.RFC3339),
+	}
+}
+
+func DiscussionCommentFromRecord(did, rkey string, record tangled.RepoDiscussionComment) (*DiscussionComment, error) {
+	created, err := time.Parse(time.RFC3339, record.CreatedAt)
+	if err != nil {
+		created = time.Now()
+	}
+
+	if _, err = syntax.ParseATURI(record.Discussion); err != nil {
+		return nil, err
+	}
+	if record.ReplyTo != nil {
+		if _, err = syntax.ParseATURI(*record.ReplyTo); err != nil {
+			return nil, err
+		}
+	}
+
+	mentions, references := parseMentions(record.Mentions, record.References)
+
+	return &DiscussionComment{
+		Did:          did,
+		Rkey:         rkey,
+		DiscussionAt: record.Discussion,
+		ReplyTo:      record.ReplyTo,
+		Body:         record.Body,
+		Created:      created,
+		Mentions:     mentions,
+		References:   references,
+	}, nil
+}
+
 func (c *DiscussionComment) IsTopLevel() bool {
 	return c.ReplyTo == nil
 }
@@ -21,6 +21,13 @@ const (
 	NotificationTypePullClosed     NotificationType = "pull_closed"
 	NotificationTypePullReopen     NotificationType = "pull_reopen"
 	NotificationTypeUserMentioned  NotificationType = "user_mentioned"
+
+	NotificationTypeDiscussionCreated    NotificationType = "discussion_created"
+	NotificationTypeDiscussionCommented  NotificationType = "discussion_commented"
+	NotificationTypeDiscussionPatchAdded NotificationType = "discussion_patch_added"
+	NotificationTypeDiscussionMerged     NotificationType = "discussion_merged"
+	NotificationTypeDiscussionClosed     NotificationType = "discussion_closed"
+	NotificationTypeDiscussionReopen     NotificationType = "discussion_reopen"
 )
 
 type Notification struct {
@@ -66,6 +73,18 @@ func (n *Notification) Icon() string {
 		return "user-plus"
 	case NotificationTypeUserMentioned:
 		return "at-sign"
+	case NotificationTypeDiscussionCreated:
+		return "message-circle"
+	case NotificationTypeDiscussionCommented:
+		return "message-square"
+	case NotificationTypeDiscussionPatchAdded:
+		return "file-diff"
+	case NotificationTypeDiscussionMerged:
+		return "git-merge"
+	case NotificationTypeDiscussionClosed:
+		return "ban"
+	case NotificationTypeDiscussionReopen:
+		return "message-circle"
 	default:
 		return ""
 	}
@@ -73,9 +92,10 @@ func (n *Notification) Icon() string {
 
 type NotificationWithEntity struct {
 	*Notification
-	Repo  *Repo
-	Issue *Issue
-	Pull  *Pull
+	Repo       *Repo
+	Issue      *Issue
+	Pull       *Pull
+	Discussion *Discussion
 }
 
 type NotificationPreferences struct {
@@ -119,6 +139,18 @@ func (prefs *NotificationPreferences) ShouldNotify(t NotificationType) bool {
 		return prefs.Followed
 	case NotificationTypeUserMentioned:
 		return prefs.UserMentioned
+	case NotificationTypeDiscussionCreated:
+		return prefs.PullCreated // same pref for now
+	case NotificationTypeDiscussionCommented:
+		return prefs.PullCommented // same pref for now
+	case NotificationTypeDiscussionPatchAdded:
+		return prefs.PullCreated // same pref for now
+	case NotificationTypeDiscussionMerged:
+		return prefs.PullMerged // same pref for now
+	case NotificationTypeDiscussionClosed:
+		return prefs.PullMerged // same pref for now
+	case NotificationTypeDiscussionReopen:
+		return prefs.PullCreated // same pref for now
 	default:
 		return false
 	}
@@ -140,6 +140,12 @@ type Trigger struct {
 	PRTargetBranch *string
 	PRSourceSha    *string
 	PRAction       *string
+
+	// discussion trigger fields
+	DiscussionAt      *string
+	DiscussionChannel *string
+	DiscussionPatch   *string
+	DiscussionAction  *string
 }
 
 func (t *Trigger) IsPush() bool {
@@ -150,11 +156,17 @@ func (t *Trigger) IsPullRequest() bool {
 	return t != nil && t.Kind == workflow.TriggerKindPullRequest
 }
 
+func (t *Trigger) IsDiscussion() bool {
+	return t != nil && t.Kind == workflow.TriggerKindDiscussion
+}
+
 func (t *Trigger) TargetRef() string {
 	if t.IsPush() {
 		return plumbing.ReferenceName(*t.PushRef).Short()
 	} else if t.IsPullRequest() {
 		return *t.PRTargetBranch
+	} else if t.IsDiscussion() {
+		return *t.DiscussionChannel
 	}
 
 	return ""
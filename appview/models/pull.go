@@ -163,6 +163,12 @@ type PullComment struct {
 	Mentions   []syntax.DID
 	References []syntax.ATURI
 
+	// review threads: the root of a thread is anchored to lines of a
+	// submission, and replies point back to the root
+	ReplyTo  string
+	Resolved *bool
+	Anchors  []PullCommentAnchor // one per round the thread was carried to, oldest first
+
 	// meta
 	Created time.Time
 }
@@ -171,6 +177,99 @@ func (p *PullComment) AtUri() syntax.ATURI {
 	return syntax.ATURI(p.CommentAt)
 }
 
+// IsReview reports whether the comment belongs to a review thread
+func (p PullComment) IsReview() bool {
+	return p.ReplyTo != "" || len(p.Anchors) > 0
+}
+
+// Anchor is where the comment started its review thread
+func (p PullComment) Anchor() *PullCommentAnchor {
+	if len(p.Anchors) == 0 {
+		return nil
+	}
+	return &p.Anchors[0]
+}
+
+// AnchorAt is where the review thread of the comment is on a round, if it
+// was carried to that round
+func (p PullComment) AnchorAt(round int) *PullCommentAnchor {
+	for i := range p.Anchors {
+		if p.Anchors[i].Round == round {
+			return &p.Anchors[i]
+		}
+	}
+	return nil
+}
+
+type PullCommentSide string
+
+const (
+	PullCommentSideOld PullCommentSide = "old"
+	PullCommentSideNew PullCommentSide = "new"
+)
+
+func (s PullCommentSide) IsValid() bool {
+	return s == PullCommentSideOld || s == PullCommentSideNew
+}
+
+// PullCommentAnchor is a range of lines of a file in the patch of a round
+type PullCommentAnchor struct {
+	Round     int
+	Path      string
+	Side      PullCommentSide
+	StartLine int
+	EndLine   int
+
+	// the lines changed in this round, so the thread no longer points at
+	// what was reviewed
+	Outdated bool
+}
+
+// LineId is the id of the last anchored line in the rendered diff
+func (a PullCommentAnchor) LineId() string {
+	if a.Side == PullCommentSideOld {
+		return fmt.Sprintf("%s-O%d", a.Path, a.EndLine)
+	}
+	return fmt.Sprintf("%s-N%d", a.Path, a.EndLine)
+}
+
+func (a PullCommentAnchor) String() string {
+	lines := fmt.Sprintf("L%d", a.StartLine)
+	if a.EndLine != a.StartLine {
+		lines = fmt.Sprintf("L%d-%d", a.StartLine, a.EndLine)
+	}
+	return fmt.Sprintf("%s:%s (%s)", a.Path, lines, a.Side)
+}
+
+// PullReviewThread is an anchored comment along with its replies
+type PullReviewThread struct {
+	Root    PullComment
+	Replies []PullComment
+}
+
+// IsResolved reports whether the latest reply that resolved or reopened the
+// thread resolved it
+func (t PullReviewThread) IsResolved() bool {
+	for i := len(t.Replies) - 1; i >= 0; i-- {
+		if r := t.Replies[i].Resolved; r != nil {
+			return *r
+		}
+	}
+	return false
+}
+
+// Comments are the root and replies of the thread that have a body, as
+// replies that only resolve or reopen a thread may not
+func (t PullReviewThread) Comments() []PullComment {
+	comments := []PullComment{t.Root}
+	for _, r := range t.Replies {
+		if r.Body != "" {
+			comments = append(comments, r)
+		}
+	}
+	return comments
+}
+
 func (p *Pull) TotalComments() int {
 	total := 0
 	for _, s := range p.Submissions {
@@ -179,6 +278,61 @@ func (p *Pull) TotalComments() int {
 	return total
 }
 
+// ReviewThreads are the review threads of the pull, ordered by when they
+// were started
+func (p *Pull) ReviewThreads() []PullReviewThread {
+	var threads []PullReviewThread
+	index := make(map[string]int)
+
+	for _, s := range p.Submissions {
+		for _, c := range s.Comments {
+			if len(c.Anchors) > 0 && c.ReplyTo == "" {
+				index[c.CommentAt] = len(threads)
+				threads = append(threads, PullReviewThread{Root: c})
+			}
+		}
+	}
+
+	for _, s := range p.Submissions {
+		for _, c := range s.Comments {
+			if i, ok := index[c.ReplyTo]; ok {
+				threads[i].Replies = append(threads[i].Replies, c)
+			}
+		}
+	}
+
+	// replies can be made on any round, so they are interleaved by time
+	for i := range threads {
+		slices.SortFunc(threads[i].Replies, func(a, b PullComment) int {
+			return a.Created.Compare(b.Created)
+		})
+	}
+
+	return threads
+}
+
+// ReviewThread finds the review thread started by a comment
+func (p *Pull) ReviewThread(rootAt string) (PullReviewThread, bool) {
+	for _, t := range p.ReviewThreads() {
+		if t.Root.CommentAt == rootAt {
+			return t, true
+		}
+	}
+	return PullReviewThread{}, false
+}
+
+// ReviewThreadsAt groups the review threads carried to a round by the path
+// of the file they are anchored to
+func (p *Pull) ReviewThreadsAt(round int) map[string][]PullReviewThread {
+	byPath := make(map[string][]PullReviewThread)
+	for _, t := range p.ReviewThreads() {
+		if a := t.Root.AnchorAt(round); a != nil {
+			byPath[a.Path] = append(byPath[a.Path], t)
+		}
+	}
+	return byPath
+}
+
 func (p *Pull) LastRoundNumber() int {
 	return len(p.Submissions) - 1
 }
@@ -229,6 +383,12 @@ func (p *Pull) IsStacked() bool {
 	return p.StackId != ""
 }
 
+// CanFastForward reports whether the target branch can be moved to the source
+// branch, which requires the source branch to be in the same repo
+func (p *Pull) CanFastForward() bool {
+	return p.IsBranchBased() && !p.IsStacked()
+}
+
 func (p *Pull) Participants() []string {
 	participantSet := make(map[string]struct{})
 	participants := []string{}
@@ -251,6 +411,30 @@ func (p *Pull) Participants() []string {
 	return participants
 }
 
+// TopLevelComments are the comments on the submission that are not part of
+// a review thread
+func (s PullSubmission) TopLevelComments() []PullComment {
+	var comments []PullComment
+	for _, c := range s.Comments {
+		if !c.IsReview() {
+			comments = append(comments, c)
+		}
+	}
+	return comments
+}
+
+// ReviewThreadRoots are the comments that started a review thread on the
+// submission
+func (s PullSubmission) ReviewThreadRoots() []PullComment {
+	var roots []PullComment
+	for _, c := range s.Comments {
+		if c.ReplyTo == "" && len(c.Anchors) > 0 {
+			roots = append(roots, c)
+		}
+	}
+	return roots
+}
+
 func (s PullSubmission) IsFormatPatch() bool {
 	return patchutil.IsFormatPatch(s.Patch)
 }
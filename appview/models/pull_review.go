@@ -0,0 +1,239 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/types"
+)
+
+type PullReviewState string
+
+const (
+	PullReviewApprove        PullReviewState = "approve"
+	PullReviewRequestChanges PullReviewState = "requestChanges"
+	PullReviewComment        PullReviewState = "comment"
+)
+
+func (s PullReviewState) IsValid() bool {
+	switch s {
+	case PullReviewApprove, PullReviewRequestChanges, PullReviewComment:
+		return true
+	}
+	return false
+}
+
+// IsVerdict reports whether the review approves or requests changes, as
+// opposed to only commenting
+func (s PullReviewState) IsVerdict() bool {
+	return s == PullReviewApprove || s == PullReviewRequestChanges
+}
+
+func (s PullReviewState) String() string {
+	switch s {
+	case PullReviewApprove:
+		return "approved"
+	case PullReviewRequestChanges:
+		return "requested changes"
+	default:
+		return "commented"
+	}
+}
+
+// PullReview is the verdict of a reviewer on one round of a pull. Each
+// reviewer has at most one review per round.
+type PullReview struct {
+	// ids
+	ID     int64
+	Did    string
+	Rkey   string
+	PullAt syntax.ATURI
+
+	// content
+	Round int
+	State PullReviewState
+	Body  string
+
+	// meta
+	Created time.Time
+}
+
+func (r PullReview) AtUri() syntax.ATURI {
+	return syntax.ATURI(fmt.Sprintf("at://%s/%s/%s", r.Did, tangled.RepoPullReviewNSID, r.Rkey))
+}
+
+func (r PullReview) AsRecord() tangled.RepoPullReview {
+	var body *string
+	if r.Body != "" {
+		body = &r.Body
+	}
+	return tangled.RepoPullReview{
+		Pull:      r.PullAt.String(),
+		Round:     int64(r.Round),
+		State:     string(r.State),
+		Body:      body,
+		CreatedAt: r.Created.Format(time.RFC3339),
+	}
+}
+
+// PullSettings are the rules a repo sets for merging its pulls
+type PullSettings struct {
+	RepoAt syntax.ATURI
+
+	// approvals from collaborators needed before a pull can be merged
+	RequiredApprovals int
+
+	// strategies pulls may be merged with, and the one offered first
+	MergeStrategies      []types.MergeStrategy
+	DefaultMergeStrategy types.MergeStrategy
+}
+
+// DefaultPullSettings are the settings of repos that did not configure pulls:
+// no approvals are required, every strategy is allowed and commits are
+// rebased by default
+func DefaultPullSettings(repoAt syntax.ATURI) PullSettings {
+	return PullSettings{
+		RepoAt:               repoAt,
+		MergeStrategies:      slices.Clone(types.MergeStrategies),
+		DefaultMergeStrategy: types.MergeStrategyRebase,
+	}
+}
+
+func (s PullSettings) AllowsMergeStrategy(strategy types.MergeStrategy) bool {
+	return slices.Contains(s.MergeStrategies, strategy)
+}
+
+// MergeStrategiesFor lists the allowed strategies a pull can be merged with.
+// Only pulls from a branch of the repo can be fast-forwarded, as the knot
+// has nothing but the patch of the others.
+func (s PullSettings) MergeStrategiesFor(pull *Pull) []types.MergeStrategy {
+	var strategies []types.MergeStrategy
+	for _, strategy := range s.MergeStrategies {
+		if strategy == types.MergeStrategyFastForward && !pull.CanFastForward() {
+			continue
+		}
+		strategies = append(strategies, strategy)
+	}
+	return strategies
+}
+
+// DefaultMergeStrategyFor is the strategy offered first for a pull: the
+// default of the repo when the pull can be merged with it, or else the first
+// strategy it can be merged with
+func (s PullSettings) DefaultMergeStrategyFor(pull *Pull) types.MergeStrategy {
+	strategies := s.MergeStrategiesFor(pull)
+	if slices.Contains(strategies, s.DefaultMergeStrategy) || len(strategies) == 0 {
+		return s.DefaultMergeStrategy
+	}
+	return strategies[0]
+}
+
+// Validate checks that the settings allow at least one known strategy and
+// that the default is one of them
+func (s PullSettings) Validate() error {
+	if s.RequiredApprovals < 0 {
+		return fmt.Errorf("required approvals must be zero or a positive number")
+	}
+	if len(s.MergeStrategies) == 0 {
+		return fmt.Errorf("at least one merge strategy must be allowed")
+	}
+	for _, strategy := range s.MergeStrategies {
+		if !strategy.IsValid() {
+			return fmt.Errorf("unknown merge strategy: %s", strategy)
+		}
+	}
+	if !s.AllowsMergeStrategy(s.DefaultMergeStrategy) {
+		return fmt.Errorf("the default merge strategy must be allowed")
+	}
+	return nil
+}
+
+// ReviewStatus tallies the reviews of a pull against the approvals its repo
+// requires
+type ReviewStatus struct {
+	Required int
+
+	// reviewers that approved the latest round
+	Approvals []PullReview
+
+	// reviewers whose latest verdict, on any round, requests changes
+	ChangeRequests []PullReview
+}
+
+// NewReviewStatus computes the review status of a pull. Only the verdicts
+// of reviewers for which counts returns true are considered; the author of
+// a pull can never review it.
+func NewReviewStatus(pull *Pull, reviews []PullReview, settings PullSettings, counts func(did string) bool) ReviewStatus {
+	status := ReviewStatus{
+		Required: settings.RequiredApprovals,
+	}
+
+	// the latest verdict of each reviewer; reviews only commenting do not
+	// change a verdict
+	latest := make(map[string]PullReview)
+	var order []string
+	for _, r := range reviews {
+		if r.Did == pull.OwnerDid || !r.State.IsVerdict() || !counts(r.Did) {
+			continue
+		}
+		prev, ok := latest[r.Did]
+		if !ok {
+			order = append(order, r.Did)
+		}
+		if !ok || r.Round > prev.Round || (r.Round == prev.Round && r.Created.After(prev.Created)) {
+			latest[r.Did] = r
+		}
+	}
+
+	for _, did := range order {
+		r := latest[did]
+		switch {
+		case r.State == PullReviewRequestChanges:
+			status.ChangeRequests = append(status.ChangeRequests, r)
+		case r.Round == pull.LastRoundNumber():
+			// approvals of earlier rounds do not carry over to resubmissions
+			status.Approvals = append(status.Approvals, r)
+		}
+	}
+
+	return status
+}
+
+// IsSatisfied reports whether the pull has enough approvals and no
+// outstanding change requests
+func (s ReviewStatus) IsSatisfied() bool {
+	return len(s.ChangeRequests) == 0 && len(s.Approvals) >= s.Required
+}
+
+// MissingApprovals is the number of approvals still needed
+func (s ReviewStatus) MissingApprovals() int {
+	return max(s.Required-len(s.Approvals), 0)
+}
+
+// Explain describes why a pull cannot be merged yet, using name to display
+// reviewers. It is empty if the review requirements are met.
+func (s ReviewStatus) Explain(name func(did string) string) string {
+	var reasons []string
+
+	if n := s.MissingApprovals(); n > 0 {
+		approvals := "approvals"
+		if s.Required == 1 {
+			approvals = "approval"
+		}
+		reasons = append(reasons, fmt.Sprintf("%d of %d required %s on the latest round", len(s.Approvals), s.Required, approvals))
+	}
+
+	if len(s.ChangeRequests) > 0 {
+		var names []string
+		for _, r := range s.ChangeRequests {
+			names = append(names, name(r.Did))
+		}
+		reasons = append(reasons, fmt.Sprintf("changes requested by %s", strings.Join(names, ", ")))
+	}
+
+	return strings.Join(reasons, "; ")
+}
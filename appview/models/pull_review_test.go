@@ -0,0 +1,180 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"tangled.org/core/types"
+)
+
+func TestNewReviewStatus(t *testing.T) {
+	pull := &Pull{
+		OwnerDid:    "did:plc:author",
+		Submissions: []*PullSubmission{{RoundNumber: 0}, {RoundNumber: 1}},
+	}
+	everyone := func(string) bool { return true }
+	at := func(minutes int) time.Time { return time.Unix(0, 0).Add(time.Duration(minutes) * time.Minute) }
+
+	tests := []struct {
+		name      string
+		reviews   []PullReview
+		required  int
+		counts    func(string) bool
+		approvals int
+		requests  int
+		satisfied bool
+	}{
+		{
+			name:      "nothing required",
+			satisfied: true,
+		},
+		{
+			name:     "approval on the latest round",
+			required: 1,
+			reviews: []PullReview{
+				{Did: "did:plc:a", Round: 1, State: PullReviewApprove},
+			},
+			approvals: 1,
+			satisfied: true,
+		},
+		{
+			name:     "approvals do not carry over to a new round",
+			required: 1,
+			reviews: []PullReview{
+				{Did: "did:plc:a", Round: 0, State: PullReviewApprove},
+			},
+		},
+		{
+			name:     "the author cannot approve",
+			required: 1,
+			reviews: []PullReview{
+				{Did: "did:plc:author", Round: 1, State: PullReviewApprove},
+			},
+		},
+		{
+			name:     "only collaborators count",
+			required: 1,
+			counts:   func(did string) bool { return did == "did:plc:b" },
+			reviews: []PullReview{
+				{Did: "did:plc:a", Round: 1, State: PullReviewApprove},
+			},
+		},
+		{
+			name: "change requests outlast new rounds",
+			reviews: []PullReview{
+				{Did: "did:plc:a", Round: 0, State: PullReviewRequestChanges},
+			},
+			requests: 1,
+		},
+		{
+			name: "a later approval clears a change request",
+			reviews: []PullReview{
+				{Did: "did:plc:a", Round: 0, State: PullReviewRequestChanges},
+				{Did: "did:plc:a", Round: 1, State: PullReviewApprove},
+			},
+			approvals: 1,
+			satisfied: true,
+		},
+		{
+			name: "comments do not clear a change request",
+			reviews: []PullReview{
+				{Did: "did:plc:a", Round: 1, State: PullReviewRequestChanges, Created: at(1)},
+				{Did: "did:plc:a", Round: 1, State: PullReviewComment, Created: at(2)},
+			},
+			requests: 1,
+		},
+		{
+			name:     "a change request blocks despite approvals",
+			required: 1,
+			reviews: []PullReview{
+				{Did: "did:plc:a", Round: 1, State: PullReviewApprove},
+				{Did: "did:plc:b", Round: 1, State: PullReviewRequestChanges},
+			},
+			approvals: 1,
+			requests:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts := tt.counts
+			if counts == nil {
+				counts = everyone
+			}
+
+			status := NewReviewStatus(pull, tt.reviews, PullSettings{RequiredApprovals: tt.required}, counts)
+			if len(status.Approvals) != tt.approvals {
+				t.Errorf("approvals = %d, want %d", len(status.Approvals), tt.approvals)
+			}
+			if len(status.ChangeRequests) != tt.requests {
+				t.Errorf("change requests = %d, want %d", len(status.ChangeRequests), tt.requests)
+			}
+			if status.IsSatisfied() != tt.satisfied {
+				t.Errorf("satisfied = %v, want %v", status.IsSatisfied(), tt.satisfied)
+			}
+			if explained := status.Explain(func(did string) string { return did }); (explained == "") != tt.satisfied {
+				t.Errorf("explanation %q does not match satisfied = %v", explained, tt.satisfied)
+			}
+		})
+	}
+}
+
+func TestPullSettingsValidate(t *testing.T) {
+	valid := DefaultPullSettings("")
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("default settings are invalid: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		settings PullSettings
+	}{
+		{
+			name:     "negative approvals",
+			settings: PullSettings{RequiredApprovals: -1, MergeStrategies: []types.MergeStrategy{types.MergeStrategyRebase}, DefaultMergeStrategy: types.MergeStrategyRebase},
+		},
+		{
+			name:     "no strategies",
+			settings: PullSettings{DefaultMergeStrategy: types.MergeStrategyRebase},
+		},
+		{
+			name:     "unknown strategy",
+			settings: PullSettings{MergeStrategies: []types.MergeStrategy{"octopus"}, DefaultMergeStrategy: "octopus"},
+		},
+		{
+			name:     "default not allowed",
+			settings: PullSettings{MergeStrategies: []types.MergeStrategy{types.MergeStrategySquash}, DefaultMergeStrategy: types.MergeStrategyRebase},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.settings.Validate(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestMergeStrategiesFor(t *testing.T) {
+	settings := PullSettings{
+		MergeStrategies:      []types.MergeStrategy{types.MergeStrategyFastForward, types.MergeStrategySquash},
+		DefaultMergeStrategy: types.MergeStrategyFastForward,
+	}
+
+	branchPull := &Pull{PullSource: &PullSource{Branch: "feature"}}
+	if got := settings.MergeStrategiesFor(branchPull); len(got) != 2 {
+		t.Errorf("branch pulls should be offered every strategy, got %v", got)
+	}
+	if got := settings.DefaultMergeStrategyFor(branchPull); got != types.MergeStrategyFastForward {
+		t.Errorf("expected the repo default for a branch pull, got %s", got)
+	}
+
+	patchPull := &Pull{}
+	if got := settings.MergeStrategiesFor(patchPull); len(got) != 1 || got[0] != types.MergeStrategySquash {
+		t.Errorf("patch pulls cannot be fast-forwarded, got %v", got)
+	}
+	if got := settings.DefaultMergeStrategyFor(patchPull); got != types.MergeStrategySquash {
+		t.Errorf("expected the first strategy the pull can use, got %s", got)
+	}
+}
@@ -164,3 +164,42 @@ func (b BlobView) IsUnsupported() bool {
 func (b BlobView) ShowingText() bool {
 	return !b.ShowingRendered
 }
+
+// CreditHunk is a run of consecutive lines of a pijul file that were
+// introduced by the same change
+type CreditHunk struct {
+	Change string
+	Author string
+	When   time.Time
+	Lines  []CreditLine
+}
+
+type CreditLine struct {
+	Number  int
+	Content string
+}
+
+// NewCreditHunks groups credited lines into hunks of consecutive lines
+// introduced by the same change
+func NewCreditHunks(lines []*tangled.RepoPijulCredit_Line) []CreditHunk {
+	var hunks []CreditHunk
+	for _, l := range lines {
+		if len(hunks) == 0 || hunks[len(hunks)-1].Change != l.Change {
+			hunk := CreditHunk{Change: l.Change}
+			if l.Author != nil {
+				hunk.Author = *l.Author
+			}
+			if l.Timestamp != nil {
+				hunk.When, _ = time.Parse(time.RFC3339, *l.Timestamp)
+			}
+			hunks = append(hunks, hunk)
+		}
+
+		last := &hunks[len(hunks)-1]
+		last.Lines = append(last.Lines, CreditLine{
+			Number:  int(l.Line),
+			Content: l.Content,
+		})
+	}
+	return hunks
+}
@@ -18,6 +18,15 @@ type PullSearchOptions struct {
 	Page pagination.Page
 }
 
+type DiscussionSearchOptions struct {
+	Keyword string
+	RepoAt  string
+	State   DiscussionState
+	Labels  []string
+
+	Page pagination.Page
+}
+
 // func (so *SearchOptions) ToFilters() []filter {
 // 	var filters []filter
 // 	if so.IsOpen != nil {
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"fmt"
 	"slices"
 
 	"github.com/bluesky-social/indigo/atproto/syntax"
@@ -460,6 +461,250 @@ func (n *databaseNotifier) NewPullState(ctx context.Context, actor syntax.DID, p
 	)
 }
 
+func (n *databaseNotifier) NewDiscussion(ctx context.Context, discussion *models.Discussion, mentions []syntax.DID) {
+	l := log.FromContext(ctx)
+
+	repo, err := db.GetRepo(n.db, orm.FilterEq("at_uri", discussion.RepoAt))
+	if err != nil {
+		l.Error("failed to get repos", "err", err)
+		return
+	}
+	collaborators, err := db.GetCollaborators(n.db, orm.FilterEq("repo_at", repo.RepoAt()))
+	if err != nil {
+		l.Error("failed to fetch collaborators", "err", err)
+		return
+	}
+
+	// build the recipients list
+	// - owner of the repo
+	// - collaborators in the repo
+	// - remove users already mentioned
+	recipients := sets.Singleton(syntax.DID(repo.Did))
+	for _, c := range collaborators {
+		recipients.Insert(c.SubjectDid)
+	}
+	for _, m := range mentions {
+		recipients.Remove(m)
+	}
+
+	actorDid := syntax.DID(discussion.Did)
+	entityType := "discussion"
+	entityId := discussion.AtUri().String()
+	repoId := &repo.Id
+	var issueId, pullId *int64
+
+	n.notifyEvent(
+		ctx,
+		actorDid,
+		recipients,
+		models.NotificationTypeDiscussionCreated,
+		entityType,
+		entityId,
+		repoId,
+		issueId,
+		pullId,
+	)
+	n.notifyEvent(
+		ctx,
+		actorDid,
+		sets.Collect(slices.Values(mentions)),
+		models.NotificationTypeUserMentioned,
+		entityType,
+		entityId,
+		repoId,
+		issueId,
+		pullId,
+	)
+}
+
+func (n *databaseNotifier) NewDiscussionComment(ctx context.Context, comment *models.DiscussionComment, mentions []syntax.DID) {
+	l := log.FromContext(ctx)
+
+	discussion, err := n.getDiscussion(comment.DiscussionAt)
+	if err != nil {
+		l.Error("failed to get discussion", "err", err)
+		return
+	}
+
+	// build the recipients list:
+	// - the owner of the repo
+	// - everybody subscribed to the discussion
+	// - if the comment is a reply -> everybody on that thread
+	// - remove mentioned users from the recipients list
+	recipients, err := n.discussionSubscribers(discussion)
+	if err != nil {
+		l.Error("failed to get discussion subscribers", "err", err)
+		return
+	}
+
+	if comment.IsReply() {
+		parentAtUri := *comment.ReplyTo
+		for _, t := range discussion.CommentList() {
+			if t.Self.AtUri().String() == parentAtUri {
+				for _, p := range t.Participants() {
+					recipients.Insert(p)
+				}
+			}
+		}
+	}
+
+	for _, m := range mentions {
+		recipients.Remove(m)
+	}
+
+	actorDid := syntax.DID(comment.Did)
+	entityType := "discussion"
+	entityId := discussion.AtUri().String()
+	repoId := &discussion.Repo.Id
+	var issueId, pullId *int64
+
+	n.notifyEvent(
+		ctx,
+		actorDid,
+		recipients,
+		models.NotificationTypeDiscussionCommented,
+		entityType,
+		entityId,
+		repoId,
+		issueId,
+		pullId,
+	)
+	n.notifyEvent(
+		ctx,
+		actorDid,
+		sets.Collect(slices.Values(mentions)),
+		models.NotificationTypeUserMentioned,
+		entityType,
+		entityId,
+		repoId,
+		issueId,
+		pullId,
+	)
+}
+
+func (n *databaseNotifier) NewDiscussionPatch(ctx context.Context, patch *models.DiscussionPatch) {
+	l := log.FromContext(ctx)
+
+	discussion, err := n.getDiscussion(patch.DiscussionAt.String())
+	if err != nil {
+		l.Error("failed to get discussion", "err", err)
+		return
+	}
+
+	// build the recipients list:
+	// - the owner of the repo
+	// - everybody subscribed to the discussion
+	recipients, err := n.discussionSubscribers(discussion)
+	if err != nil {
+		l.Error("failed to get discussion subscribers", "err", err)
+		return
+	}
+
+	actorDid := syntax.DID(patch.PushedByDid)
+	entityType := "discussion"
+	entityId := discussion.AtUri().String()
+	repoId := &discussion.Repo.Id
+	var issueId, pullId *int64
+
+	n.notifyEvent(
+		ctx,
+		actorDid,
+		recipients,
+		models.NotificationTypeDiscussionPatchAdded,
+		entityType,
+		entityId,
+		repoId,
+		issueId,
+		pullId,
+	)
+}
+
+func (n *databaseNotifier) NewDiscussionState(ctx context.Context, actor syntax.DID, discussion *models.Discussion) {
+	l := log.FromContext(ctx)
+
+	var eventType models.NotificationType
+	switch discussion.State {
+	case models.DiscussionClosed:
+		eventType = models.NotificationTypeDiscussionClosed
+	case models.DiscussionOpen:
+		eventType = models.NotificationTypeDiscussionReopen
+	case models.DiscussionMerged:
+		eventType = models.NotificationTypeDiscussionMerged
+	default:
+		l.Error("unexpected new discussion state", "state", discussion.State)
+		return
+	}
+
+	d, err := n.getDiscussion(discussion.AtUri().String())
+	if err != nil {
+		l.Error("failed to get discussion", "err", err)
+		return
+	}
+
+	collaborators, err := db.GetCollaborators(n.db, orm.FilterEq("repo_at", d.Repo.RepoAt()))
+	if err != nil {
+		l.Error("failed to fetch collaborators", "err", err)
+		return
+	}
+
+	// build up the recipients list:
+	// - repo owner
+	// - repo collaborators
+	// - everybody subscribed to the discussion
+	recipients, err := n.discussionSubscribers(d)
+	if err != nil {
+		l.Error("failed to get discussion subscribers", "err", err)
+		return
+	}
+	for _, c := range collaborators {
+		recipients.Insert(c.SubjectDid)
+	}
+
+	entityType := "discussion"
+	entityId := d.AtUri().String()
+	repoId := &d.Repo.Id
+	var issueId, pullId *int64
+
+	n.notifyEvent(
+		ctx,
+		actor,
+		recipients,
+		eventType,
+		entityType,
+		entityId,
+		repoId,
+		issueId,
+		pullId,
+	)
+}
+
+// getDiscussion fetches a discussion along with its repo and comments
+func (n *databaseNotifier) getDiscussion(discussionAt string) (*models.Discussion, error) {
+	discussions, err := db.GetDiscussions(n.db, orm.FilterEq("at_uri", discussionAt))
+	if err != nil {
+		return nil, err
+	}
+	if len(discussions) != 1 {
+		return nil, fmt.Errorf("incorrect number of discussions returned for %s: %d", discussionAt, len(discussions))
+	}
+	return &discussions[0], nil
+}
+
+// discussionSubscribers returns the owner of the repo along with everybody
+// subscribed to the discussion
+func (n *databaseNotifier) discussionSubscribers(discussion *models.Discussion) (sets.Set[syntax.DID], error) {
+	subscribers, err := db.GetDiscussionSubscribers(n.db, discussion.AtUri())
+	if err != nil {
+		return sets.Set[syntax.DID]{}, err
+	}
+
+	recipients := sets.Singleton(syntax.DID(discussion.Repo.Did))
+	for _, s := range subscribers {
+		recipients.Insert(syntax.DID(s))
+	}
+	return recipients, nil
+}
+
 func (n *databaseNotifier) notifyEvent(
 	ctx context.Context,
 	actorDid syntax.DID,
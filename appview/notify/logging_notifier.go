@@ -84,6 +84,26 @@ func (l *loggingNotifier) NewPullState(ctx context.Context, actor syntax.DID, pu
 	l.inner.NewPullState(ctx, actor, pull)
 }
 
+func (l *loggingNotifier) NewDiscussion(ctx context.Context, discussion *models.Discussion, mentions []syntax.DID) {
+	ctx = tlog.IntoContext(ctx, tlog.SubLogger(l.logger, "NewDiscussion"))
+	l.inner.NewDiscussion(ctx, discussion, mentions)
+}
+
+func (l *loggingNotifier) NewDiscussionComment(ctx context.Context, comment *models.DiscussionComment, mentions []syntax.DID) {
+	ctx = tlog.IntoContext(ctx, tlog.SubLogger(l.logger, "NewDiscussionComment"))
+	l.inner.NewDiscussionComment(ctx, comment, mentions)
+}
+
+func (l *loggingNotifier) NewDiscussionPatch(ctx context.Context, patch *models.DiscussionPatch) {
+	ctx = tlog.IntoContext(ctx, tlog.SubLogger(l.logger, "NewDiscussionPatch"))
+	l.inner.NewDiscussionPatch(ctx, patch)
+}
+
+func (l *loggingNotifier) NewDiscussionState(ctx context.Context, actor syntax.DID, discussion *models.Discussion) {
+	ctx = tlog.IntoContext(ctx, tlog.SubLogger(l.logger, "NewDiscussionState"))
+	l.inner.NewDiscussionState(ctx, actor, discussion)
+}
+
 func (l *loggingNotifier) UpdateProfile(ctx context.Context, profile *models.Profile) {
 	ctx = tlog.IntoContext(ctx, tlog.SubLogger(l.logger, "UpdateProfile"))
 	l.inner.UpdateProfile(ctx, profile)
@@ -78,6 +78,22 @@ func (m *mergedNotifier) NewPullState(ctx context.Context, actor syntax.DID, pul
 	m.fanout(func(n Notifier) { n.NewPullState(ctx, actor, pull) })
 }
 
+func (m *mergedNotifier) NewDiscussion(ctx context.Context, discussion *models.Discussion, mentions []syntax.DID) {
+	m.fanout(func(n Notifier) { n.NewDiscussion(ctx, discussion, mentions) })
+}
+
+func (m *mergedNotifier) NewDiscussionComment(ctx context.Context, comment *models.DiscussionComment, mentions []syntax.DID) {
+	m.fanout(func(n Notifier) { n.NewDiscussionComment(ctx, comment, mentions) })
+}
+
+func (m *mergedNotifier) NewDiscussionPatch(ctx context.Context, patch *models.DiscussionPatch) {
+	m.fanout(func(n Notifier) { n.NewDiscussionPatch(ctx, patch) })
+}
+
+func (m *mergedNotifier) NewDiscussionState(ctx context.Context, actor syntax.DID, discussion *models.Discussion) {
+	m.fanout(func(n Notifier) { n.NewDiscussionState(ctx, actor, discussion) })
+}
+
 func (m *mergedNotifier) UpdateProfile(ctx context.Context, profile *models.Profile) {
 	m.fanout(func(n Notifier) { n.UpdateProfile(ctx, profile) })
 }
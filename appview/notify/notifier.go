@@ -25,6 +25,11 @@ type Notifier interface {
 	NewPullComment(ctx context.Context, comment *models.PullComment, mentions []syntax.DID)
 	NewPullState(ctx context.Context, actor syntax.DID, pull *models.Pull)
 
+	NewDiscussion(ctx context.Context, discussion *models.Discussion, mentions []syntax.DID)
+	NewDiscussionComment(ctx context.Context, comment *models.DiscussionComment, mentions []syntax.DID)
+	NewDiscussionPatch(ctx context.Context, patch *models.DiscussionPatch)
+	NewDiscussionState(ctx context.Context, actor syntax.DID, discussion *models.Discussion)
+
 	UpdateProfile(ctx context.Context, profile *models.Profile)
 
 	NewString(ctx context.Context, s *models.String)
@@ -56,6 +61,14 @@ func (m *BaseNotifier) NewPullComment(ctx context.Context, models *models.PullCo
 }
 func (m *BaseNotifier) NewPullState(ctx context.Context, actor syntax.DID, pull *models.Pull) {}
 
+func (m *BaseNotifier) NewDiscussion(ctx context.Context, discussion *models.Discussion, mentions []syntax.DID) {
+}
+func (m *BaseNotifier) NewDiscussionComment(ctx context.Context, comment *models.DiscussionComment, mentions []syntax.DID) {
+}
+func (m *BaseNotifier) NewDiscussionPatch(ctx context.Context, patch *models.DiscussionPatch) {}
+func (m *BaseNotifier) NewDiscussionState(ctx context.Context, actor syntax.DID, discussion *models.Discussion) {
+}
+
 func (m *BaseNotifier) UpdateProfile(ctx context.Context, profile *models.Profile) {}
 
 func (m *BaseNotifier) NewString(ctx context.Context, s *models.String)    {}
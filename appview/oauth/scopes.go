@@ -32,6 +32,11 @@ var TangledScopes = []string{
 	"rpc:sh.tangled.repo.deleteBranch?aud=*",
 	"rpc:sh.tangled.repo.setDefaultBranch?aud=*",
 	"rpc:sh.tangled.repo.forkSync?aud=*",
+	"rpc:sh.tangled.repo.pijulForkSync?aud=*",
+	"rpc:sh.tangled.repo.createChannel?aud=*",
+	"rpc:sh.tangled.repo.renameChannel?aud=*",
+	"rpc:sh.tangled.repo.deleteChannel?aud=*",
+	"rpc:sh.tangled.repo.setDefaultChannel?aud=*",
 	"rpc:sh.tangled.repo.forkStatus?aud=*",
 	"rpc:sh.tangled.repo.mergeCheck?aud=*",
 	"rpc:sh.tangled.pipeline.cancelPipeline?aud=*",
@@ -93,6 +93,12 @@ func (p *Pages) funcMap() template.FuncMap {
 			}
 			return s[:30] + "…"
 		},
+		"truncate": func(n int, s string) string {
+			if len(s) <= n {
+				return s
+			}
+			return s[:n]
+		},
 		"splitOn": func(s, sep string) []string {
 			return strings.Split(s, sep)
 		},
@@ -466,6 +472,7 @@ func (p *Pages) funcMap() template.FuncMap {
 					{"Name": "general", "Icon": "sliders-horizontal"},
 					{"Name": "access", "Icon": "users"},
 					{"Name": "pipelines", "Icon": "layers-2"},
+					{"Name": "pulls", "Icon": "git-pull-request"},
 				},
 			}
 		},
@@ -694,6 +694,7 @@ type RepoIndexParams struct {
 	VerifiedCommits  commitverify.VerifiedCommits
 	Languages        []types.RepoLanguageDetails
 	Pipelines        map[string]models.Pipeline
+	ForkStatus       *tangled.RepoPijulForkStatus_Output
 	NeedsKnotUpgrade bool
 	types.RepoIndexResponse
 }
@@ -772,6 +773,9 @@ type RepoChangeParams struct {
 	RepoInfo     repoinfo.RepoInfo
 	Active       string
 	Change       PijulChangeDetail
+	Channel      string
+	Diff         *types.NiceDiff
+	DiffOpts     types.DiffOpts
 }
 
 func (p *Pages) RepoChange(w io.Writer, params RepoChangeParams) error {
@@ -779,29 +783,45 @@ func (p *Pages) RepoChange(w io.Writer, params RepoChangeParams) error {
 	return p.executeRepo("repo/change", w, params)
 }
 
+type RepoChangeDependenciesParams struct {
+	LoggedInUser *oauth.MultiAccountUser
+	RepoInfo     repoinfo.RepoInfo
+	Active       string
+	Change       PijulDependencyNode
+	Channel      string
+	Dependencies []PijulDependencyNode
+	Dependents   []PijulDependencyNode
+}
+
+func (p *Pages) RepoChangeDependencies(w io.Writer, params RepoChangeDependenciesParams) error {
+	params.Active = "changes"
+	return p.executeRepo("repo/changeDependencies", w, params)
+}
+
+// PijulDependencyNode is a change in a dependency graph. Direct is set when
+// the change is an immediate dependency (or dependent) of the change the
+// graph was built for.
+type PijulDependencyNode struct {
+	Hash         string
+	Message      string
+	Dependencies []string
+	Direct       bool
+	Timestamp    time.Time
+	HasTimestamp bool
+}
+
 type PijulChangeDetail struct {
 	Hash         string
 	Authors      []*tangled.RepoChangeGet_Author
 	Message      string
+	Description  string
 	Dependencies []string
 	Diff         string
 	HasDiff      bool
-	DiffLines    []PijulDiffLine
 	Timestamp    time.Time
 	HasTimestamp bool
 }
 
-type PijulDiffLine struct {
-	Kind string
-	Op   string
-	Body string
-	Text string
-	OldLine int64
-	NewLine int64
-	HasOld  bool
-	HasNew  bool
-}
-
 type RepoCommitParams struct {
 	LoggedInUser *oauth.MultiAccountUser
 	RepoInfo     repoinfo.RepoInfo
@@ -890,6 +910,18 @@ func (p *Pages) RepoBranches(w io.Writer, params RepoBranchesParams) error {
 	return p.executeRepo("repo/branches", w, params)
 }
 
+type RepoChannelsParams struct {
+	LoggedInUser *oauth.MultiAccountUser
+	RepoInfo     repoinfo.RepoInfo
+	Active       string
+	Channels     []types.Branch
+}
+
+func (p *Pages) RepoChannels(w io.Writer, params RepoChannelsParams) error {
+	params.Active = "overview"
+	return p.executeRepo("repo/channels", w, params)
+}
+
 type RepoTagsParams struct {
 	LoggedInUser *oauth.MultiAccountUser
 	RepoInfo     repoinfo.RepoInfo
@@ -936,6 +968,7 @@ type RepoBlobParams struct {
 	BlobView       models.BlobView
 	EmailToDid     map[string]string
 	LastCommitInfo *types.LastCommitInfo
+	Credit         []models.CreditHunk
 	*tangled.RepoBlob_Output
 }
 
@@ -983,6 +1016,7 @@ type RepoGeneralSettingsParams struct {
 	Active             string
 	Tab                string
 	Branches           []types.Branch
+	BranchProtections  []types.BranchProtection
 }
 
 func (p *Pages) RepoGeneralSettings(w io.Writer, params RepoGeneralSettingsParams) error {
@@ -1021,6 +1055,23 @@ func (p *Pages) RepoPipelineSettings(w io.Writer, params RepoPipelineSettingsPar
 	return p.executeRepo("repo/settings/pipelines", w, params)
 }
 
+type RepoPullSettingsParams struct {
+	LoggedInUser    *oauth.MultiAccountUser
+	RepoInfo        repoinfo.RepoInfo
+	Active          string
+	Tab             string
+	Settings        models.PullSettings
+	MergeStrategies []types.MergeStrategy
+	BranchRules     []models.BranchRule
+}
+
+func (p *Pages) RepoPullSettings(w io.Writer, params RepoPullSettingsParams) error {
+	params.Active = "settings"
+	params.Tab = "pulls"
+	params.MergeStrategies = types.MergeStrategies
+	return p.executeRepo("repo/settings/pulls", w, params)
+}
+
 type RepoIssuesParams struct {
 	LoggedInUser    *oauth.MultiAccountUser
 	RepoInfo        repoinfo.RepoInfo
@@ -1209,6 +1260,14 @@ type RepoSinglePullParams struct {
 	ActiveRound        int
 	IsInterdiff        bool
 
+	// review threads on the active round, by file path
+	ReviewThreads map[string][]models.PullReviewThread
+
+	Reviews      []models.PullReview
+	ReviewStatus models.ReviewStatus
+	ChecksStatus models.ChecksStatus
+	PullSettings models.PullSettings
+
 	Reactions   map[models.ReactionKind]models.ReactionDisplayData
 	UserReacted map[models.ReactionKind]bool
 
@@ -1308,6 +1367,9 @@ type PullActionsParams struct {
 	ResubmitCheck      ResubmitResult
 	BranchDeleteStatus *models.BranchDeleteStatus
 	Stack              models.Stack
+	ReviewStatus       models.ReviewStatus
+	ChecksStatus       models.ChecksStatus
+	PullSettings       models.PullSettings
 }
 
 func (p *Pages) PullActionsFragment(w io.Writer, params PullActionsParams) error {
@@ -1325,6 +1387,17 @@ func (p *Pages) PullNewCommentFragment(w io.Writer, params PullNewCommentParams)
 	return p.executePlain("repo/pulls/fragments/pullNewComment", w, params)
 }
 
+type PullNewReviewParams struct {
+	LoggedInUser *oauth.MultiAccountUser
+	RepoInfo     repoinfo.RepoInfo
+	Pull         *models.Pull
+	RoundNumber  int
+}
+
+func (p *Pages) PullNewReviewFragment(w io.Writer, params PullNewReviewParams) error {
+	return p.executePlain("repo/pulls/fragments/pullNewReview", w, params)
+}
+
 type RepoCompareParams struct {
 	LoggedInUser *oauth.MultiAccountUser
 	RepoInfo     repoinfo.RepoInfo
@@ -1336,6 +1409,9 @@ type RepoCompareParams struct {
 	Diff         *types.NiceDiff
 	DiffOpts     types.DiffOpts
 
+	// set when comparing channels of a pijul repo
+	ChannelComparison *tangled.RepoCompareChannels_Output
+
 	Active string
 }
 
@@ -1584,6 +1660,8 @@ type RepoDiscussionsListParams struct {
 	Active          string
 	Discussions     []models.Discussion
 	Filter          string
+	FilterQuery     string
+	FilterLabels    []string
 	DiscussionCount models.DiscussionCount
 }
 
@@ -1593,9 +1671,11 @@ func (p *Pages) RepoDiscussionsList(w io.Writer, params RepoDiscussionsListParam
 }
 
 type NewDiscussionParams struct {
-	LoggedInUser *oauth.MultiAccountUser
-	RepoInfo     repoinfo.RepoInfo
-	Active       string
+	LoggedInUser  *oauth.MultiAccountUser
+	RepoInfo      repoinfo.RepoInfo
+	Active        string
+	TargetChannel string
+	SourceChannel string
 }
 
 func (p *Pages) NewDiscussion(w io.Writer, params NewDiscussionParams) error {
@@ -1604,16 +1684,33 @@ func (p *Pages) NewDiscussion(w io.Writer, params NewDiscussionParams) error {
 }
 
 type RepoSingleDiscussionParams struct {
-	LoggedInUser  *oauth.MultiAccountUser
-	RepoInfo      repoinfo.RepoInfo
-	Active        string
-	Discussion    *models.Discussion
-	CommentList   []models.DiscussionCommentListItem
-	CanManage     bool
-	ActivePatches []*models.DiscussionPatch
+	LoggedInUser     *oauth.MultiAccountUser
+	RepoInfo         repoinfo.RepoInfo
+	Active           string
+	Discussion       *models.Discussion
+	CommentList      []models.DiscussionCommentListItem
+	CanManage        bool
+	ActivePatches    []*models.DiscussionPatch
+	ApplyCheck       types.MergeCheckResponse
+	ChannelConflicts []types.ConflictInfo
+	Pipelines        map[string]models.Pipeline
 }
 
 func (p *Pages) RepoSingleDiscussion(w io.Writer, params RepoSingleDiscussionParams) error {
 	params.Active = "discussions"
 	return p.executeRepo("repo/pijul/discussions/single", w, params)
 }
+
+type RepoDiscussionInterdiffParams struct {
+	LoggedInUser *oauth.MultiAccountUser
+	RepoInfo     repoinfo.RepoInfo
+	Discussion   *models.Discussion
+	From         *models.DiscussionPatch
+	To           *models.DiscussionPatch
+	Interdiff    *patchutil.InterdiffResult
+	DiffOpts     types.DiffOpts
+}
+
+func (p *Pages) RepoDiscussionInterdiff(w io.Writer, params RepoDiscussionInterdiffParams) error {
+	return p.execute("repo/pijul/discussions/interdiff", w, params)
+}
@@ -139,3 +139,15 @@ func (r RolesInRepo) IsCollaborator() bool {
 func (r RolesInRepo) IsPushAllowed() bool {
 	return slices.Contains(r.Roles, "repo:push")
 }
+
+func (r RolesInRepo) IsApplyAllowed() bool {
+	return slices.Contains(r.Roles, "pijul:apply")
+}
+
+func (r RolesInRepo) IsEditChannelsAllowed() bool {
+	return slices.Contains(r.Roles, "pijul:edit_channels")
+}
+
+func (r RolesInRepo) IsEditTagsAllowed() bool {
+	return slices.Contains(r.Roles, "pijul:edit_tags")
+}
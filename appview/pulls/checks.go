@@ -0,0 +1,45 @@
+package pulls
+
+import (
+	"tangled.org/core/appview/db"
+	"tangled.org/core/appview/models"
+	"tangled.org/core/orm"
+)
+
+// checksStatus looks up the workflows that the branch rules of a repo
+// require for the target branch of a pull, and how they fared on the latest
+// submission of the pull
+func (s *Pulls) checksStatus(repo *models.Repo, pull *models.Pull) (models.ChecksStatus, error) {
+	rules, err := db.GetBranchRules(s.db, orm.FilterEq("repo_at", repo.RepoAt()))
+	if err != nil {
+		return models.ChecksStatus{}, err
+	}
+
+	required := models.RequiredWorkflows(rules, pull.TargetBranch)
+	if len(required) == 0 {
+		return models.ChecksStatus{}, nil
+	}
+
+	// patch based pulls have no commit for pipelines to run on
+	sha := pull.LatestSha()
+	if sha == "" {
+		status := models.NewChecksStatus(required, nil)
+		status.NoCommit = true
+		return status, nil
+	}
+
+	// only the latest few pipelines of a commit matter
+	pipelines, err := db.GetPipelineStatuses(
+		s.db,
+		10,
+		orm.FilterEq("p.repo_owner", repo.Did),
+		orm.FilterEq("p.repo_name", repo.Name),
+		orm.FilterEq("p.knot", repo.Knot),
+		orm.FilterEq("p.sha", sha),
+	)
+	if err != nil {
+		return models.ChecksStatus{}, err
+	}
+
+	return models.NewChecksStatus(required, pipelines), nil
+}
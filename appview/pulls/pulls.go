@@ -2,6 +2,7 @@ package pulls
 
 import (
 	"bytes"
+	"cmp"
 	"compress/gzip"
 	"context"
 	"database/sql"
@@ -135,6 +136,24 @@ func (s *Pulls) PullActions(w http.ResponseWriter, r *http.Request) {
 			resubmitResult = s.resubmitCheck(r, f, pull, stack)
 		}
 
+		settings, err := db.GetPullSettings(s.db, f.RepoAt())
+		if err != nil {
+			log.Println("failed to get pull settings", err)
+			settings = models.DefaultPullSettings(f.RepoAt())
+		}
+
+		var reviewStatus models.ReviewStatus
+		if reviews, err := s.pullReviews(pull); err != nil {
+			log.Println("failed to get pull reviews", err)
+		} else {
+			reviewStatus = s.reviewStatus(f, settings, pull, reviews)
+		}
+
+		checksStatus, err := s.checksStatus(f, pull)
+		if err != nil {
+			log.Println("failed to get required checks", err)
+		}
+
 		s.pages.PullActionsFragment(w, pages.PullActionsParams{
 			LoggedInUser:       user,
 			RepoInfo:           s.repoResolver.GetRepoInfo(r, user),
@@ -144,6 +163,9 @@ func (s *Pulls) PullActions(w http.ResponseWriter, r *http.Request) {
 			ResubmitCheck:      resubmitResult,
 			BranchDeleteStatus: branchDeleteStatus,
 			Stack:              stack,
+			ReviewStatus:       reviewStatus,
+			ChecksStatus:       checksStatus,
+			PullSettings:       settings,
 		})
 		return
 	}
@@ -276,6 +298,31 @@ func (s *Pulls) repoPullHelper(w http.ResponseWriter, r *http.Request, interdiff
 		diff = patchutil.Interdiff(previousPatch, currentPatch)
 	}
 
+	settings, err := db.GetPullSettings(s.db, f.RepoAt())
+	if err != nil {
+		log.Println("failed to get pull settings", err)
+		settings = models.DefaultPullSettings(f.RepoAt())
+	}
+
+	reviews, err := s.pullReviews(pull)
+	if err != nil {
+		log.Println("failed to get pull reviews", err)
+		// non-fatal
+	}
+	reviewStatus := s.reviewStatus(f, settings, pull, reviews)
+
+	checksStatus, err := s.checksStatus(f, pull)
+	if err != nil {
+		log.Println("failed to get required checks", err)
+		// non-fatal
+	}
+
+	// interdiffs are not anchored to any one round, so they show no threads
+	var reviewThreads map[string][]models.PullReviewThread
+	if !interdiff {
+		reviewThreads = pull.ReviewThreadsAt(roundIdInt)
+	}
+
 	s.pages.RepoSinglePull(w, pages.RepoSinglePullParams{
 		LoggedInUser:       user,
 		RepoInfo:           s.repoResolver.GetRepoInfo(r, user),
@@ -291,6 +338,11 @@ func (s *Pulls) repoPullHelper(w http.ResponseWriter, r *http.Request, interdiff
 		DiffOpts:           diffOpts,
 		ActiveRound:        roundIdInt,
 		IsInterdiff:        interdiff,
+		ReviewThreads:      reviewThreads,
+		Reviews:            reviews,
+		ReviewStatus:       reviewStatus,
+		ChecksStatus:       checksStatus,
+		PullSettings:       settings,
 
 		Reactions:   reactionMap,
 		UserReacted: userReactions,
@@ -746,8 +798,44 @@ func (s *Pulls) PullComment(w http.ResponseWriter, r *http.Request) {
 		return
 	case http.MethodPost:
 		body := r.FormValue("body")
-		if body == "" {
-			s.pages.Notice(w, "pull", "Comment body is required")
+
+		// review comments are posted from the diff, which has its own notice
+		noticeId := "pull-comment"
+		if r.FormValue("path") != "" || r.FormValue("reply_to") != "" {
+			noticeId = "review-error"
+		}
+
+		// review threads are started by anchoring a comment to lines of this
+		// round, and continued by replying to the comment that started them
+		anchor, err := parseCommentAnchor(r, roundNumber)
+		if err != nil {
+			s.pages.Notice(w, noticeId, fmt.Sprintf("Invalid review comment: %s.", err))
+			return
+		}
+
+		var replyToUri *string
+		replyTo := r.FormValue("reply_to")
+		if replyTo != "" {
+			replyToUri = &replyTo
+			if anchor != nil {
+				s.pages.Notice(w, noticeId, "Replies cannot start a new review thread.")
+				return
+			}
+			if _, ok := pull.ReviewThread(replyTo); !ok {
+				s.pages.Notice(w, noticeId, "No such review thread on this pull.")
+				return
+			}
+		}
+
+		// replies can resolve or reopen their thread, with or without a body
+		var resolved *bool
+		if v := r.FormValue("resolved"); v != "" && replyTo != "" {
+			isResolved := v == "true"
+			resolved = &isResolved
+		}
+
+		if body == "" && resolved == nil {
+			s.pages.Notice(w, noticeId, "Comment body is required")
 			return
 		}
 
@@ -757,7 +845,7 @@ func (s *Pulls) PullComment(w http.ResponseWriter, r *http.Request) {
 		tx, err := s.db.BeginTx(r.Context(), nil)
 		if err != nil {
 			log.Println("failed to start transaction", err)
-			s.pages.Notice(w, "pull-comment", "Failed to create comment.")
+			s.pages.Notice(w, noticeId, "Failed to create comment.")
 			return
 		}
 		defer tx.Rollback()
@@ -767,7 +855,7 @@ func (s *Pulls) PullComment(w http.ResponseWriter, r *http.Request) {
 		client, err := s.oauth.AuthorizedClient(r)
 		if err != nil {
 			log.Println("failed to get authorized client", err)
-			s.pages.Notice(w, "pull-comment", "Failed to create comment.")
+			s.pages.Notice(w, noticeId, "Failed to create comment.")
 			return
 		}
 		atResp, err := comatproto.RepoPutRecord(r.Context(), client, &comatproto.RepoPutRecord_Input{
@@ -779,12 +867,15 @@ func (s *Pulls) PullComment(w http.ResponseWriter, r *http.Request) {
 					Pull:      pull.AtUri().String(),
 					Body:      body,
 					CreatedAt: createdAt,
+					Anchor:    anchorAsRecord(anchor),
+					ReplyTo:   replyToUri,
+					Resolved:  resolved,
 				},
 			},
 		})
 		if err != nil {
 			log.Println("failed to create pull comment", err)
-			s.pages.Notice(w, "pull-comment", "Failed to create comment.")
+			s.pages.Notice(w, noticeId, "Failed to create comment.")
 			return
 		}
 
@@ -797,26 +888,38 @@ func (s *Pulls) PullComment(w http.ResponseWriter, r *http.Request) {
 			SubmissionId: pull.Submissions[roundNumber].ID,
 			Mentions:     mentions,
 			References:   references,
+			ReplyTo:      replyTo,
+			Resolved:     resolved,
+		}
+		if anchor != nil {
+			comment.Anchors = []models.PullCommentAnchor{*anchor}
 		}
 
 		// Create the pull comment in the database with the commentAt field
 		commentId, err := db.NewPullComment(tx, comment)
 		if err != nil {
 			log.Println("failed to create pull comment", err)
-			s.pages.Notice(w, "pull-comment", "Failed to create comment.")
+			s.pages.Notice(w, noticeId, "Failed to create comment.")
 			return
 		}
 
 		// Commit the transaction
 		if err = tx.Commit(); err != nil {
 			log.Println("failed to commit transaction", err)
-			s.pages.Notice(w, "pull-comment", "Failed to create comment.")
+			s.pages.Notice(w, noticeId, "Failed to create comment.")
 			return
 		}
 
-		s.notifier.NewPullComment(r.Context(), comment, mentions)
+		if body != "" {
+			s.notifier.NewPullComment(r.Context(), comment, mentions)
+		}
 
 		ownerSlashRepo := reporesolver.GetBaseRepoPath(r, f)
+		if comment.IsReview() {
+			// review threads are shown on the diff of the round
+			s.pages.HxLocation(w, fmt.Sprintf("/%s/pulls/%d/round/%d#comment-%d", ownerSlashRepo, pull.PullId, roundNumber, commentId))
+			return
+		}
 		s.pages.HxLocation(w, fmt.Sprintf("/%s/pulls/%d#comment-%d", ownerSlashRepo, pull.PullId, commentId))
 		return
 	}
@@ -1892,6 +1995,13 @@ func (s *Pulls) resubmitPullHelper(
 		s.pages.Notice(w, "resubmit-error", "Failed to create pull request. Try again later.")
 		return
 	}
+
+	// review threads are carried to the new round, or marked outdated
+	if err := carryReviewThreads(tx, pull, newRoundNumber, cmp.Or(combinedPatch, newPatch)); err != nil {
+		log.Println("failed to carry review threads", err)
+		s.pages.Notice(w, "resubmit-error", "Failed to create pull request. Try again later.")
+		return
+	}
 	client, err := s.oauth.AuthorizedClient(r)
 	if err != nil {
 		log.Println("failed to authorize client")
@@ -2091,6 +2201,12 @@ func (s *Pulls) resubmitStackedPullHelper(
 			return
 		}
 
+		if err := carryReviewThreads(tx, op, newRoundNumber, np.LatestSubmission().CombinedPatch()); err != nil {
+			log.Println("failed to carry review threads", err, op.PullId)
+			s.pages.Notice(w, "pull-resubmit-error", "Failed to resubmit pull request. Try again later.")
+			return
+		}
+
 		blob, err := xrpc.RepoUploadBlob(r.Context(), client, gz(patch), ApplicationGzip)
 		if err != nil {
 			log.Println("failed to upload patch blob", err)
@@ -2183,7 +2299,54 @@ func (s *Pulls) MergePull(w http.ResponseWriter, r *http.Request) {
 		pullsToMerge = append(pullsToMerge, mergeable...)
 	}
 
-	patch := pullsToMerge.CombinedPatch()
+	settings, err := db.GetPullSettings(s.db, f.RepoAt())
+	if err != nil {
+		log.Println("failed to get pull settings", err)
+		s.pages.Notice(w, "pull-merge-error", "Failed to merge pull request. Try again later.")
+		return
+	}
+
+	strategy := types.MergeStrategy(r.FormValue("strategy"))
+	if strategy == "" {
+		strategy = settings.DefaultMergeStrategyFor(pull)
+	}
+	if !settings.AllowsMergeStrategy(strategy) {
+		s.pages.Notice(w, "pull-merge-error", fmt.Sprintf("This repository does not allow pulls to be merged with the %s strategy.", strategy))
+		return
+	}
+	if strategy == types.MergeStrategyFastForward && !pull.CanFastForward() {
+		s.pages.Notice(w, "pull-merge-error", "Only pulls from a branch of this repository can be fast-forwarded.")
+		return
+	}
+
+	// every pull that is merged must meet the review requirements and pass
+	// the required workflows
+	for _, p := range pullsToMerge {
+		reason, err := s.checkReviews(r.Context(), f, settings, p)
+		if err != nil {
+			log.Println("failed to check reviews", err)
+			s.pages.Notice(w, "pull-merge-error", "Failed to merge pull request. Try again later.")
+			return
+		}
+		if reason == "" {
+			checks, err := s.checksStatus(f, p)
+			if err != nil {
+				log.Println("failed to check required workflows", err)
+				s.pages.Notice(w, "pull-merge-error", "Failed to merge pull request. Try again later.")
+				return
+			}
+			reason = checks.Explain()
+		}
+		if reason == "" {
+			continue
+		}
+		if p == pull {
+			s.pages.Notice(w, "pull-merge-error", fmt.Sprintf("This pull cannot be merged yet: %s.", reason))
+		} else {
+			s.pages.Notice(w, "pull-merge-error", fmt.Sprintf("Pull #%d in this stack cannot be merged yet: %s.", p.PullId, reason))
+		}
+		return
+	}
 
 	ident, err := s.idResolver.ResolveIdent(r.Context(), pull.OwnerDid)
 	if err != nil {
@@ -2198,21 +2361,60 @@ func (s *Pulls) MergePull(w http.ResponseWriter, r *http.Request) {
 	}
 
 	authorName := ident.Handle.String()
-	mergeInput := &tangled.RepoMerge_Input{
-		Did:           f.Did,
-		Name:          f.Name,
-		Branch:        pull.TargetBranch,
-		Patch:         patch,
-		CommitMessage: &pull.Title,
-		AuthorName:    &authorName,
-	}
-
-	if pull.Body != "" {
-		mergeInput.CommitBody = &pull.Body
-	}
-
-	if email.Address != "" {
-		mergeInput.AuthorEmail = &email.Address
+	newMergeInput := func(p *models.Pull, patch string) *tangled.RepoMerge_Input {
+		strategy := string(strategy)
+		input := &tangled.RepoMerge_Input{
+			Did:           f.Did,
+			Name:          f.Name,
+			Branch:        p.TargetBranch,
+			Patch:         patch,
+			CommitMessage: &p.Title,
+			AuthorName:    &authorName,
+			Strategy:      &strategy,
+		}
+		if p.Body != "" {
+			input.CommitBody = &p.Body
+		}
+		if email.Address != "" {
+			input.AuthorEmail = &email.Address
+		}
+		if strategy == string(types.MergeStrategyFastForward) {
+			// only what was reviewed lands
+			sourceRev := p.LatestSha()
+			input.SourceBranch = &p.PullSource.Branch
+			input.SourceRev = &sourceRev
+		}
+		return input
+	}
+
+	// squashing a stack lands one commit per pull, starting from the bottom,
+	// every other strategy lands the whole stack at once
+	type landing struct {
+		input *tangled.RepoMerge_Input
+		pulls models.Stack
+	}
+	var landings []landing
+	if strategy == types.MergeStrategySquash {
+		for i := len(pullsToMerge) - 1; i >= 0; i-- {
+			p := pullsToMerge[i]
+			landings = append(landings, landing{newMergeInput(p, p.LatestPatch()), models.Stack{p}})
+		}
+	} else {
+		input := newMergeInput(pull, pullsToMerge.CombinedPatch())
+		if strategy == types.MergeStrategyMerge && len(pullsToMerge) > 1 {
+			// the merge commit of a stack lists every pull it brings in
+			var body strings.Builder
+			if pull.Body != "" {
+				body.WriteString(pull.Body)
+				body.WriteString("\n\n")
+			}
+			for i := len(pullsToMerge) - 1; i >= 0; i-- {
+				fmt.Fprintf(&body, "- #%d: %s\n", pullsToMerge[i].PullId, pullsToMerge[i].Title)
+			}
+			commitBody := strings.TrimSpace(body.String())
+			input.CommitBody = &commitBody
+		}
+		landings = append(landings, landing{input, pullsToMerge})
 	}
 
 	client, err := s.oauth.ServiceClient(
@@ -2227,45 +2429,60 @@ func (s *Pulls) MergePull(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = tangled.RepoMerge(r.Context(), client, mergeInput)
-	if err := xrpcclient.HandleXrpcErr(err); err != nil {
-		s.pages.Notice(w, "pull-merge-error", err.Error())
-		return
-	}
+	var merged models.Stack
+	defer func() {
+		// notify about the pull merge
+		for _, p := range merged {
+			s.notifier.NewPullState(r.Context(), syntax.DID(user.Active.Did), p)
+		}
+	}()
 
-	tx, err := s.db.Begin()
-	if err != nil {
-		log.Println("failed to start transcation", err)
-		s.pages.Notice(w, "pull-merge-error", "Failed to merge pull request. Try again later.")
-		return
-	}
-	defer tx.Rollback()
+	for _, l := range landings {
+		err = tangled.RepoMerge(r.Context(), client, l.input)
+		if err := xrpcclient.HandleXrpcErr(err); err != nil {
+			if len(merged) > 0 {
+				s.pages.Notice(w, "pull-merge-error", fmt.Sprintf("Merged part of the stack, but pull #%d failed: %s", l.pulls[0].PullId, err.Error()))
+			} else {
+				s.pages.Notice(w, "pull-merge-error", err.Error())
+			}
+			return
+		}
 
-	for _, p := range pullsToMerge {
-		err := db.MergePull(tx, f.RepoAt(), p.PullId)
-		if err != nil {
+		if err := s.markMerged(f, l.pulls); err != nil {
+			// TODO: this is unsound, we should also revert the merge from the knotserver here
 			log.Printf("failed to update pull request status in database: %s", err)
 			s.pages.Notice(w, "pull-merge-error", "Failed to merge pull request. Try again later.")
 			return
 		}
-		p.State = models.PullMerged
+		merged = append(merged, l.pulls...)
 	}
 
-	err = tx.Commit()
+	ownerSlashRepo := reporesolver.GetBaseRepoPath(r, f)
+	s.pages.HxLocation(w, fmt.Sprintf("/%s/pulls/%d", ownerSlashRepo, pull.PullId))
+}
+
+// markMerged records that pulls were merged
+func (s *Pulls) markMerged(repo *models.Repo, pulls models.Stack) error {
+	tx, err := s.db.Begin()
 	if err != nil {
-		// TODO: this is unsound, we should also revert the merge from the knotserver here
-		log.Printf("failed to update pull request status in database: %s", err)
-		s.pages.Notice(w, "pull-merge-error", "Failed to merge pull request. Try again later.")
-		return
+		return err
 	}
+	defer tx.Rollback()
 
-	// notify about the pull merge
-	for _, p := range pullsToMerge {
-		s.notifier.NewPullState(r.Context(), syntax.DID(user.Active.Did), p)
+	for _, p := range pulls {
+		if err := db.MergePull(tx, repo.RepoAt(), p.PullId); err != nil {
+			return err
+		}
 	}
 
-	ownerSlashRepo := reporesolver.GetBaseRepoPath(r, f)
-	s.pages.HxLocation(w, fmt.Sprintf("/%s/pulls/%d", ownerSlashRepo, pull.PullId))
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, p := range pulls {
+		p.State = models.PullMerged
+	}
+	return nil
 }
 
 func (s *Pulls) ClosePull(w http.ResponseWriter, r *http.Request) {
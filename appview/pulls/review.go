@@ -0,0 +1,321 @@
+package pulls
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/appview/db"
+	"tangled.org/core/appview/models"
+	"tangled.org/core/appview/pages"
+	"tangled.org/core/appview/pages/repoinfo"
+	"tangled.org/core/appview/reporesolver"
+	"tangled.org/core/appview/xrpcclient"
+	"tangled.org/core/orm"
+	"tangled.org/core/patchutil"
+	"tangled.org/core/tid"
+
+	comatproto "github.com/bluesky-social/indigo/api/atproto"
+	lexutil "github.com/bluesky-social/indigo/lex/util"
+	"github.com/go-chi/chi/v5"
+)
+
+// parseCommentAnchor reads the lines a new review thread is anchored to from
+// a comment form. It returns nil if the comment is not anchored.
+func parseCommentAnchor(r *http.Request, round int) (*models.PullCommentAnchor, error) {
+	path := r.FormValue("path")
+	if path == "" {
+		return nil, nil
+	}
+
+	side := models.PullCommentSide(r.FormValue("side"))
+	if !side.IsValid() {
+		return nil, errors.New("side must be either old or new")
+	}
+
+	start, err := strconv.Atoi(r.FormValue("start_line"))
+	if err != nil || start < 1 {
+		return nil, errors.New("start line must be a positive number")
+	}
+
+	end := start
+	if e := r.FormValue("end_line"); e != "" {
+		end, err = strconv.Atoi(e)
+		if err != nil || end < start {
+			return nil, errors.New("end line must not come before the start line")
+		}
+	}
+
+	return &models.PullCommentAnchor{
+		Round:     round,
+		Path:      path,
+		Side:      side,
+		StartLine: start,
+		EndLine:   end,
+	}, nil
+}
+
+func anchorAsRecord(a *models.PullCommentAnchor) *tangled.RepoPullComment_Anchor {
+	if a == nil {
+		return nil
+	}
+	return &tangled.RepoPullComment_Anchor{
+		Round:     int64(a.Round),
+		Path:      a.Path,
+		Side:      string(a.Side),
+		StartLine: int64(a.StartLine),
+		EndLine:   int64(a.EndLine),
+	}
+}
+
+// carryReviewThreads records where the review threads on the last round of a
+// pull are on a new round with the given patch
+func carryReviewThreads(tx *sql.Tx, pull *models.Pull, round int, patch string) error {
+	for commentAt, anchor := range carriedReviewThreads(pull, round, patch) {
+		if err := db.AddPullCommentAnchors(tx, commentAt, anchor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// carriedReviewThreads moves the review threads on the last round of a pull
+// to a new round with the given patch. Lines are remapped with the interdiff
+// of the two rounds, and threads whose lines changed are marked outdated.
+// Outdated threads are only carried along while they are unresolved.
+func carriedReviewThreads(pull *models.Pull, round int, patch string) map[string]models.PullCommentAnchor {
+	carried := make(map[string]models.PullCommentAnchor)
+
+	threads := pull.ReviewThreadsAt(pull.LastRoundNumber())
+	if len(threads) == 0 {
+		return carried
+	}
+
+	files := make(map[string]*patchutil.InterdiffFile)
+	previous, err1 := patchutil.AsDiff(pull.LatestSubmission().CombinedPatch())
+	current, err2 := patchutil.AsDiff(patch)
+	if err1 == nil && err2 == nil {
+		for _, f := range patchutil.Interdiff(previous, current).Files {
+			files[f.Name] = f
+		}
+	}
+
+	for path, pathThreads := range threads {
+		file := files[path]
+
+		for _, t := range pathThreads {
+			a := *t.Root.AnchorAt(pull.LastRoundNumber())
+			a.Round = round
+
+			if !a.Outdated {
+				a.Outdated = !remapAnchor(&a, file)
+			}
+
+			if a.Outdated && t.IsResolved() {
+				continue
+			}
+			carried[t.Root.CommentAt] = a
+		}
+	}
+
+	return carried
+}
+
+// remapAnchor moves the lines of an anchor to where they are after the
+// interdiff of a file, and reports whether they are unchanged
+func remapAnchor(a *models.PullCommentAnchor, file *patchutil.InterdiffFile) bool {
+	if file == nil || file.Status.IsOnlyInOne() || file.Status.IsOnlyInTwo() {
+		return false
+	}
+
+	// the old side is the target branch, which is the same for both rounds
+	// unless the patch was rebased
+	if a.Side == models.PullCommentSideOld {
+		return file.Status.IsOk() || file.Status.IsUnchanged()
+	}
+
+	start, end, ok := file.MapLines(int64(a.StartLine), int64(a.EndLine))
+	if !ok {
+		return false
+	}
+
+	a.StartLine, a.EndLine = int(start), int(end)
+	return true
+}
+
+// ReviewPull records the verdict of a reviewer on a round of a pull. Each
+// reviewer has one review per round, which is replaced when they review the
+// round again.
+func (s *Pulls) ReviewPull(w http.ResponseWriter, r *http.Request) {
+	user := s.oauth.GetMultiAccountUser(r)
+	f, err := s.repoResolver.Resolve(r)
+	if err != nil {
+		log.Println("failed to get repo and knot", err)
+		return
+	}
+
+	pull, ok := r.Context().Value("pull").(*models.Pull)
+	if !ok {
+		log.Println("failed to get pull")
+		s.pages.Notice(w, "pull-error", "Failed to edit patch. Try again later.")
+		return
+	}
+
+	roundNumber, err := strconv.Atoi(chi.URLParam(r, "round"))
+	if err != nil || roundNumber < 0 || roundNumber >= len(pull.Submissions) {
+		http.Error(w, "bad round id", http.StatusBadRequest)
+		log.Println("failed to parse round id", err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.pages.PullNewReviewFragment(w, pages.PullNewReviewParams{
+			LoggedInUser: user,
+			RepoInfo:     s.repoResolver.GetRepoInfo(r, user),
+			Pull:         pull,
+			RoundNumber:  roundNumber,
+		})
+		return
+	case http.MethodPost:
+		noticeId := "pull-review"
+
+		if user.Active.Did == pull.OwnerDid {
+			s.pages.Notice(w, noticeId, "You cannot review your own pull.")
+			return
+		}
+		if !pull.State.IsOpen() {
+			s.pages.Notice(w, noticeId, "Only open pulls can be reviewed.")
+			return
+		}
+
+		state := models.PullReviewState(r.FormValue("state"))
+		if !state.IsValid() {
+			s.pages.Notice(w, noticeId, "Choose whether to approve, request changes or comment.")
+			return
+		}
+
+		body := r.FormValue("body")
+		if state == models.PullReviewComment && body == "" {
+			s.pages.Notice(w, noticeId, "Review comments need a body.")
+			return
+		}
+
+		review := models.PullReview{
+			Did:     user.Active.Did,
+			Rkey:    tid.TID(),
+			PullAt:  pull.AtUri(),
+			Round:   roundNumber,
+			State:   state,
+			Body:    body,
+			Created: time.Now(),
+		}
+
+		// a new review of the same round replaces the earlier one
+		existing, err := db.GetPullReviews(
+			s.db,
+			orm.FilterEq("pull_at", pull.AtUri()),
+			orm.FilterEq("round_number", roundNumber),
+			orm.FilterEq("did", user.Active.Did),
+		)
+		if err != nil {
+			log.Println("failed to get existing reviews", err)
+			s.pages.Notice(w, noticeId, "Failed to submit review.")
+			return
+		}
+		var previous *models.PullReview
+		if len(existing) > 0 {
+			previous = &existing[0]
+			review.Rkey = previous.Rkey
+		}
+
+		client, err := s.oauth.AuthorizedClient(r)
+		if err != nil {
+			log.Println("failed to get authorized client", err)
+			s.pages.Notice(w, noticeId, "Failed to submit review.")
+			return
+		}
+
+		record := review.AsRecord()
+		resp, err := comatproto.RepoPutRecord(r.Context(), client, &comatproto.RepoPutRecord_Input{
+			Collection: tangled.RepoPullReviewNSID,
+			Repo:       user.Active.Did,
+			Rkey:       review.Rkey,
+			Record: &lexutil.LexiconTypeDecoder{
+				Val: &record,
+			},
+		})
+		if err != nil {
+			log.Println("failed to create pull review", err)
+			s.pages.Notice(w, noticeId, "Failed to submit review.")
+			return
+		}
+
+		if err := db.PutPullReview(s.db, &review); err != nil {
+			log.Println("failed to save pull review", err)
+			// undo the record, putting back the review it replaced if any
+			if previous == nil {
+				err = xrpcclient.RollbackRecord(context.Background(), resp.Uri, client)
+			} else {
+				previousRecord := previous.AsRecord()
+				_, err = comatproto.RepoPutRecord(context.Background(), client, &comatproto.RepoPutRecord_Input{
+					Collection: tangled.RepoPullReviewNSID,
+					Repo:       user.Active.Did,
+					Rkey:       previous.Rkey,
+					Record: &lexutil.LexiconTypeDecoder{
+						Val: &previousRecord,
+					},
+				})
+			}
+			if err != nil {
+				log.Println("failed to roll back pull review", err)
+			}
+			s.pages.Notice(w, noticeId, "Failed to submit review.")
+			return
+		}
+
+		ownerSlashRepo := reporesolver.GetBaseRepoPath(r, f)
+		s.pages.HxLocation(w, fmt.Sprintf("/%s/pulls/%d/round/%d#review-%s", ownerSlashRepo, pull.PullId, roundNumber, review.Rkey))
+		return
+	}
+}
+
+// reviewStatus tallies the reviews of a pull against the approvals its repo
+// requires. Only reviews by those allowed to push to the repo count.
+func (s *Pulls) reviewStatus(repo *models.Repo, settings models.PullSettings, pull *models.Pull, reviews []models.PullReview) models.ReviewStatus {
+	counts := func(did string) bool {
+		roles := repoinfo.RolesInRepo{Roles: s.enforcer.GetPermissionsInRepo(did, repo.Knot, repo.DidSlashRepo())}
+		return roles.IsPushAllowed()
+	}
+
+	return models.NewReviewStatus(pull, reviews, settings, counts)
+}
+
+// pullReviews fetches the reviews of a pull
+func (s *Pulls) pullReviews(pull *models.Pull) ([]models.PullReview, error) {
+	return db.GetPullReviews(s.db, orm.FilterEq("pull_at", pull.AtUri()))
+}
+
+// checkReviews explains why the review requirements of a repo keep a pull
+// from being merged, or returns an empty string if they are met
+func (s *Pulls) checkReviews(ctx context.Context, repo *models.Repo, settings models.PullSettings, pull *models.Pull) (string, error) {
+	reviews, err := s.pullReviews(pull)
+	if err != nil {
+		return "", err
+	}
+
+	status := s.reviewStatus(repo, settings, pull, reviews)
+	return status.Explain(func(did string) string {
+		ident, err := s.idResolver.ResolveIdent(ctx, did)
+		if err != nil {
+			return did
+		}
+		return "@" + ident.Handle.String()
+	}), nil
+}
@@ -33,6 +33,10 @@ func (s *Pulls) Router(mw *middleware.Middleware) http.Handler {
 				r.Get("/", s.PullComment)
 				r.Post("/", s.PullComment)
 			})
+			r.With(middleware.AuthMiddleware(s.oauth)).Route("/review", func(r chi.Router) {
+				r.Get("/", s.ReviewPull)
+				r.Post("/", s.ReviewPull)
+			})
 		})
 
 		r.Route("/round/{round}.patch", func(r chi.Router) {
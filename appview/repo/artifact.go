@@ -75,18 +75,24 @@ func (rp *Repo) AttachArtifact(w http.ResponseWriter, r *http.Request) {
 	rkey := tid.TID()
 	createdAt := time.Now()
 
+	record := tangled.RepoArtifact{
+		Artifact:  uploadBlobResp.Blob,
+		CreatedAt: createdAt.Format(time.RFC3339),
+		Name:      header.Filename,
+		Repo:      f.RepoAt().String(),
+	}
+	if f.IsPijul() {
+		record.PijulState = &tag.Hash
+	} else {
+		record.Tag = tag.Tag.Hash[:]
+	}
+
 	putRecordResp, err := comatproto.RepoPutRecord(r.Context(), client, &comatproto.RepoPutRecord_Input{
 		Collection: tangled.RepoArtifactNSID,
 		Repo:       user.Active.Did,
 		Rkey:       rkey,
 		Record: &lexutil.LexiconTypeDecoder{
-			Val: &tangled.RepoArtifact{
-				Artifact:  uploadBlobResp.Blob,
-				CreatedAt: createdAt.Format(time.RFC3339),
-				Name:      header.Filename,
-				Repo:      f.RepoAt().String(),
-				Tag:       tag.Tag.Hash[:],
-			},
+			Val: &record,
 		},
 	})
 	if err != nil {
@@ -323,6 +329,18 @@ func (rp *Repo) resolveTag(ctx context.Context, f *models.Repo, tagParam string)
 	}
 
 	repo := fmt.Sprintf("%s/%s", f.Did, f.Name)
+	if f.IsPijul() {
+		tags, err := pijulTags(ctx, xrpcc, repo)
+		if err != nil {
+			l.Error("failed to call XRPC repo.pijulTags", "err", err)
+			return nil, err
+		}
+		if tag := findPijulTag(tags, tagParam); tag != nil {
+			return tag, nil
+		}
+		return nil, fmt.Errorf("invalid tag %q", tagParam)
+	}
+
 	xrpcBytes, err := tangled.RepoTags(ctx, xrpcc, "", 0, repo)
 	if err != nil {
 		if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
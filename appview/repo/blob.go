@@ -80,6 +80,19 @@ func (rp *Repo) Blob(w http.ResponseWriter, r *http.Request) {
 	// Create the blob view
 	blobView := NewBlobView(resp, rp.config, f, ref, filePath, r.URL.Query())
 
+	// pijul files can be shown with each line credited to its change
+	var credit []models.CreditHunk
+	if f.IsPijul() && blobView.HasTextView && r.URL.Query().Get("credit") == "true" {
+		creditResp, err := tangled.RepoPijulCredit(r.Context(), xrpcc, ref, filePath, repo)
+		if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
+			// fall back to the plain view
+			l.Error("failed to call XRPC repo.pijulCredit", "err", xrpcerr)
+		} else {
+			credit = models.NewCreditHunks(creditResp.Lines)
+			blobView.ShowingRendered = false
+		}
+	}
+
 	user := rp.oauth.GetMultiAccountUser(r)
 
 	// Get email to DID mapping for commit author
@@ -115,6 +128,7 @@ func (rp *Repo) Blob(w http.ResponseWriter, r *http.Request) {
 		BlobView:        blobView,
 		EmailToDid:      emailToDidMap,
 		LastCommitInfo:  lastCommitInfo,
+		Credit:          credit,
 		RepoBlob_Output: resp,
 	})
 }
@@ -1,16 +1,20 @@
 package repo
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
-	"strings"
 	"time"
 
 	"tangled.org/core/api/tangled"
+	"tangled.org/core/appview/oauth"
 	"tangled.org/core/appview/pages"
 	xrpcclient "tangled.org/core/appview/xrpcclient"
+	"tangled.org/core/patchutil"
+	"tangled.org/core/types"
 
 	indigoxrpc "github.com/bluesky-social/indigo/xrpc"
 	"github.com/go-chi/chi/v5"
@@ -62,15 +66,7 @@ func (rp *Repo) Changes(w http.ResponseWriter, r *http.Request) {
 			rp.pages.Error503(w)
 			return
 		}
-		for _, ch := range channels.Channels {
-			if ch.Is_current != nil && *ch.Is_current {
-				ref = ch.Name
-				break
-			}
-		}
-		if ref == "" && len(channels.Channels) > 0 {
-			ref = channels.Channels[0].Name
-		}
+		ref = defaultChannelOf(channels.Channels)
 	}
 
 	limit := int64(60)
@@ -132,6 +128,11 @@ func (rp *Repo) Change(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var diffOpts types.DiffOpts
+	if d := r.URL.Query().Get("diff"); d == "split" {
+		diffOpts.Split = true
+	}
+
 	scheme := "http"
 	if !rp.config.Core.Dev {
 		scheme = "https"
@@ -149,16 +150,26 @@ func (rp *Repo) Change(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the channel unrecord acts on, defaulting to the repo's default channel
+	channel := r.URL.Query().Get("channel")
+	if channel == "" {
+		if def, err := tangled.RepoGetDefaultChannel(r.Context(), xrpcc, repo); err == nil {
+			channel = def.Channel
+		}
+	}
+
 	change := pages.PijulChangeDetail{
 		Hash:         resp.Hash,
 		Authors:      resp.Authors,
 		Message:      resp.Message,
 		Dependencies: resp.Dependencies,
 	}
+	if resp.Description != nil {
+		change.Description = *resp.Description
+	}
 	if resp.Diff != nil {
 		change.Diff = *resp.Diff
 		change.HasDiff = true
-		change.DiffLines = parsePijulDiffLines(change.Diff)
 	}
 	if resp.Timestamp != nil {
 		if parsed, err := time.Parse(time.RFC3339, *resp.Timestamp); err == nil {
@@ -172,135 +183,184 @@ func (rp *Repo) Change(w http.ResponseWriter, r *http.Request) {
 		LoggedInUser: user,
 		RepoInfo:     rp.repoResolver.GetRepoInfo(r, user),
 		Change:       change,
+		Channel:      channel,
+		Diff:         pijulNiceDiff(resp),
+		DiffOpts:     diffOpts,
 	})
 }
 
-func parsePijulDiffLines(diff string) []pages.PijulDiffLine {
-	if diff == "" {
-		return nil
+func (rp *Repo) UnrecordChange(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "UnrecordChange")
+	noticeId := "unrecord-error"
+	fail := func(msg string, err error) {
+		l.Error(msg, "err", err)
+		rp.pages.Notice(w, noticeId, msg)
 	}
-	lines := strings.Split(diff, "\n")
-	out := make([]pages.PijulDiffLine, 0, len(lines))
-	var oldLine int64
-	var newLine int64
-	var hasOld bool
-	var hasNew bool
-	for _, line := range lines {
-		kind := "context"
-		op := " "
-		body := line
-		switch {
-		case strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- "):
-			kind = "meta"
-			op = ""
-			hasOld = false
-			hasNew = false
-		case strings.HasPrefix(line, "@@"):
-			kind = "meta"
-			op = ""
-			if o, n, ok := parseUnifiedHunkHeader(line); ok {
-				oldLine = o
-				newLine = n
-				hasOld = true
-				hasNew = true
-			} else {
-				hasOld = false
-				hasNew = false
-			}
-		case strings.HasPrefix(line, "diff ") || strings.HasPrefix(line, "index "):
-			kind = "meta"
-			op = ""
-			hasOld = false
-			hasNew = false
-		case strings.HasPrefix(line, "#"):
-			kind = "section"
-			op = ""
-			hasOld = false
-			hasNew = false
-		case strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++"):
-			kind = "add"
-			op = "+"
-			body = line[1:]
-		case strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---"):
-			kind = "del"
-			op = "-"
-			body = line[1:]
-		case strings.HasPrefix(line, " "):
-			body = line[1:]
-		}
-		diffLine := pages.PijulDiffLine{
-			Kind: kind,
-			Op:   op,
-			Body: body,
-			Text: line,
+
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to fully resolve repo", "err", err)
+		return
+	}
+	if !f.IsPijul() {
+		rp.pages.Error404(w)
+		return
+	}
+
+	hash := chi.URLParam(r, "hash")
+	channel := r.FormValue("channel")
+	if hash == "" || channel == "" {
+		fail("No change or channel provided.", nil)
+		return
+	}
+	cascade := r.FormValue("cascade") == "on"
+
+	client, err := rp.oauth.ServiceClient(
+		r,
+		oauth.WithService(f.Knot),
+		oauth.WithLxm(tangled.RepoUnrecordChangesNSID),
+		oauth.WithDev(rp.config.Core.Dev),
+	)
+	if err != nil {
+		fail("Failed to connect to knotserver", err)
+		return
+	}
+
+	_, err = tangled.RepoUnrecordChanges(r.Context(), client, &tangled.RepoUnrecordChanges_Input{
+		Repo:    fmt.Sprintf("%s/%s", f.Did, f.Name),
+		Channel: channel,
+		Changes: []string{hash},
+		Cascade: &cascade,
+	})
+	if err != nil {
+		// dependents are worth spelling out, the user can retry with cascade
+		var xe *indigoxrpc.XRPCError
+		if errors.As(err, &xe) && xe.ErrStr == "HasDependents" {
+			fail(fmt.Sprintf("Cannot unrecord: %s. Unrecord them as well to continue.", xe.Message), err)
+			return
 		}
-		if kind != "meta" {
-			if kind == "del" {
-				if hasOld {
-					diffLine.OldLine = oldLine
-					diffLine.HasOld = true
-					oldLine++
-				}
-			} else if kind == "add" {
-				if hasNew {
-					diffLine.NewLine = newLine
-					diffLine.HasNew = true
-					newLine++
-				}
-			} else {
-				if hasOld {
-					diffLine.OldLine = oldLine
-					diffLine.HasOld = true
-					oldLine++
-				}
-				if hasNew {
-					diffLine.NewLine = newLine
-					diffLine.HasNew = true
-					newLine++
-				}
-			}
+		if err := xrpcclient.HandleXrpcErr(err); err != nil {
+			fail(fmt.Sprintf("Failed to unrecord change: %s", err), err)
+			return
 		}
-		out = append(out, diffLine)
 	}
-	return out
+
+	l.Info("unrecorded change", "hash", hash, "channel", channel, "cascade", cascade)
+	rp.pages.HxLocation(w, fmt.Sprintf("/%s/changes/%s", f.DidSlashRepo(), url.PathEscape(channel)))
 }
 
-func parseUnifiedHunkHeader(line string) (int64, int64, bool) {
-	start := strings.Index(line, "@@")
-	if start == -1 {
-		return 0, 0, false
+func (rp *Repo) ChangeDependencies(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "RepoChangeDependencies")
+
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to fully resolve repo", "err", err)
+		return
+	}
+	if !f.IsPijul() {
+		rp.pages.Error404(w)
+		return
+	}
+
+	hash := chi.URLParam(r, "hash")
+	if hash == "" {
+		rp.pages.Error404(w)
+		return
+	}
+	channel := r.URL.Query().Get("channel")
+
+	scheme := "http"
+	if !rp.config.Core.Dev {
+		scheme = "https"
+	}
+	host := fmt.Sprintf("%s://%s", scheme, f.Knot)
+	xrpcc := &indigoxrpc.Client{
+		Host: host,
+	}
+
+	repo := fmt.Sprintf("%s/%s", f.Did, f.Name)
+	resp, err := tangled.RepoChangeDependencies(r.Context(), xrpcc, channel, hash, repo)
+	if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
+		l.Error("failed to call XRPC repo.changeDependencies", "err", xrpcerr)
+		rp.pages.Error503(w)
+		return
 	}
-	trimmed := strings.TrimSpace(line[start+2:])
-	end := strings.Index(trimmed, "@@")
-	if end == -1 {
-		return 0, 0, false
+	if resp.Change == nil {
+		rp.pages.Error404(w)
+		return
 	}
-	fields := strings.Fields(strings.TrimSpace(trimmed[:end]))
-	if len(fields) < 2 {
-		return 0, 0, false
+
+	root := dependencyNodeView(resp.Change, false)
+
+	dependencies := make([]pages.PijulDependencyNode, 0, len(resp.Dependencies))
+	for _, n := range resp.Dependencies {
+		direct := slices.Contains(root.Dependencies, n.Hash)
+		dependencies = append(dependencies, dependencyNodeView(n, direct))
 	}
-	oldStart, okOld := parseUnifiedRange(fields[0], "-")
-	newStart, okNew := parseUnifiedRange(fields[1], "+")
-	if !okOld || !okNew {
-		return 0, 0, false
+
+	dependents := make([]pages.PijulDependencyNode, 0, len(resp.Dependents))
+	for _, n := range resp.Dependents {
+		direct := slices.Contains(n.Dependencies, root.Hash)
+		dependents = append(dependents, dependencyNodeView(n, direct))
 	}
-	return oldStart, newStart, true
+
+	user := rp.oauth.GetMultiAccountUser(r)
+	rp.pages.RepoChangeDependencies(w, pages.RepoChangeDependenciesParams{
+		LoggedInUser: user,
+		RepoInfo:     rp.repoResolver.GetRepoInfo(r, user),
+		Change:       root,
+		Channel:      channel,
+		Dependencies: dependencies,
+		Dependents:   dependents,
+	})
 }
 
-func parseUnifiedRange(value, prefix string) (int64, bool) {
-	if !strings.HasPrefix(value, prefix) {
-		return 0, false
+func dependencyNodeView(n *tangled.RepoChangeDependencies_Node, direct bool) pages.PijulDependencyNode {
+	view := pages.PijulDependencyNode{
+		Hash:         n.Hash,
+		Message:      n.Message,
+		Dependencies: n.Dependencies,
+		Direct:       direct,
 	}
-	value = strings.TrimPrefix(value, prefix)
-	if value == "" {
-		return 0, false
+	if n.Timestamp != nil {
+		if parsed, err := time.Parse(time.RFC3339, *n.Timestamp); err == nil {
+			view.Timestamp = parsed
+			view.HasTimestamp = true
+		}
 	}
-	if idx := strings.Index(value, ","); idx >= 0 {
-		value = value[:idx]
+	return view
+}
+
+// pijulNiceDiff converts the files of a pijul change into the diff
+// representation used for git commits, so both render the same way. Pijul
+// only reports where a hunk lands in the new file, so old line numbers are
+// derived from the hunks before it.
+func pijulNiceDiff(resp *tangled.RepoChangeGet_Output) *types.NiceDiff {
+	if len(resp.Files) == 0 {
+		return nil
 	}
-	out, err := strconv.ParseInt(value, 10, 64)
-	if err != nil {
-		return 0, false
+
+	nd := &types.NiceDiff{}
+	for _, f := range patchutil.FromPijulChange(resp) {
+		var d types.Diff
+		d.Name.Old = f.OldName
+		d.Name.New = f.NewName
+		d.IsNew = f.IsNew
+		d.IsDelete = f.IsDelete
+		d.IsRename = f.IsRename
+		for _, frag := range f.TextFragments {
+			d.TextFragments = append(d.TextFragments, *frag)
+		}
+		nd.Diff = append(nd.Diff, d)
 	}
-	return out, true
+
+	if resp.Stats != nil {
+		nd.Stat = types.DiffStat{
+			Insertions:   resp.Stats.Additions,
+			Deletions:    resp.Stats.Deletions,
+			FilesChanged: int(resp.Stats.FilesChanged),
+		}
+	}
+
+	return nd
 }
@@ -0,0 +1,244 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/appview/oauth"
+	"tangled.org/core/appview/pages"
+	xrpcclient "tangled.org/core/appview/xrpcclient"
+	"tangled.org/core/types"
+
+	indigoxrpc "github.com/bluesky-social/indigo/xrpc"
+)
+
+func (rp *Repo) Channels(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "RepoChannels")
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to get repo and knot", "err", err)
+		return
+	}
+	scheme := "http"
+	if !rp.config.Core.Dev {
+		scheme = "https"
+	}
+	host := fmt.Sprintf("%s://%s", scheme, f.Knot)
+	xrpcc := &indigoxrpc.Client{
+		Host: host,
+	}
+	repo := fmt.Sprintf("%s/%s", f.Did, f.Name)
+	channels, err := pijulChannels(r.Context(), xrpcc, repo)
+	if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
+		l.Error("failed to call XRPC repo.channelList", "err", xrpcerr)
+		rp.pages.Error503(w)
+		return
+	}
+	user := rp.oauth.GetMultiAccountUser(r)
+	rp.pages.RepoChannels(w, pages.RepoChannelsParams{
+		LoggedInUser: user,
+		RepoInfo:     rp.repoResolver.GetRepoInfo(r, user),
+		Channels:     channels,
+	})
+}
+
+func (rp *Repo) CreateChannel(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "CreateChannel")
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to get repo and knot", "err", err)
+		return
+	}
+	noticeId := "channel-error"
+	name := r.FormValue("name")
+	if name == "" {
+		rp.pages.Notice(w, noticeId, "No channel name provided.")
+		return
+	}
+	input := &tangled.RepoCreateChannel_Input{
+		Repo: fmt.Sprintf("%s/%s", f.Did, f.Name),
+		Name: name,
+	}
+	if from := r.FormValue("from"); from != "" {
+		input.From = &from
+	}
+	client, err := rp.oauth.ServiceClient(
+		r,
+		oauth.WithService(f.Knot),
+		oauth.WithLxm(tangled.RepoCreateChannelNSID),
+		oauth.WithDev(rp.config.Core.Dev),
+	)
+	if err != nil {
+		rp.pages.Notice(w, noticeId, "Failed to connect to knot server.")
+		return
+	}
+	err = tangled.RepoCreateChannel(r.Context(), client, input)
+	if err := xrpcclient.HandleXrpcErr(err); err != nil {
+		l.Error("failed to create channel", "err", err)
+		rp.pages.Notice(w, noticeId, fmt.Sprintf("Failed to create channel: %s", err))
+		return
+	}
+	l.Info("created channel", "channel", name, "repo", f.RepoAt())
+	rp.pages.HxRefresh(w)
+}
+
+func (rp *Repo) RenameChannel(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "RenameChannel")
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to get repo and knot", "err", err)
+		return
+	}
+	noticeId := "channel-error"
+	name := r.FormValue("name")
+	newName := r.FormValue("newName")
+	if name == "" || newName == "" {
+		rp.pages.Notice(w, noticeId, "No channel name provided.")
+		return
+	}
+	client, err := rp.oauth.ServiceClient(
+		r,
+		oauth.WithService(f.Knot),
+		oauth.WithLxm(tangled.RepoRenameChannelNSID),
+		oauth.WithDev(rp.config.Core.Dev),
+	)
+	if err != nil {
+		rp.pages.Notice(w, noticeId, "Failed to connect to knot server.")
+		return
+	}
+	err = tangled.RepoRenameChannel(
+		r.Context(),
+		client,
+		&tangled.RepoRenameChannel_Input{
+			Repo:    fmt.Sprintf("%s/%s", f.Did, f.Name),
+			Name:    name,
+			NewName: newName,
+		},
+	)
+	if err := xrpcclient.HandleXrpcErr(err); err != nil {
+		l.Error("failed to rename channel", "err", err)
+		rp.pages.Notice(w, noticeId, fmt.Sprintf("Failed to rename channel: %s", err))
+		return
+	}
+	l.Info("renamed channel", "channel", name, "newName", newName, "repo", f.RepoAt())
+	rp.pages.HxRefresh(w)
+}
+
+func (rp *Repo) DeleteChannel(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "DeleteChannel")
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to get repo and knot", "err", err)
+		return
+	}
+	noticeId := "channel-error"
+	name := r.FormValue("name")
+	if name == "" {
+		rp.pages.Notice(w, noticeId, "No channel name provided.")
+		return
+	}
+	client, err := rp.oauth.ServiceClient(
+		r,
+		oauth.WithService(f.Knot),
+		oauth.WithLxm(tangled.RepoDeleteChannelNSID),
+		oauth.WithDev(rp.config.Core.Dev),
+	)
+	if err != nil {
+		rp.pages.Notice(w, noticeId, "Failed to connect to knot server.")
+		return
+	}
+	err = tangled.RepoDeleteChannel(
+		r.Context(),
+		client,
+		&tangled.RepoDeleteChannel_Input{
+			Repo: fmt.Sprintf("%s/%s", f.Did, f.Name),
+			Name: name,
+		},
+	)
+	if err := xrpcclient.HandleXrpcErr(err); err != nil {
+		l.Error("failed to delete channel", "err", err)
+		rp.pages.Notice(w, noticeId, fmt.Sprintf("Failed to delete channel: %s", err))
+		return
+	}
+	l.Info("deleted channel", "channel", name, "repo", f.RepoAt())
+	rp.pages.HxRefresh(w)
+}
+
+func (rp *Repo) SetDefaultChannel(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "SetDefaultChannel")
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to get repo and knot", "err", err)
+		return
+	}
+	noticeId := "channel-error"
+	channel := r.FormValue("channel")
+	if channel == "" {
+		rp.pages.Notice(w, noticeId, "No channel provided.")
+		return
+	}
+	client, err := rp.oauth.ServiceClient(
+		r,
+		oauth.WithService(f.Knot),
+		oauth.WithLxm(tangled.RepoSetDefaultChannelNSID),
+		oauth.WithDev(rp.config.Core.Dev),
+	)
+	if err != nil {
+		rp.pages.Notice(w, noticeId, "Failed to connect to knot server.")
+		return
+	}
+	err = tangled.RepoSetDefaultChannel(
+		r.Context(),
+		client,
+		&tangled.RepoSetDefaultChannel_Input{
+			Repo:    fmt.Sprintf("%s/%s", f.Did, f.Name),
+			Channel: channel,
+		},
+	)
+	if err := xrpcclient.HandleXrpcErr(err); err != nil {
+		l.Error("failed to set default channel", "err", err)
+		rp.pages.Notice(w, noticeId, fmt.Sprintf("Failed to set default channel: %s", err))
+		return
+	}
+	l.Info("set default channel", "channel", channel, "repo", f.RepoAt())
+	rp.pages.HxRefresh(w)
+}
+
+// pijulChannels lists the channels of a repo as branches, to share the
+// templates of git repos
+func pijulChannels(ctx context.Context, xrpcc *indigoxrpc.Client, repo string) ([]types.Branch, error) {
+	resp, err := tangled.RepoChannelList(ctx, xrpcc, "", 100, repo)
+	if err != nil {
+		return nil, err
+	}
+	defaultChannel := defaultChannelOf(resp.Channels)
+	channels := make([]types.Branch, len(resp.Channels))
+	for i, ch := range resp.Channels {
+		channels[i] = types.Branch{
+			Reference: types.Reference{Name: ch.Name},
+			IsDefault: ch.Name == defaultChannel,
+		}
+	}
+	return channels, nil
+}
+
+// defaultChannelOf picks the default channel from a channel listing. Knots
+// that predate persisted default channels only mark the current channel.
+func defaultChannelOf(channels []*tangled.RepoChannelList_Channel) string {
+	for _, ch := range channels {
+		if ch.Is_default != nil && *ch.Is_default {
+			return ch.Name
+		}
+	}
+	for _, ch := range channels {
+		if ch.Is_current != nil && *ch.Is_current {
+			return ch.Name
+		}
+	}
+	if len(channels) > 0 {
+		return channels[0].Name
+	}
+	return ""
+}
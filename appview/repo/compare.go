@@ -37,22 +37,32 @@ func (rp *Repo) CompareNew(w http.ResponseWriter, r *http.Request) {
 	}
 
 	repo := fmt.Sprintf("%s/%s", f.Did, f.Name)
-	branchBytes, err := tangled.RepoBranches(r.Context(), xrpcc, "", 0, repo)
-	if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
-		l.Error("failed to call XRPC repo.branches", "err", xrpcerr)
-		rp.pages.Error503(w)
-		return
-	}
+	var branches []types.Branch
+	if f.IsPijul() {
+		branches, err = pijulChannels(r.Context(), xrpcc, repo)
+		if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
+			l.Error("failed to call XRPC repo.channelList", "err", xrpcerr)
+			rp.pages.Error503(w)
+			return
+		}
+	} else {
+		branchBytes, err := tangled.RepoBranches(r.Context(), xrpcc, "", 0, repo)
+		if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
+			l.Error("failed to call XRPC repo.branches", "err", xrpcerr)
+			rp.pages.Error503(w)
+			return
+		}
 
-	var branchResult types.RepoBranchesResponse
-	if err := json.Unmarshal(branchBytes, &branchResult); err != nil {
-		l.Error("failed to decode XRPC branches response", "err", err)
-		rp.pages.Notice(w, "compare-error", "Failed to produce comparison. Try again later.")
-		return
-	}
-	branches := branchResult.Branches
+		var branchResult types.RepoBranchesResponse
+		if err := json.Unmarshal(branchBytes, &branchResult); err != nil {
+			l.Error("failed to decode XRPC branches response", "err", err)
+			rp.pages.Notice(w, "compare-error", "Failed to produce comparison. Try again later.")
+			return
+		}
+		branches = branchResult.Branches
 
-	sortBranches(branches)
+		sortBranches(branches)
+	}
 
 	var defaultBranch string
 	for _, b := range branches {
@@ -74,18 +84,22 @@ func (rp *Repo) CompareNew(w http.ResponseWriter, r *http.Request) {
 		head = queryHead
 	}
 
-	tagBytes, err := tangled.RepoTags(r.Context(), xrpcc, "", 0, repo)
-	if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
-		l.Error("failed to call XRPC repo.tags", "err", xrpcerr)
-		rp.pages.Error503(w)
-		return
-	}
-
+	// channels are compared without tags, as pijul tags are states rather
+	// than refs
 	var tags types.RepoTagsResponse
-	if err := json.Unmarshal(tagBytes, &tags); err != nil {
-		l.Error("failed to decode XRPC tags response", "err", err)
-		rp.pages.Notice(w, "compare-error", "Failed to produce comparison. Try again later.")
-		return
+	if !f.IsPijul() {
+		tagBytes, err := tangled.RepoTags(r.Context(), xrpcc, "", 0, repo)
+		if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
+			l.Error("failed to call XRPC repo.tags", "err", xrpcerr)
+			rp.pages.Error503(w)
+			return
+		}
+
+		if err := json.Unmarshal(tagBytes, &tags); err != nil {
+			l.Error("failed to decode XRPC tags response", "err", err)
+			rp.pages.Notice(w, "compare-error", "Failed to produce comparison. Try again later.")
+			return
+		}
 	}
 
 	rp.pages.RepoCompareNew(w, pages.RepoCompareNewParams{
@@ -151,6 +165,11 @@ func (rp *Repo) Compare(w http.ResponseWriter, r *http.Request) {
 
 	repo := fmt.Sprintf("%s/%s", f.Did, f.Name)
 
+	if f.IsPijul() {
+		rp.comparePijul(w, r, xrpcc, repo, base, head, diffOpts)
+		return
+	}
+
 	branchBytes, err := tangled.RepoBranches(r.Context(), xrpcc, "", 0, repo)
 	if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
 		l.Error("failed to call XRPC repo.branches", "err", xrpcerr)
@@ -212,3 +231,36 @@ func (rp *Repo) Compare(w http.ResponseWriter, r *http.Request) {
 	})
 
 }
+
+// comparePijul compares two channels of a pijul repo
+func (rp *Repo) comparePijul(w http.ResponseWriter, r *http.Request, xrpcc *indigoxrpc.Client, repo, base, head string, diffOpts types.DiffOpts) {
+	l := rp.logger.With("handler", "RepoCompare")
+	user := rp.oauth.GetMultiAccountUser(r)
+
+	channels, err := pijulChannels(r.Context(), xrpcc, repo)
+	if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
+		l.Error("failed to call XRPC repo.channelList", "err", xrpcerr)
+		rp.pages.Error503(w)
+		return
+	}
+
+	comparison, err := tangled.RepoCompareChannels(r.Context(), xrpcc, base, head, repo)
+	if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
+		l.Error("failed to call XRPC repo.compareChannels", "err", xrpcerr)
+		rp.pages.Error503(w)
+		return
+	}
+
+	diff := patchutil.AsNiceDiff(comparison.Patch, base)
+
+	rp.pages.RepoCompare(w, pages.RepoCompareParams{
+		LoggedInUser:      user,
+		RepoInfo:          rp.repoResolver.GetRepoInfo(r, user),
+		Branches:          channels,
+		Base:              base,
+		Head:              head,
+		Diff:              &diff,
+		DiffOpts:          diffOpts,
+		ChannelComparison: comparison,
+	})
+}
@@ -145,6 +145,17 @@ func (rp *Repo) Index(w http.ResponseWriter, r *http.Request) {
 		// non-fatal
 	}
 
+	// pijul forks are compared against their upstream on the knot
+	var forkStatus *tangled.RepoPijulForkStatus_Output
+	if f.IsPijul() && f.Source != "" {
+		didSlashRepo := fmt.Sprintf("%s/%s", f.Did, f.Name)
+		forkStatus, err = tangled.RepoPijulForkStatus(r.Context(), xrpcc, result.Ref, didSlashRepo, "")
+		if err != nil {
+			l.Warn("failed to fetch fork status", "err", err)
+			// non-fatal
+		}
+	}
+
 	rp.pages.RepoIndexPage(w, pages.RepoIndexParams{
 		LoggedInUser:      user,
 		RepoInfo:          rp.repoResolver.GetRepoInfo(r, user),
@@ -158,6 +169,7 @@ func (rp *Repo) Index(w http.ResponseWriter, r *http.Request) {
 		VerifiedCommits: vc,
 		Languages:       languageInfo,
 		Pipelines:       pipelines,
+		ForkStatus:      forkStatus,
 	})
 }
 
@@ -409,30 +421,22 @@ func (rp *Repo) buildPijulIndexResponse(ctx context.Context, xrpcc *indigoxrpc.C
 		return nil, fmt.Errorf("failed to call repoChannelList: %w", err)
 	}
 
+	defaultChannel := defaultChannelOf(channelsResp.Channels)
+
 	// Convert channels to branches format for compatibility
 	var branches []types.Branch
 	for _, ch := range channelsResp.Channels {
-		isDefault := ch.Is_current != nil && *ch.Is_current
 		branches = append(branches, types.Branch{
 			Reference: types.Reference{
 				Name: ch.Name,
 			},
-			IsDefault: isDefault,
+			IsDefault: ch.Name == defaultChannel,
 		})
 	}
 
-	// if no ref specified, use default channel or first available
+	// if no ref specified, use default channel
 	if ref == "" {
-		for _, ch := range channelsResp.Channels {
-			if ch.Is_current != nil && *ch.Is_current {
-				ref = ch.Name
-				break
-			}
-		}
-		// fallback to first channel
-		if ref == "" && len(channelsResp.Channels) > 0 {
-			ref = channelsResp.Channels[0].Name
-		}
+		ref = defaultChannel
 	}
 
 	// if ref is still empty, this means no channels exist (empty repo)
@@ -458,7 +462,7 @@ func (rp *Repo) buildPijulIndexResponse(ctx context.Context, xrpcc *indigoxrpc.C
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		resp, err := tangled.RepoPijulTree(ctx, xrpcc, ref, "", didSlashRepo)
+		resp, err := tangled.RepoPijulTree(ctx, xrpcc, "", ref, "", didSlashRepo)
 		if err != nil {
 			errs = errors.Join(errs, fmt.Errorf("failed to call repoPijulTree: %w", err))
 			return
@@ -478,6 +482,29 @@ func (rp *Repo) buildPijulIndexResponse(ctx context.Context, xrpcc *indigoxrpc.C
 		changesResp = resp
 	}()
 
+	// unresolved conflicts on the channel, older knots don't report these
+	var conflicts []types.ConflictInfo
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		resp, err := tangled.RepoChannelConflicts(ctx, xrpcc, ref, didSlashRepo)
+		if err != nil {
+			rp.logger.Warn("failed to call repoChannelConflicts", "err", err)
+			return
+		}
+		for _, c := range resp.Conflicts {
+			var reason string
+			if c.Reason != nil {
+				reason = *c.Reason
+			}
+			conflicts = append(conflicts, types.ConflictInfo{
+				Filename: c.Path,
+				Reason:   reason,
+				Changes:  c.Changes,
+			})
+		}
+	}()
+
 	wg.Wait()
 
 	if errs != nil {
@@ -523,6 +550,7 @@ func (rp *Repo) buildPijulIndexResponse(ctx context.Context, xrpcc *indigoxrpc.C
 		Branches:       branches,
 		Tags:           nil, // Pijul doesn't have tags
 		TotalCommits:   totalChanges,
+		Conflicts:      conflicts,
 	}
 
 	return result, nil
@@ -30,7 +30,6 @@ import (
 	"tangled.org/core/xrpc/serviceauth"
 
 	comatproto "github.com/bluesky-social/indigo/api/atproto"
-	atpclient "github.com/bluesky-social/indigo/atproto/client"
 	"github.com/bluesky-social/indigo/atproto/syntax"
 	lexutil "github.com/bluesky-social/indigo/lex/util"
 	securejoin "github.com/cyphar/filepath-securejoin"
@@ -289,7 +288,7 @@ func (rp *Repo) AddLabelDef(w http.ResponseWriter, r *http.Request) {
 
 	rollback := func() {
 		err1 := tx.Rollback()
-		err2 := rollbackRecord(context.Background(), aturi, client)
+		err2 := xrpcclient.RollbackRecord(context.Background(), aturi, client)
 
 		// ignore txn complete errors, this is okay
 		if errors.Is(err1, sql.ErrTxDone) {
@@ -771,7 +770,7 @@ func (rp *Repo) AddCollaborator(w http.ResponseWriter, r *http.Request) {
 	rollback := func() {
 		err1 := tx.Rollback()
 		err2 := rp.enforcer.E.LoadPolicy()
-		err3 := rollbackRecord(context.Background(), aturi, client)
+		err3 := xrpcclient.RollbackRecord(context.Background(), aturi, client)
 
 		// ignore txn complete errors, this is okay
 		if errors.Is(err1, sql.ErrTxDone) {
@@ -966,6 +965,11 @@ func (rp *Repo) SyncRepoFork(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodPost:
+		if f.IsPijul() {
+			rp.syncPijulFork(w, r, f, ref)
+			return
+		}
+
 		client, err := rp.oauth.ServiceClient(
 			r,
 			oauth.WithService(f.Knot),
@@ -1002,6 +1006,51 @@ func (rp *Repo) SyncRepoFork(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// syncPijulFork pulls the changes of the upstream channel that a channel of
+// the fork lacks
+func (rp *Repo) syncPijulFork(w http.ResponseWriter, r *http.Request, f *models.Repo, channel string) {
+	l := rp.logger.With("handler", "SyncRepoFork")
+
+	if f.Source == "" {
+		rp.pages.Notice(w, "repo", "This repository is not a fork.")
+		return
+	}
+
+	if channel == "" {
+		rp.pages.Notice(w, "repo", "Choose a channel to sync.")
+		return
+	}
+
+	client, err := rp.oauth.ServiceClient(
+		r,
+		oauth.WithService(f.Knot),
+		oauth.WithLxm(tangled.RepoPijulForkSyncNSID),
+		oauth.WithDev(rp.config.Core.Dev),
+		oauth.WithTimeout(time.Second*20), // pulling many changes takes time
+	)
+	if err != nil {
+		rp.pages.Notice(w, "repo", "Failed to connect to knot server.")
+		return
+	}
+
+	resp, err := tangled.RepoPijulForkSync(
+		r.Context(),
+		client,
+		&tangled.RepoPijulForkSync_Input{
+			Repo:    fmt.Sprintf("%s/%s", f.Did, f.Name),
+			Channel: channel,
+		},
+	)
+	if err := xrpcclient.HandleXrpcErr(err); err != nil {
+		l.Error("failed to sync fork", "err", err)
+		rp.pages.Notice(w, "repo", err.Error())
+		return
+	}
+
+	l.Info("synced fork", "channel", channel, "pulled", len(resp.Pulled))
+	rp.pages.HxRefresh(w)
+}
+
 func (rp *Repo) ForkRepo(w http.ResponseWriter, r *http.Request) {
 	l := rp.logger.With("handler", "ForkRepo")
 
@@ -1134,7 +1183,7 @@ func (rp *Repo) ForkRepo(w http.ResponseWriter, r *http.Request) {
 		rollback := func() {
 			err1 := tx.Rollback()
 			err2 := rp.enforcer.E.LoadPolicy()
-			err3 := rollbackRecord(context.Background(), aturi, atpClient)
+			err3 := xrpcclient.RollbackRecord(context.Background(), aturi, atpClient)
 
 			// ignore txn complete errors, this is okay
 			if errors.Is(err1, sql.ErrTxDone) {
@@ -1221,25 +1270,3 @@ func (rp *Repo) ForkRepo(w http.ResponseWriter, r *http.Request) {
 		rp.pages.HxLocation(w, fmt.Sprintf("/%s/%s", user.Active.Did, forkName))
 	}
 }
-
-// this is used to rollback changes made to the PDS
-//
-// it is a no-op if the provided ATURI is empty
-func rollbackRecord(ctx context.Context, aturi string, client *atpclient.APIClient) error {
-	if aturi == "" {
-		return nil
-	}
-
-	parsed := syntax.ATURI(aturi)
-
-	collection := parsed.Collection().String()
-	repo := parsed.Authority().String()
-	rkey := parsed.RecordKey().String()
-
-	_, err := comatproto.RepoDeleteRecord(ctx, client, &comatproto.RepoDeleteRecord_Input{
-		Collection: collection,
-		Repo:       repo,
-		Rkey:       rkey,
-	})
-	return err
-}
@@ -20,22 +20,45 @@ func (rp *Repo) Router(mw *middleware.Middleware) http.Handler {
 		r.Get("/*", rp.Tree)
 	})
 	r.Get("/commit/{ref}", rp.Commit)
-	r.Get("/change/{hash}", rp.Change)
+	r.Route("/change/{hash}", func(r chi.Router) {
+		r.Get("/", rp.Change)
+		r.Get("/dependencies", rp.ChangeDependencies)
+
+		// unrecording rewrites the channel, needs pijul:apply
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(rp.oauth))
+			r.Use(mw.RepoPermissionMiddleware("pijul:apply"))
+			r.Post("/unrecord", rp.UnrecordChange)
+		})
+	})
 	r.Get("/branches", rp.Branches)
 	r.Delete("/branches", rp.DeleteBranch)
+	r.Route("/channels", func(r chi.Router) {
+		r.Get("/", rp.Channels)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(rp.oauth))
+			r.Use(mw.RepoPermissionMiddleware("pijul:edit_channels"))
+			r.Post("/", rp.CreateChannel)
+			r.Put("/rename", rp.RenameChannel)
+			r.Put("/default", rp.SetDefaultChannel)
+			r.Delete("/", rp.DeleteChannel)
+		})
+	})
 	r.Route("/tags", func(r chi.Router) {
 		r.Get("/", rp.Tags)
 		r.Route("/{tag}", func(r chi.Router) {
 			r.Get("/", rp.Tag)
 			r.Get("/download/{file}", rp.DownloadArtifact)
 
-			// require repo:push to upload or delete artifacts
+			// require repo:push (pijul:edit_tags on pijul repos) to upload
+			// or delete artifacts
 			//
 			// additionally: only the uploader can truly delete an artifact
 			// (record+blob will live on their pds)
 			r.Group(func(r chi.Router) {
 				r.Use(middleware.AuthMiddleware(rp.oauth))
-				r.Use(mw.RepoPermissionMiddleware("repo:push"))
+				r.Use(mw.RepoVcsPermissionMiddleware("repo:push", "pijul:edit_tags"))
 				r.Post("/upload", rp.AttachArtifact)
 				r.Delete("/{file}", rp.DeleteArtifact)
 			})
@@ -54,6 +77,7 @@ func (rp *Repo) Router(mw *middleware.Middleware) http.Handler {
 		r.Post("/", rp.ForkRepo)
 		r.With(mw.RepoPermissionMiddleware("repo:owner")).Route("/sync", func(r chi.Router) {
 			r.Post("/", rp.SyncRepoFork)
+			r.Post("/{ref}", rp.SyncRepoFork)
 		})
 	})
 
@@ -81,6 +105,11 @@ func (rp *Repo) Router(mw *middleware.Middleware) http.Handler {
 			r.Get("/", rp.Settings)
 			r.With(mw.RepoPermissionMiddleware("repo:owner")).Put("/base", rp.EditBaseSettings)
 			r.With(mw.RepoPermissionMiddleware("repo:owner")).Post("/spindle", rp.EditSpindle)
+			r.With(mw.RepoPermissionMiddleware("repo:owner")).Post("/pulls", rp.EditPullSettings)
+			r.With(mw.RepoPermissionMiddleware("repo:owner")).Put("/branch-rules", rp.AddBranchRule)
+			r.With(mw.RepoPermissionMiddleware("repo:owner")).Delete("/branch-rules", rp.DeleteBranchRule)
+			r.With(mw.RepoPermissionMiddleware("repo:owner")).Put("/branch-protections", rp.AddBranchProtection)
+			r.With(mw.RepoPermissionMiddleware("repo:owner")).Delete("/branch-protections", rp.DeleteBranchProtection)
 			r.With(mw.RepoPermissionMiddleware("repo:owner")).Put("/label", rp.AddLabelDef)
 			r.With(mw.RepoPermissionMiddleware("repo:owner")).Delete("/label", rp.DeleteLabelDef)
 			r.With(mw.RepoPermissionMiddleware("repo:owner")).Post("/label/subscribe", rp.SubscribeLabel)
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"tangled.org/core/api/tangled"
 	"tangled.org/core/appview/db"
@@ -167,6 +169,9 @@ func (rp *Repo) Settings(w http.ResponseWriter, r *http.Request) {
 
 	case "pipelines":
 		rp.pipelineSettings(w, r)
+
+	case "pulls":
+		rp.pullSettings(w, r)
 	}
 }
 
@@ -200,6 +205,25 @@ func (rp *Repo) generalSettings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// knots that do not support branch protection yet fail this call, which
+	// should not keep the rest of the settings from loading
+	var protections []types.BranchProtection
+	if !f.IsPijul() {
+		if out, err := tangled.RepoBranchProtections(r.Context(), xrpcc, repo); err != nil {
+			l.Error("failed to call XRPC repo.branchProtections", "err", err)
+		} else {
+			for _, p := range out.Rules {
+				protections = append(protections, types.BranchProtection{
+					Pattern:        p.Pattern,
+					NoForcePush:    p.NoForcePush,
+					NoDeletion:     p.NoDeletion,
+					RequirePull:    p.RequirePull,
+					AllowedPushers: p.AllowedPushers,
+				})
+			}
+		}
+	}
+
 	defaultLabels, err := db.GetLabelDefinitions(rp.db, orm.FilterIn("at_uri", rp.config.Label.DefaultLabelDefs))
 	if err != nil {
 		l.Error("failed to fetch labels", "err", err)
@@ -247,6 +271,7 @@ func (rp *Repo) generalSettings(w http.ResponseWriter, r *http.Request) {
 		LoggedInUser:       user,
 		RepoInfo:           rp.repoResolver.GetRepoInfo(r, user),
 		Branches:           result.Branches,
+		BranchProtections:  protections,
 		Labels:             labels,
 		DefaultLabels:      defaultLabels,
 		SubscribedLabels:   subscribedLabels,
@@ -360,6 +385,257 @@ func (rp *Repo) pipelineSettings(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (rp *Repo) pullSettings(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "pullSettings")
+
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to get repo and knot", "err", err)
+		return
+	}
+	user := rp.oauth.GetMultiAccountUser(r)
+
+	settings, err := db.GetPullSettings(rp.db, f.RepoAt())
+	if err != nil {
+		l.Error("failed to fetch pull settings", "err", err)
+		rp.pages.Error503(w)
+		return
+	}
+
+	rules, err := db.GetBranchRules(rp.db, orm.FilterEq("repo_at", f.RepoAt()))
+	if err != nil {
+		l.Error("failed to fetch branch rules", "err", err)
+		rp.pages.Error503(w)
+		return
+	}
+
+	rp.pages.RepoPullSettings(w, pages.RepoPullSettingsParams{
+		LoggedInUser: user,
+		RepoInfo:     rp.repoResolver.GetRepoInfo(r, user),
+		Settings:     settings,
+		BranchRules:  rules,
+	})
+}
+
+func (rp *Repo) AddBranchRule(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "AddBranchRule")
+
+	noticeId := "branch-rule-operation"
+
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to get repo and knot", "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	rule := models.BranchRule{
+		RepoAt:            f.RepoAt(),
+		Pattern:           strings.TrimSpace(r.FormValue("pattern")),
+		RequiredWorkflows: strings.FieldsFunc(r.FormValue("workflows"), func(c rune) bool { return c == ',' || unicode.IsSpace(c) }),
+		Created:           time.Now(),
+	}
+	if err := rule.Validate(); err != nil {
+		rp.pages.Notice(w, noticeId, err.Error())
+		return
+	}
+
+	if err := db.AddBranchRule(rp.db, &rule); err != nil {
+		l.Error("failed to add branch rule", "err", err)
+		rp.pages.Notice(w, noticeId, "Failed to add branch rule.")
+		return
+	}
+
+	rp.pages.HxRefresh(w)
+}
+
+func (rp *Repo) DeleteBranchRule(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "DeleteBranchRule")
+
+	noticeId := "branch-rule-operation"
+
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to get repo and knot", "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = db.DeleteBranchRule(
+		rp.db,
+		orm.FilterEq("repo_at", f.RepoAt()),
+		orm.FilterEq("id", r.FormValue("rule-id")),
+	)
+	if err != nil {
+		l.Error("failed to delete branch rule", "err", err)
+		rp.pages.Notice(w, noticeId, "Failed to delete branch rule.")
+		return
+	}
+
+	rp.pages.HxRefresh(w)
+}
+
+func (rp *Repo) AddBranchProtection(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "AddBranchProtection")
+
+	noticeId := "branch-protection-operation"
+
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to get repo and knot", "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	rule := types.BranchProtection{
+		Pattern:     strings.TrimSpace(r.FormValue("pattern")),
+		NoForcePush: r.FormValue("no_force_push") == "on",
+		NoDeletion:  r.FormValue("no_deletion") == "on",
+		RequirePull: r.FormValue("require_pull") == "on",
+	}
+
+	pushers := strings.FieldsFunc(r.FormValue("allowed_pushers"), func(c rune) bool { return c == ',' || unicode.IsSpace(c) })
+	for _, pusher := range pushers {
+		// remove a single leading `@`, to make @handle work with ResolveIdent
+		pusher = strings.TrimPrefix(pusher, "@")
+
+		ident, err := rp.idResolver.ResolveIdent(r.Context(), pusher)
+		if err != nil {
+			rp.pages.Notice(w, noticeId, fmt.Sprintf("'%s' is not a valid DID/handle.", pusher))
+			return
+		}
+		rule.AllowedPushers = append(rule.AllowedPushers, ident.DID.String())
+	}
+
+	if err := rule.Validate(); err != nil {
+		rp.pages.Notice(w, noticeId, err.Error())
+		return
+	}
+
+	client, err := rp.oauth.ServiceClient(
+		r,
+		oauth.WithService(f.Knot),
+		oauth.WithLxm(tangled.RepoSetBranchProtectionNSID),
+		oauth.WithDev(rp.config.Core.Dev),
+	)
+	if err != nil {
+		l.Error("failed to connect to knot server", "err", err)
+		rp.pages.Notice(w, noticeId, "Failed to connect to knot server.")
+		return
+	}
+
+	xe := tangled.RepoSetBranchProtection(
+		r.Context(),
+		client,
+		&tangled.RepoSetBranchProtection_Input{
+			Repo: f.DidSlashRepo(),
+			Rule: &tangled.RepoBranchProtections_Rule{
+				Pattern:        rule.Pattern,
+				NoForcePush:    rule.NoForcePush,
+				NoDeletion:     rule.NoDeletion,
+				RequirePull:    rule.RequirePull,
+				AllowedPushers: rule.AllowedPushers,
+			},
+		},
+	)
+	if err := xrpcclient.HandleXrpcErr(xe); err != nil {
+		l.Error("xrpc failed", "err", xe)
+		rp.pages.Notice(w, noticeId, err.Error())
+		return
+	}
+
+	rp.pages.HxRefresh(w)
+}
+
+func (rp *Repo) DeleteBranchProtection(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "DeleteBranchProtection")
+
+	noticeId := "branch-protection-operation"
+
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to get repo and knot", "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	client, err := rp.oauth.ServiceClient(
+		r,
+		oauth.WithService(f.Knot),
+		oauth.WithLxm(tangled.RepoDeleteBranchProtectionNSID),
+		oauth.WithDev(rp.config.Core.Dev),
+	)
+	if err != nil {
+		l.Error("failed to connect to knot server", "err", err)
+		rp.pages.Notice(w, noticeId, "Failed to connect to knot server.")
+		return
+	}
+
+	xe := tangled.RepoDeleteBranchProtection(
+		r.Context(),
+		client,
+		&tangled.RepoDeleteBranchProtection_Input{
+			Repo:    f.DidSlashRepo(),
+			Pattern: r.FormValue("pattern"),
+		},
+	)
+	if err := xrpcclient.HandleXrpcErr(xe); err != nil {
+		l.Error("xrpc failed", "err", xe)
+		rp.pages.Notice(w, noticeId, err.Error())
+		return
+	}
+
+	rp.pages.HxRefresh(w)
+}
+
+func (rp *Repo) EditPullSettings(w http.ResponseWriter, r *http.Request) {
+	l := rp.logger.With("handler", "EditPullSettings")
+
+	noticeId := "repo-pull-settings-error"
+
+	f, err := rp.repoResolver.Resolve(r)
+	if err != nil {
+		l.Error("failed to get repo and knot", "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		l.Error("invalid form", "err", err)
+		rp.pages.Notice(w, noticeId, "Failed to save pull settings.")
+		return
+	}
+
+	required, err := strconv.Atoi(r.FormValue("required_approvals"))
+	if err != nil {
+		rp.pages.Notice(w, noticeId, "Required approvals must be zero or a positive number.")
+		return
+	}
+
+	settings := models.PullSettings{
+		RepoAt:               f.RepoAt(),
+		RequiredApprovals:    required,
+		DefaultMergeStrategy: types.MergeStrategy(r.FormValue("default_merge_strategy")),
+	}
+	for _, s := range r.Form["merge_strategies"] {
+		settings.MergeStrategies = append(settings.MergeStrategies, types.MergeStrategy(s))
+	}
+
+	if err := settings.Validate(); err != nil {
+		rp.pages.Notice(w, noticeId, err.Error())
+		return
+	}
+
+	err = db.SetPullSettings(rp.db, settings)
+	if err != nil {
+		l.Error("failed to save pull settings", "err", err)
+		rp.pages.Notice(w, noticeId, "Failed to save pull settings.")
+		return
+	}
+
+	rp.pages.HxRefresh(w)
+}
+
 func (rp *Repo) EditBaseSettings(w http.ResponseWriter, r *http.Request) {
 	l := rp.logger.With("handler", "EditBaseSettings")
 
@@ -1,9 +1,11 @@
 package repo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"tangled.org/core/api/tangled"
 	"tangled.org/core/appview/db"
@@ -16,6 +18,7 @@ import (
 	indigoxrpc "github.com/bluesky-social/indigo/xrpc"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 func (rp *Repo) Tags(w http.ResponseWriter, r *http.Request) {
@@ -25,14 +28,6 @@ func (rp *Repo) Tags(w http.ResponseWriter, r *http.Request) {
 		l.Error("failed to get repo and knot", "err", err)
 		return
 	}
-	if f.IsPijul() {
-		rp.pages.Error404(w)
-		return
-	}
-	if f.IsPijul() {
-		rp.pages.Error404(w)
-		return
-	}
 	scheme := "http"
 	if !rp.config.Core.Dev {
 		scheme = "https"
@@ -42,17 +37,26 @@ func (rp *Repo) Tags(w http.ResponseWriter, r *http.Request) {
 		Host: host,
 	}
 	repo := fmt.Sprintf("%s/%s", f.Did, f.Name)
-	xrpcBytes, err := tangled.RepoTags(r.Context(), xrpcc, "", 0, repo)
-	if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
-		l.Error("failed to call XRPC repo.tags", "err", xrpcerr)
-		rp.pages.Error503(w)
-		return
-	}
 	var result types.RepoTagsResponse
-	if err := json.Unmarshal(xrpcBytes, &result); err != nil {
-		l.Error("failed to decode XRPC response", "err", err)
-		rp.pages.Error503(w)
-		return
+	if f.IsPijul() {
+		result.Tags, err = pijulTags(r.Context(), xrpcc, repo)
+		if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
+			l.Error("failed to call XRPC repo.pijulTags", "err", xrpcerr)
+			rp.pages.Error503(w)
+			return
+		}
+	} else {
+		xrpcBytes, err := tangled.RepoTags(r.Context(), xrpcc, "", 0, repo)
+		if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
+			l.Error("failed to call XRPC repo.tags", "err", xrpcerr)
+			rp.pages.Error503(w)
+			return
+		}
+		if err := json.Unmarshal(xrpcBytes, &result); err != nil {
+			l.Error("failed to decode XRPC response", "err", err)
+			rp.pages.Error503(w)
+			return
+		}
 	}
 	artifacts, err := db.GetArtifact(rp.db, orm.FilterEq("repo_at", f.RepoAt()))
 	if err != nil {
@@ -107,17 +111,31 @@ func (rp *Repo) Tag(w http.ResponseWriter, r *http.Request) {
 	repo := fmt.Sprintf("%s/%s", f.Did, f.Name)
 	tag := chi.URLParam(r, "tag")
 
-	xrpcBytes, err := tangled.RepoTag(r.Context(), xrpcc, repo, tag)
-	if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
-		l.Error("failed to call XRPC repo.tags", "err", xrpcerr)
-		rp.pages.Error503(w)
-		return
-	}
 	var result types.RepoTagResponse
-	if err := json.Unmarshal(xrpcBytes, &result); err != nil {
-		l.Error("failed to decode XRPC response", "err", err)
-		rp.pages.Error503(w)
-		return
+	if f.IsPijul() {
+		tags, err := pijulTags(r.Context(), xrpcc, repo)
+		if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
+			l.Error("failed to call XRPC repo.pijulTags", "err", xrpcerr)
+			rp.pages.Error503(w)
+			return
+		}
+		result.Tag = findPijulTag(tags, tag)
+		if result.Tag == nil {
+			rp.pages.Error404(w)
+			return
+		}
+	} else {
+		xrpcBytes, err := tangled.RepoTag(r.Context(), xrpcc, repo, tag)
+		if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
+			l.Error("failed to call XRPC repo.tags", "err", xrpcerr)
+			rp.pages.Error503(w)
+			return
+		}
+		if err := json.Unmarshal(xrpcBytes, &result); err != nil {
+			l.Error("failed to decode XRPC response", "err", err)
+			rp.pages.Error503(w)
+			return
+		}
 	}
 
 	filters := []orm.Filter{orm.FilterEq("repo_at", f.RepoAt())}
@@ -144,3 +162,61 @@ func (rp *Repo) Tag(w http.ResponseWriter, r *http.Request) {
 		ArtifactMap:     artifactMap,
 	})
 }
+
+// pijulTags lists the tags of a pijul repo as tag references, so that the
+// tags page and artifacts treat them the same as annotated git tags
+func pijulTags(ctx context.Context, xrpcc *indigoxrpc.Client, repo string) ([]*types.TagReference, error) {
+	out, err := tangled.RepoPijulTags(ctx, xrpcc, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]*types.TagReference, len(out.Tags))
+	for i, t := range out.Tags {
+		tags[i] = pijulTagReference(t)
+	}
+	return tags, nil
+}
+
+// pijulTagReference converts a pijul tag into a tag reference. The reference
+// hash is the tagged state; the tag object is keyed by models.PijulTagKey.
+func pijulTagReference(t *tangled.RepoPijulTags_Tag) *types.TagReference {
+	key := models.PijulTagKey(t.State)
+	tag := &object.Tag{
+		Hash:    key,
+		Name:    t.Name,
+		Message: t.Message,
+		Target:  key,
+	}
+	if len(t.Authors) > 0 {
+		tag.Tagger.Name = t.Authors[0].Name
+		if t.Authors[0].Email != nil {
+			tag.Tagger.Email = *t.Authors[0].Email
+		}
+	}
+	if t.Timestamp != nil {
+		if when, err := time.Parse(time.RFC3339, *t.Timestamp); err == nil {
+			tag.Tagger.When = when
+		}
+	}
+
+	return &types.TagReference{
+		Reference: types.Reference{
+			Name: t.Name,
+			Hash: t.State,
+		},
+		Tag:     tag,
+		Message: t.Message,
+	}
+}
+
+// findPijulTag finds a tag by name, state, or key. Tags are listed most
+// recent first, so the most recent of several tags sharing a name wins.
+func findPijulTag(tags []*types.TagReference, ref string) *types.TagReference {
+	for _, t := range tags {
+		if t.Name == ref || t.Hash == ref || t.Tag.Hash.String() == ref {
+			return t
+		}
+	}
+	return nil
+}
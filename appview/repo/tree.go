@@ -43,7 +43,9 @@ func (rp *Repo) Tree(w http.ResponseWriter, r *http.Request) {
 	}
 	repo := fmt.Sprintf("%s/%s", f.Did, f.Name)
 	if f.IsPijul() {
-		xrpcResp, err := tangled.RepoPijulTree(r.Context(), xrpcc, ref, treePath, repo)
+		// an optional change hash pins the tree to an older channel state
+		change := r.URL.Query().Get("change")
+		xrpcResp, err := tangled.RepoPijulTree(r.Context(), xrpcc, change, ref, treePath, repo)
 		if xrpcerr := xrpcclient.HandleXrpcErr(err); xrpcerr != nil {
 			l.Error("failed to call XRPC repo.pijulTree", "err", xrpcerr)
 			rp.pages.Error503(w)
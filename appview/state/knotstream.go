@@ -239,6 +239,12 @@ func ingestPipeline(d *db.DB, source ec.Source, msg ec.Message) error {
 		trigger.PRSourceSha = &record.TriggerMetadata.PullRequest.SourceSha
 		trigger.PRAction = &record.TriggerMetadata.PullRequest.Action
 		sha = *trigger.PRSourceSha
+	case workflow.TriggerKindDiscussion:
+		trigger.DiscussionAt = &record.TriggerMetadata.Discussion.Discussion
+		trigger.DiscussionChannel = &record.TriggerMetadata.Discussion.TargetChannel
+		trigger.DiscussionPatch = &record.TriggerMetadata.Discussion.PatchHash
+		trigger.DiscussionAction = &record.TriggerMetadata.Discussion.Action
+		sha = *trigger.DiscussionPatch
 	}
 
 	tx, err := d.Begin()
@@ -297,6 +297,7 @@ func (s *State) DiscussionsRouter(mw *middleware.Middleware) http.Handler {
 		s.config,
 		s.notifier,
 		s.validator,
+		s.indexer.Discussions,
 		log.SubLogger(s.logger, "discussions"),
 	)
 	return discussions.Router(mw)
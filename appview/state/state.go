@@ -35,8 +35,6 @@ import (
 	"tangled.org/core/tid"
 
 	comatproto "github.com/bluesky-social/indigo/api/atproto"
-	atpclient "github.com/bluesky-social/indigo/atproto/client"
-	"github.com/bluesky-social/indigo/atproto/syntax"
 	lexutil "github.com/bluesky-social/indigo/lex/util"
 	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/go-chi/chi/v5"
@@ -118,6 +116,11 @@ func Make(ctx context.Context, config *config.Config) (*State, error) {
 			tangled.StringNSID,
 			tangled.RepoIssueNSID,
 			tangled.RepoIssueCommentNSID,
+			tangled.RepoDiscussionNSID,
+			tangled.RepoDiscussionPatchNSID,
+			tangled.RepoDiscussionPatchStateNSID,
+			tangled.RepoDiscussionCommentNSID,
+			tangled.RepoDiscussionStateNSID,
 			tangled.LabelDefinitionNSID,
 			tangled.LabelOpNSID,
 		},
@@ -551,7 +554,7 @@ func (s *State) NewRepo(w http.ResponseWriter, r *http.Request) {
 		rollback := func() {
 			err1 := tx.Rollback()
 			err2 := s.enforcer.E.LoadPolicy()
-			err3 := rollbackRecord(context.Background(), aturi, atpClient)
+			err3 := xrpcclient.RollbackRecord(context.Background(), aturi, atpClient)
 
 			// ignore txn complete errors, this is okay
 			if errors.Is(err1, sql.ErrTxDone) {
@@ -637,28 +640,6 @@ func (s *State) NewRepo(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// this is used to rollback changes made to the PDS
-//
-// it is a no-op if the provided ATURI is empty
-func rollbackRecord(ctx context.Context, aturi string, client *atpclient.APIClient) error {
-	if aturi == "" {
-		return nil
-	}
-
-	parsed := syntax.ATURI(aturi)
-
-	collection := parsed.Collection().String()
-	repo := parsed.Authority().String()
-	rkey := parsed.RecordKey().String()
-
-	_, err := comatproto.RepoDeleteRecord(ctx, client, &comatproto.RepoDeleteRecord_Input{
-		Collection: collection,
-		Repo:       repo,
-		Rkey:       rkey,
-	})
-	return err
-}
-
 func BackfillDefaultDefs(e db.Execer, r *idresolver.Resolver, defaults []string) error {
 	defaultLabels, err := db.GetLabelDefinitions(e, orm.FilterIn("at_uri", defaults))
 	if err != nil {
@@ -0,0 +1,87 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"tangled.org/core/appview/db"
+	"tangled.org/core/appview/models"
+	"tangled.org/core/orm"
+)
+
+func (v *Validator) ValidateDiscussionComment(comment *models.DiscussionComment) error {
+	// if comments have parents, only ingest ones that are 1 level deep
+	if comment.ReplyTo != nil {
+		parents, err := db.GetDiscussionComments(v.db, orm.FilterEq("at_uri", *comment.ReplyTo))
+		if err != nil {
+			return fmt.Errorf("failed to fetch parent comment: %w", err)
+		}
+		if len(parents) != 1 {
+			return fmt.Errorf("incorrect number of parent comments returned: %d", len(parents))
+		}
+
+		// depth check
+		parent := parents[0]
+		if parent.ReplyTo != nil {
+			return fmt.Errorf("incorrect depth, this comment is replying at depth >1")
+		}
+		if parent.DiscussionAt != comment.DiscussionAt {
+			return fmt.Errorf("parent comment belongs to a different discussion")
+		}
+	}
+
+	if sb := strings.TrimSpace(v.sanitizer.SanitizeDefault(comment.Body)); sb == "" {
+		return fmt.Errorf("body is empty after HTML sanitization")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateDiscussion(discussion *models.Discussion) error {
+	if discussion.Title == "" {
+		return fmt.Errorf("discussion title is empty")
+	}
+
+	if st := strings.TrimSpace(v.sanitizer.SanitizeDescription(discussion.Title)); st == "" {
+		return fmt.Errorf("title is empty after HTML sanitization")
+	}
+
+	repo, err := db.GetRepoByAtUri(v.db, discussion.RepoAt.String())
+	if err != nil {
+		return fmt.Errorf("failed to find repo: %w", err)
+	}
+	if !repo.IsPijul() {
+		return fmt.Errorf("discussions are only available for pijul repos")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateDiscussionPatch(patch *models.DiscussionPatch) error {
+	if patch.PatchHash == "" || patch.Patch == "" {
+		return fmt.Errorf("patch hash and content are required")
+	}
+
+	discussions, err := db.GetDiscussions(v.db, orm.FilterEq("at_uri", patch.DiscussionAt))
+	if err != nil {
+		return fmt.Errorf("failed to fetch discussion: %w", err)
+	}
+	if len(discussions) != 1 {
+		return fmt.Errorf("incorrect number of discussions returned: %d", len(discussions))
+	}
+	if !discussions[0].State.IsOpen() {
+		return fmt.Errorf("cannot add patches to a closed or merged discussion")
+	}
+
+	// the same record may be seen again, e.g. when the appview that created
+	// it ingests it, so only another record adding this patch is a duplicate
+	exists, err := db.PatchAddedByOtherRecord(v.db, patch.DiscussionAt, patch.PatchHash, patch.PushedByDid, patch.Rkey)
+	if err != nil {
+		return fmt.Errorf("failed to check patch existence: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("patch %s has already been added to the discussion", patch.PatchHash)
+	}
+
+	return nil
+}
@@ -1,9 +1,13 @@
 package xrpcclient
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
+	comatproto "github.com/bluesky-social/indigo/api/atproto"
+	atpclient "github.com/bluesky-social/indigo/atproto/client"
+	"github.com/bluesky-social/indigo/atproto/syntax"
 	indigoxrpc "github.com/bluesky-social/indigo/xrpc"
 )
 
@@ -34,3 +38,26 @@ func HandleXrpcErr(err error) error {
 		return ErrXrpcFailed
 	}
 }
+
+// RollbackRecord deletes a record created on the PDS, to undo it when a
+// later step fails
+//
+// it is a no-op if the provided ATURI is empty
+func RollbackRecord(ctx context.Context, aturi string, client *atpclient.APIClient) error {
+	if aturi == "" {
+		return nil
+	}
+
+	parsed := syntax.ATURI(aturi)
+
+	collection := parsed.Collection().String()
+	repo := parsed.Authority().String()
+	rkey := parsed.RecordKey().String()
+
+	_, err := comatproto.RepoDeleteRecord(ctx, client, &comatproto.RepoDeleteRecord_Input{
+		Collection: collection,
+		Repo:       repo,
+		Rkey:       rkey,
+	})
+	return err
+}
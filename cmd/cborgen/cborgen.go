@@ -24,6 +24,7 @@ func main() {
 		tangled.GitRefUpdate_LangBreakdown{},
 		tangled.GitRefUpdate_Meta{},
 		tangled.PijulRefUpdate{},
+		tangled.PijulRefUpdate_Languages{},
 		tangled.GraphFollow{},
 		tangled.Knot{},
 		tangled.KnotMember{},
@@ -33,6 +34,7 @@ func main() {
 		tangled.LabelOp_Operand{},
 		tangled.Pipeline{},
 		tangled.Pipeline_CloneOpts{},
+		tangled.Pipeline_DiscussionTriggerData{},
 		tangled.Pipeline_ManualTriggerData{},
 		tangled.Pipeline_Pair{},
 		tangled.Pipeline_PullRequestTriggerData{},
@@ -47,12 +49,16 @@ func main() {
 		tangled.RepoCollaborator{},
 		tangled.RepoDiscussion{},
 		tangled.RepoDiscussionComment{},
+		tangled.RepoDiscussionPatch{},
+		tangled.RepoDiscussionPatchState{},
 		tangled.RepoDiscussionState{},
 		tangled.RepoIssue{},
 		tangled.RepoIssueComment{},
 		tangled.RepoIssueState{},
 		tangled.RepoPull{},
 		tangled.RepoPullComment{},
+		tangled.RepoPullComment_Anchor{},
+		tangled.RepoPullReview{},
 		tangled.RepoPull_Source{},
 		tangled.RepoPullStatus{},
 		tangled.RepoPull_Target{},
@@ -1,7 +1,9 @@
 package guard
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -14,6 +16,8 @@ import (
 
 	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/urfave/cli/v3"
+	"tangled.org/core/hook"
+	"tangled.org/core/knotserver/pijul"
 	"tangled.org/core/log"
 )
 
@@ -144,17 +148,54 @@ func Run(ctx context.Context, cmd *cli.Command) error {
 			"fullPath", fullPath,
 			"client", clientIP)
 
+		// hold the repo lock through the session, so that the knot does
+		// not copy the pristine halfway through a push, nor write to it
+		// between the snapshots
+		unlock, err := pijul.LockRepo(fullPath)
+		if err != nil {
+			l.Error("failed to lock pijul repo", "error", err)
+			fmt.Fprintln(os.Stderr, "failed to lock repository")
+			return err
+		}
+
+		// pijul has no server-side hooks, so snapshot the channels around
+		// the protocol session to find out what a push changed
+		before, err := snapshotPijulRepo(fullPath)
+		if err != nil {
+			l.Error("failed to snapshot pijul repo", "error", err)
+			// non-fatal
+		}
+
 		pijulCmd := exec.Command("pijul", args...)
 		pijulCmd.Stdout = os.Stdout
 		pijulCmd.Stderr = os.Stderr
 		pijulCmd.Stdin = os.Stdin
 
 		if err := pijulCmd.Run(); err != nil {
+			unlock()
 			l.Error("command failed", "error", err)
 			fmt.Fprintf(os.Stderr, "command failed: %v\n", err)
 			return fmt.Errorf("command failed: %v", err)
 		}
 
+		var after map[string]pijul.ChannelSnapshot
+		if before != nil {
+			after, err = snapshotPijulRepo(fullPath)
+		}
+		// the knot reads the repo to process the push, so let go first
+		unlock()
+
+		if before != nil {
+			if err != nil {
+				l.Error("failed to snapshot pijul repo", "error", err)
+			} else if lines := pijul.DiffSnapshots(before, after); len(lines) > 0 {
+				if err := pijulPostPush(endpoint, fullPath, incomingUser, lines); err != nil {
+					l.Error("failed to run pijul post-push hook", "error", err)
+					// non-fatal
+				}
+			}
+		}
+
 		l.Info("command completed",
 			"user", incomingUser,
 			"command", "pijul protocol",
@@ -262,6 +303,53 @@ func parsePijulProtocolArgs(args []string) (string, string, error) {
 	return repo, version, nil
 }
 
+func snapshotPijulRepo(path string) (map[string]pijul.ChannelSnapshot, error) {
+	pr, err := pijul.PlainOpen(path)
+	if err != nil {
+		return nil, err
+	}
+	return pr.Snapshot()
+}
+
+// pijulPostPush notifies the knot of channels updated by a pijul push, and
+// relays any messages from the knot back to the client
+func pijulPostPush(endpoint, pijulDir, userDid string, lines []pijul.PostPushLine) error {
+	payload, err := json.Marshal(lines)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", endpoint+"/hooks/pijul-post-push", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Pijul-Dir", pijulDir)
+	req.Header.Set("X-Pijul-User-Did", userDid)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var data hook.HookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	for _, message := range data.Messages {
+		fmt.Fprintln(os.Stderr, message)
+	}
+
+	return nil
+}
+
 // runs guardAndQualifyRepo logic
 func guardAndQualifyRepo(l *slog.Logger, endpoint, incomingUser, repo, gitCommand string) (string, error) {
 	u, _ := url.Parse(endpoint + "/guard")
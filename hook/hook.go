@@ -2,11 +2,14 @@ package hook
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"os/exec"
 	"strings"
 
 	"github.com/urfave/cli/v3"
@@ -16,6 +19,19 @@ type HookResponse struct {
 	Messages []string `json:"messages"`
 }
 
+// RefUpdate is a ref update that the pre-receive hook asks the knot to
+// accept
+type RefUpdate struct {
+	OldSha string `json:"oldSha"`
+	NewSha string `json:"newSha"`
+	Ref    string `json:"ref"`
+
+	// the new sha does not descend from the old one
+	Forced bool `json:"forced"`
+}
+
+const zeroSha = "0000000000000000000000000000000000000000"
+
 // The hook command is nested like so:
 //
 //	knot hook --[flags] [hook]
@@ -47,6 +63,11 @@ func Command() *cli.Command {
 			},
 		},
 		Commands: []*cli.Command{
+			{
+				Name:   "pre-receive",
+				Usage:  "asks the knot to accept the ref updates of a push (waits for stdin)",
+				Action: preReceive,
+			},
 			{
 				Name:   "post-receive",
 				Usage:  "sends a post-receive hook to the knot (waits for stdin)",
@@ -104,3 +125,87 @@ func postReceive(ctx context.Context, cmd *cli.Command) error {
 
 	return nil
 }
+
+func preReceive(ctx context.Context, cmd *cli.Command) error {
+	gitDir := cmd.String("git-dir")
+	userDid := cmd.String("user-did")
+	userHandle := cmd.String("user-handle")
+	endpoint := cmd.String("internal-api")
+
+	var updates []RefUpdate
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), " ", 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		update := RefUpdate{
+			OldSha: parts[0],
+			NewSha: parts[1],
+			Ref:    parts[2],
+		}
+		update.Forced = isForced(ctx, gitDir, update)
+		updates = append(updates, update)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read ref updates: %w", err)
+	}
+
+	payload, err := json.Marshal(updates)
+	if err != nil {
+		return fmt.Errorf("failed to encode ref updates: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", "http://"+endpoint+"/hooks/pre-receive", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Git-Dir", gitDir)
+	req.Header.Set("X-Git-User-Did", userDid)
+	req.Header.Set("X-Git-User-Handle", userHandle)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusForbidden {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var data HookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// git relays the output of the hook to the client
+	for _, message := range data.Messages {
+		fmt.Fprintln(os.Stderr, message)
+	}
+
+	if resp.StatusCode == http.StatusForbidden {
+		return errors.New("push rejected by branch protection rules")
+	}
+
+	return nil
+}
+
+// isForced reports whether an update rewrites the history of a ref. It has to
+// be computed here rather than by the knot: the pushed objects are kept in a
+// quarantine directory that only the hook can see until the push is accepted.
+func isForced(ctx context.Context, gitDir string, update RefUpdate) bool {
+	if update.OldSha == zeroSha || update.NewSha == zeroSha {
+		return false
+	}
+
+	// exits with 1 if the old sha is not an ancestor of the new one, and
+	// with other codes if either cannot be read, which is treated as forced
+	// as well
+	cmd := exec.CommandContext(ctx, "git", "merge-base", "--is-ancestor", update.OldSha, update.NewSha)
+	cmd.Dir = gitDir
+	return cmd.Run() != nil
+}
@@ -101,23 +101,39 @@ func SetupUser(config config, userPath string) error {
 	return nil
 }
 
-// setup hook in /scanpath/did:plc:user/repo
+// setup hooks in /scanpath/did:plc:user/repo
 func SetupRepo(config config, path string) error {
 	if _, err := git.PlainOpen(path); err != nil {
 		return fmt.Errorf("%s: %w", path, ErrNoGitRepo)
 	}
 
-	preReceiveD := filepath.Join(path, "hooks", "post-receive.d")
-	if err := os.MkdirAll(preReceiveD, 0755); err != nil {
-		return fmt.Errorf("%s: %w", preReceiveD, ErrCreatingHookDir)
+	// pre-receive enforces branch protection rules, post-receive notifies
+	// the knot of accepted ref updates
+	if err := setupHook(config, path, "pre-receive", "40-protect.sh"); err != nil {
+		return err
+	}
+
+	if err := setupHook(config, path, "post-receive", "40-notify.sh"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setupHook installs a script calling `knot hook <hookName>` along with a
+// delegate that runs every script of the hook
+func setupHook(config config, path, hookName, scriptName string) error {
+	hookD := filepath.Join(path, "hooks", hookName+".d")
+	if err := os.MkdirAll(hookD, 0755); err != nil {
+		return fmt.Errorf("%s: %w", hookD, ErrCreatingHookDir)
 	}
 
-	notify := filepath.Join(preReceiveD, "40-notify.sh")
-	if err := mkHook(config, notify); err != nil {
-		return fmt.Errorf("%s: %w", notify, ErrCreatingHook)
+	script := filepath.Join(hookD, scriptName)
+	if err := mkHook(config, script, hookName); err != nil {
+		return fmt.Errorf("%s: %w", script, ErrCreatingHook)
 	}
 
-	delegate := filepath.Join(path, "hooks", "post-receive")
+	delegate := filepath.Join(path, "hooks", hookName)
 	if err := mkDelegate(delegate); err != nil {
 		return fmt.Errorf("%s: %w", delegate, ErrCreatingDelegate)
 	}
@@ -125,7 +141,7 @@ func SetupRepo(config config, path string) error {
 	return nil
 }
 
-func mkHook(config config, hookPath string) error {
+func mkHook(config config, hookPath, hookName string) error {
 	executablePath, err := os.Executable()
 	if err != nil {
 		return err
@@ -138,8 +154,8 @@ for ((i=0; i<GIT_PUSH_OPTION_COUNT; i++)); do
     option_var="GIT_PUSH_OPTION_$i"
     push_options+=(-push-option "${!option_var}")
 done
-%s hook -git-dir "$GIT_DIR" -user-did "$GIT_USER_DID" -user-handle "$GIT_USER_HANDLE" -internal-api "%s" "${push_options[@]}" post-receive
-	`, executablePath, config.internalApi)
+%s hook -git-dir "$GIT_DIR" -user-did "$GIT_USER_DID" -user-handle "$GIT_USER_HANDLE" -internal-api "%s" "${push_options[@]}" %s
+	`, executablePath, config.internalApi, hookName)
 
 	return os.WriteFile(hookPath, []byte(hookContent), 0755)
 }
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"strings"
+
+	"tangled.org/core/types"
+)
+
+// SetBranchProtection adds a protection rule to a repo, replacing the rule
+// with the same pattern if there is one
+func (d *DB) SetBranchProtection(repo string, p types.BranchProtection) error {
+	_, err := d.db.Exec(
+		`insert or replace into branch_protections (repo, pattern, no_force_push, no_deletion, require_pull, allowed_pushers)
+		values (?, ?, ?, ?, ?, ?)`,
+		repo, p.Pattern, p.NoForcePush, p.NoDeletion, p.RequirePull, strings.Join(p.AllowedPushers, ","),
+	)
+	return err
+}
+
+// GetBranchProtections returns the protection rules of a repo, sorted by
+// pattern
+func (d *DB) GetBranchProtections(repo string) ([]types.BranchProtection, error) {
+	var rules []types.BranchProtection
+
+	rows, err := d.db.Query(
+		`select pattern, no_force_push, no_deletion, require_pull, allowed_pushers
+		from branch_protections where repo = ? order by pattern asc`,
+		repo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p types.BranchProtection
+		var allowedPushers string
+		if err := rows.Scan(&p.Pattern, &p.NoForcePush, &p.NoDeletion, &p.RequirePull, &allowedPushers); err != nil {
+			return nil, err
+		}
+		if allowedPushers != "" {
+			p.AllowedPushers = strings.Split(allowedPushers, ",")
+		}
+		rules = append(rules, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (d *DB) RemoveBranchProtection(repo, pattern string) error {
+	_, err := d.db.Exec(`delete from branch_protections where repo = ? and pattern = ?`, repo, pattern)
+	return err
+}
+
+func (d *DB) RemoveBranchProtections(repo string) error {
+	_, err := d.db.Exec(`delete from branch_protections where repo = ?`, repo)
+	return err
+}
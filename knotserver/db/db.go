@@ -65,6 +65,38 @@ func Setup(ctx context.Context, dbPath string) (*DB, error) {
 			primary key (rkey, nsid)
 		);
 
+		-- patches added to discussions on pijul repos of this knot, used to
+		-- run pipelines against the active patches of a discussion
+		create table if not exists discussion_patches (
+			patch_at text primary key,
+			discussion_at text not null,
+			repo text not null, -- did/name
+			hash text not null,
+			removed integer not null default 0,
+			created integer not null default (strftime('%s', 'now'))
+		);
+
+		-- upstreams of pijul repos on this knot that were forked from
+		-- another repo, used to compare and sync forks
+		create table if not exists pijul_forks (
+			repo text primary key, -- did/name
+			upstream text not null, -- clone url
+			created integer not null default (strftime('%s', 'now'))
+		);
+
+		-- protection rules of git repos on this knot, enforced when branches
+		-- are pushed to, merged into or deleted
+		create table if not exists branch_protections (
+			repo text not null, -- did/name
+			pattern text not null,
+			no_force_push integer not null default 0,
+			no_deletion integer not null default 0,
+			require_pull integer not null default 0,
+			allowed_pushers text not null default '', -- comma separated dids
+			created integer not null default (strftime('%s', 'now')),
+			primary key (repo, pattern)
+		);
+
 		create table if not exists migrations (
 			id integer primary key autoincrement,
 			name text unique
@@ -0,0 +1,63 @@
+package db
+
+type DiscussionPatch struct {
+	PatchAt      string
+	DiscussionAt string
+	Repo         string // did/name
+	Hash         string
+	Removed      bool
+}
+
+func (d *DB) AddDiscussionPatch(p DiscussionPatch) error {
+	_, err := d.db.Exec(
+		`insert or ignore into discussion_patches (patch_at, discussion_at, repo, hash) values (?, ?, ?, ?)`,
+		p.PatchAt, p.DiscussionAt, p.Repo, p.Hash,
+	)
+	return err
+}
+
+func (d *DB) GetDiscussionPatch(patchAt string) (*DiscussionPatch, error) {
+	p := DiscussionPatch{PatchAt: patchAt}
+	err := d.db.QueryRow(
+		`select discussion_at, repo, hash, removed from discussion_patches where patch_at = ?`,
+		patchAt,
+	).Scan(&p.DiscussionAt, &p.Repo, &p.Hash, &p.Removed)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (d *DB) SetDiscussionPatchRemoved(patchAt string, removed bool) error {
+	_, err := d.db.Exec(`update discussion_patches set removed = ? where patch_at = ?`, removed, patchAt)
+	return err
+}
+
+// GetActiveDiscussionPatches returns the hashes of the patches of a
+// discussion that have not been removed, oldest first
+func (d *DB) GetActiveDiscussionPatches(discussionAt string) ([]string, error) {
+	var hashes []string
+
+	rows, err := d.db.Query(
+		`select hash from discussion_patches where discussion_at = ? and removed = 0 order by created asc, rowid asc`,
+		discussionAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
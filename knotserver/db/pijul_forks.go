@@ -0,0 +1,22 @@
+package db
+
+func (d *DB) AddPijulFork(repo, upstream string) error {
+	_, err := d.db.Exec(
+		`insert or replace into pijul_forks (repo, upstream) values (?, ?)`,
+		repo, upstream,
+	)
+	return err
+}
+
+// GetPijulForkUpstream returns the clone url of the upstream of a fork,
+// sql.ErrNoRows is returned for repos that are not forks
+func (d *DB) GetPijulForkUpstream(repo string) (string, error) {
+	var upstream string
+	err := d.db.QueryRow(`select upstream from pijul_forks where repo = ?`, repo).Scan(&upstream)
+	return upstream, err
+}
+
+func (d *DB) RemovePijulFork(repo string) error {
+	_, err := d.db.Exec(`delete from pijul_forks where repo = ?`, repo)
+	return err
+}
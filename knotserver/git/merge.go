@@ -3,6 +3,7 @@ package git
 import (
 	"bytes"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -95,8 +96,24 @@ type MergeOptions struct {
 	CommitterName  string
 	CommitterEmail string
 	FormatPatch    bool
+
+	// Strategy decides how the commits land on the target branch, they are
+	// rebased onto it if unset
+	Strategy types.MergeStrategy
 }
 
+// ErrNotFastForward is returned by the fast-forward strategy when the target
+// branch has commits that the source branch lacks
+var ErrNotFastForward = errors.New("target branch cannot be fast-forwarded")
+
+// ErrSourceMoved is returned by the fast-forward strategy when the source
+// branch is not at the commit it was expected at
+var ErrSourceMoved = errors.New("source branch has moved")
+
+// mergeSideBranch is where the merge commit strategy applies a patch before
+// merging it into the target branch
+const mergeSideBranch = "tangled-merge"
+
 func (e ErrMerge) Error() string {
 	if e.HasConflict {
 		return fmt.Sprintf("merge failed due to conflicts: %s (%d conflicts)", e.Message, len(e.Conflicts))
@@ -147,15 +164,19 @@ func (g *GitRepo) cloneTemp(targetBranch string) (string, error) {
 	return tmpDir, nil
 }
 
+func (g *GitRepo) configureCommitter(opts MergeOptions) {
+	exec.Command("git", "-C", g.path, "config", "user.name", opts.CommitterName).Run()
+	exec.Command("git", "-C", g.path, "config", "user.email", opts.CommitterEmail).Run()
+	exec.Command("git", "-C", g.path, "config", "advice.mergeConflict", "false").Run()
+	exec.Command("git", "-C", g.path, "config", "advice.amWorkDir", "false").Run()
+}
+
 func (g *GitRepo) applyPatch(patchData, patchFile string, opts MergeOptions) error {
 	var stderr bytes.Buffer
 	var cmd *exec.Cmd
 
 	// configure default git user before merge
-	exec.Command("git", "-C", g.path, "config", "user.name", opts.CommitterName).Run()
-	exec.Command("git", "-C", g.path, "config", "user.email", opts.CommitterEmail).Run()
-	exec.Command("git", "-C", g.path, "config", "advice.mergeConflict", "false").Run()
-	exec.Command("git", "-C", g.path, "config", "advice.amWorkDir", "false").Run()
+	g.configureCommitter(opts)
 
 	// if patch is a format-patch, apply using 'git am'
 	if opts.FormatPatch {
@@ -174,6 +195,24 @@ func (g *GitRepo) applyPatch(patchData, patchFile string, opts MergeOptions) err
 		return fmt.Errorf("failed to stage changes: %w", err)
 	}
 
+	cmd = exec.Command("git", g.commitArgs(opts)...)
+
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		conflicts := parseGitApplyErrors(stderr.String())
+		return &ErrMerge{
+			Message:     "patch cannot be applied cleanly",
+			Conflicts:   conflicts,
+			HasConflict: len(conflicts) > 0,
+			OtherError:  err,
+		}
+	}
+
+	return nil
+}
+
+func (g *GitRepo) commitArgs(opts MergeOptions) []string {
 	commitArgs := []string{"-C", g.path, "commit"}
 
 	// Set author if provided
@@ -191,21 +230,94 @@ func (g *GitRepo) applyPatch(patchData, patchFile string, opts MergeOptions) err
 		commitArgs = append(commitArgs, "-m", opts.CommitBody)
 	}
 
-	cmd = exec.Command("git", commitArgs...)
+	return commitArgs
+}
 
-	cmd.Stderr = &stderr
+// merge lands a patch on the checked out target branch using the strategy
+// of opts
+func (g *GitRepo) merge(patchData, patchFile, targetBranch string, opts MergeOptions) error {
+	switch opts.Strategy {
+	case types.MergeStrategyMerge:
+		return g.mergeCommit(patchData, patchFile, targetBranch, opts)
+	case types.MergeStrategySquash:
+		return g.squash(patchData, patchFile, opts)
+	default:
+		return g.applyPatch(patchData, patchFile, opts)
+	}
+}
 
-	if err := cmd.Run(); err != nil {
-		conflicts := parseGitApplyErrors(stderr.String())
+// mergeCommit applies a patch on a side branch and merges that into the
+// target branch with a merge commit, keeping the commits of the patch intact
+func (g *GitRepo) mergeCommit(patchData, patchFile, targetBranch string, opts MergeOptions) error {
+	var stderr bytes.Buffer
+
+	checkout := exec.Command("git", "-C", g.path, "checkout", "-q", "-b", mergeSideBranch)
+	checkout.Stderr = &stderr
+	if err := checkout.Run(); err != nil {
+		return fmt.Errorf("failed to create side branch: %s", stderr.String())
+	}
+
+	if err := g.applyPatch(patchData, patchFile, opts); err != nil {
+		return err
+	}
+
+	stderr.Reset()
+	checkout = exec.Command("git", "-C", g.path, "checkout", "-q", targetBranch)
+	checkout.Stderr = &stderr
+	if err := checkout.Run(); err != nil {
+		return fmt.Errorf("failed to check out target branch: %s", stderr.String())
+	}
+
+	mergeArgs := []string{"-C", g.path, "merge", "--no-ff", "-m", opts.CommitMessage}
+	if opts.CommitBody != "" {
+		mergeArgs = append(mergeArgs, "-m", opts.CommitBody)
+	}
+	mergeArgs = append(mergeArgs, mergeSideBranch)
+
+	stderr.Reset()
+	merge := exec.Command("git", mergeArgs...)
+	merge.Stderr = &stderr
+	if err := merge.Run(); err != nil {
 		return &ErrMerge{
-			Message:     "patch cannot be applied cleanly",
-			Conflicts:   conflicts,
-			HasConflict: len(conflicts) > 0,
-			OtherError:  err,
+			Message:    "failed to create merge commit",
+			OtherError: fmt.Errorf("%w: %s", err, stderr.String()),
 		}
 	}
 
-	return nil
+	return g.Refresh()
+}
+
+// squash applies a patch and folds all of its commits into one
+func (g *GitRepo) squash(patchData, patchFile string, opts MergeOptions) error {
+	// plain patches are applied as a single commit already
+	if !opts.FormatPatch {
+		return g.applyPatch(patchData, patchFile, opts)
+	}
+
+	base, err := g.r.Head()
+	if err != nil {
+		return err
+	}
+
+	if err := g.applyPatch(patchData, patchFile, opts); err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	reset := exec.Command("git", "-C", g.path, "reset", "--soft", base.Hash().String())
+	reset.Stderr = &stderr
+	if err := reset.Run(); err != nil {
+		return fmt.Errorf("failed to squash commits: %s", stderr.String())
+	}
+
+	stderr.Reset()
+	commit := exec.Command("git", g.commitArgs(opts)...)
+	commit.Stderr = &stderr
+	if err := commit.Run(); err != nil {
+		return fmt.Errorf("failed to commit squashed changes: %s", stderr.String())
+	}
+
+	return g.Refresh()
 }
 
 func (g *GitRepo) applyMailbox(patchData string) error {
@@ -376,7 +488,7 @@ func (g *GitRepo) MergeWithOptions(patchData string, targetBranch string, opts M
 		return err
 	}
 
-	if err := tmpRepo.applyPatch(patchData, patchFile, opts); err != nil {
+	if err := tmpRepo.merge(patchData, patchFile, targetBranch, opts); err != nil {
 		return err
 	}
 
@@ -391,6 +503,68 @@ func (g *GitRepo) MergeWithOptions(patchData string, targetBranch string, opts M
 	return nil
 }
 
+// FastForward moves targetBranch to the tip of sourceBranch, which must
+// descend from it, without creating any commit. When sourceRev is set, the
+// source branch must be at that commit. It returns the ref update it made,
+// as no hook runs for it.
+func (g *GitRepo) FastForward(sourceBranch, targetBranch, sourceRev string) (*PostReceiveLine, error) {
+	source, err := g.r.Reference(plumbing.NewBranchReferenceName(sourceBranch), true)
+	if err != nil {
+		return nil, fmt.Errorf("source branch %s: %w", sourceBranch, err)
+	}
+	target, err := g.r.Reference(plumbing.NewBranchReferenceName(targetBranch), true)
+	if err != nil {
+		return nil, fmt.Errorf("target branch %s: %w", targetBranch, err)
+	}
+
+	if sourceRev != "" && source.Hash().String() != sourceRev {
+		return nil, &ErrMerge{
+			Message:    fmt.Sprintf("%s is at %s, not %s", sourceBranch, source.Hash().String()[:7], sourceRev),
+			OtherError: ErrSourceMoved,
+		}
+	}
+
+	sourceCommit, err := g.r.CommitObject(source.Hash())
+	if err != nil {
+		return nil, err
+	}
+	targetCommit, err := g.r.CommitObject(target.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := targetCommit.IsAncestor(sourceCommit)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &ErrMerge{
+			Message:    fmt.Sprintf("%s has commits that %s does not", targetBranch, sourceBranch),
+			OtherError: ErrNotFastForward,
+		}
+	}
+
+	// passing the old value makes git refuse the update if the target moved
+	// in the meantime
+	var stderr bytes.Buffer
+	updateRef := exec.Command("git", "-C", g.path, "update-ref",
+		"-m", "fast-forward to "+sourceBranch,
+		target.Name().String(), source.Hash().String(), target.Hash().String())
+	updateRef.Stderr = &stderr
+	if err := updateRef.Run(); err != nil {
+		return nil, &ErrMerge{
+			Message:    "failed to update target branch",
+			OtherError: fmt.Errorf("%w: %s", err, stderr.String()),
+		}
+	}
+
+	return &PostReceiveLine{
+		OldSha: target.Hash(),
+		NewSha: source.Hash(),
+		Ref:    target.Name().String(),
+	}, nil
+}
+
 func parseGitApplyErrors(errorOutput string) []ConflictInfo {
 	var conflicts []ConflictInfo
 	lines := strings.Split(errorOutput, "\n")
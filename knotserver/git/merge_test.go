@@ -12,6 +12,7 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"tangled.org/core/types"
 )
 
 type Helper struct {
@@ -704,3 +705,92 @@ index 0000000..5e1c309
 	require.NoError(t, err)
 	assert.Equal(t, "Add feature", strings.TrimSpace(commit.Message))
 }
+
+func TestMerge_MergeCommit(t *testing.T) {
+	h := helper(t)
+	defer h.cleanup()
+
+	repo := h.initRepo()
+	head, err := repo.r.Head()
+	require.NoError(t, err)
+
+	patch := `diff --git a/feature.txt b/feature.txt
+new file mode 100644
+index 0000000..5e1c309
+--- /dev/null
++++ b/feature.txt
+@@ -0,0 +1 @@
++Hello World
+`
+
+	patchFile, err := createTemp(patch)
+	require.NoError(t, err)
+	defer os.Remove(patchFile)
+
+	opts := MergeOptions{
+		CommitMessage:  "Merge feature",
+		CommitterName:  "Test Committer",
+		CommitterEmail: "committer@example.com",
+		Strategy:       types.MergeStrategyMerge,
+	}
+
+	err = repo.merge(patch, patchFile, head.Name().Short(), opts)
+	require.NoError(t, err)
+
+	newHead, err := repo.r.Head()
+	require.NoError(t, err)
+	assert.Equal(t, head.Name(), newHead.Name())
+
+	commit, err := repo.r.CommitObject(newHead.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, "Merge feature", strings.TrimSpace(commit.Message))
+	assert.Len(t, commit.ParentHashes, 2)
+	assert.Equal(t, head.Hash(), commit.ParentHashes[0])
+
+	assert.True(t, h.fileExists("feature.txt"))
+}
+
+func TestFastForward(t *testing.T) {
+	h := helper(t)
+	defer h.cleanup()
+
+	repo := h.initRepo()
+	head, err := repo.r.Head()
+	require.NoError(t, err)
+	target := head.Name().Short()
+
+	// the feature branch starts at the tip of the target
+	w, err := repo.r.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, w.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName("feature"),
+		Create: true,
+	}))
+	tip := h.commitFile("feature.txt", "feature\n", "Add feature")
+
+	_, err = repo.FastForward("feature", target, "0000000")
+	var mergeErr *ErrMerge
+	require.ErrorAs(t, err, &mergeErr)
+	assert.ErrorIs(t, mergeErr.OtherError, ErrSourceMoved)
+
+	line, err := repo.FastForward("feature", target, tip.String())
+	require.NoError(t, err)
+	assert.Equal(t, head.Hash(), line.OldSha)
+	assert.Equal(t, tip, line.NewSha)
+
+	moved, err := repo.r.Reference(head.Name(), true)
+	require.NoError(t, err)
+	assert.Equal(t, tip, moved.Hash(), "the target should point at the tip of the branch, with no new commit")
+
+	// once the target has commits of its own it can no longer be fast-forwarded
+	require.NoError(t, w.Checkout(&git.CheckoutOptions{Branch: head.Name()}))
+	h.commitFile("other.txt", "other\n", "Add other")
+	require.NoError(t, w.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName("feature"),
+	}))
+	h.commitFile("feature.txt", "more\n", "Extend feature")
+
+	_, err = repo.FastForward("feature", target, "")
+	require.ErrorAs(t, err, &mergeErr)
+	assert.ErrorIs(t, mergeErr.OtherError, ErrNotFastForward)
+}
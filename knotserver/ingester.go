@@ -350,6 +350,10 @@ func (h *Knot) processMessages(ctx context.Context, event *models.Event) error {
 		err = h.processKnotMember(ctx, event)
 	case tangled.RepoPullNSID:
 		err = h.processPull(ctx, event)
+	case tangled.RepoDiscussionPatchNSID:
+		err = h.processDiscussionPatch(ctx, event)
+	case tangled.RepoDiscussionPatchStateNSID:
+		err = h.processDiscussionPatchState(ctx, event)
 	case tangled.RepoCollaboratorNSID:
 		err = h.processCollaborator(ctx, event)
 	}
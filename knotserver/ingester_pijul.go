@@ -0,0 +1,291 @@
+package knotserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	comatproto "github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/atproto/identity"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/bluesky-social/indigo/xrpc"
+	"github.com/bluesky-social/jetstream/pkg/models"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/knotserver/db"
+	"tangled.org/core/knotserver/pijul"
+	"tangled.org/core/log"
+	"tangled.org/core/rbac"
+	"tangled.org/core/workflow"
+)
+
+// discussionTarget is the pijul repo on this knot that a discussion belongs to
+type discussionTarget struct {
+	ownerDid     string
+	repoName     string
+	repoPath     string
+	channel      string
+	knot         string
+	discussion   syntax.ATURI
+	didSlashRepo string
+}
+
+func (h *Knot) processDiscussionPatch(ctx context.Context, event *models.Event) error {
+	raw := json.RawMessage(event.Commit.Record)
+	did := event.Did
+
+	var record tangled.RepoDiscussionPatch
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return fmt.Errorf("failed to unmarshal record: %w", err)
+	}
+
+	l := log.FromContext(ctx)
+	l = l.With("handler", "processDiscussionPatch")
+	l = l.With("did", did)
+	l = l.With("discussion", record.Discussion)
+
+	discussionAt, err := syntax.ParseATURI(record.Discussion)
+	if err != nil {
+		return fmt.Errorf("failed to parse ATURI: %w", err)
+	}
+
+	target, err := h.discussionTarget(ctx, discussionAt)
+	if err != nil {
+		return err
+	}
+
+	patchAt := fmt.Sprintf("at://%s/%s/%s", did, tangled.RepoDiscussionPatchNSID, event.Commit.RKey)
+	err = h.db.AddDiscussionPatch(db.DiscussionPatch{
+		PatchAt:      patchAt,
+		DiscussionAt: discussionAt.String(),
+		Repo:         target.didSlashRepo,
+		Hash:         record.PatchHash,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add discussion patch: %w", err)
+	}
+
+	l.Info("added discussion patch", "patch", patchAt)
+
+	return h.triggerDiscussionPipeline(target, record.PatchHash, "create")
+}
+
+func (h *Knot) processDiscussionPatchState(ctx context.Context, event *models.Event) error {
+	raw := json.RawMessage(event.Commit.Record)
+	did := event.Did
+
+	var record tangled.RepoDiscussionPatchState
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return fmt.Errorf("failed to unmarshal record: %w", err)
+	}
+
+	patch, err := h.db.GetDiscussionPatch(record.Patch)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("ignoring patch state record: unknown patch %s", record.Patch)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get discussion patch: %w", err)
+	}
+
+	// the pusher of a patch can always change its state, others need to be
+	// able to edit discussions on this repo
+	patchAt, err := syntax.ParseATURI(record.Patch)
+	if err != nil {
+		return fmt.Errorf("failed to parse ATURI: %w", err)
+	}
+	if patchAt.Authority().String() != did {
+		ok, err := h.e.E.Enforce(did, rbac.ThisServer, patch.Repo, rbac.PijulEditDiscussion)
+		if err != nil || !ok {
+			return fmt.Errorf("insufficient permissions: %s, %s, %s", did, rbac.PijulEditDiscussion, patch.Repo)
+		}
+	}
+
+	// states are "active" or "removed"
+	removed := record.State == "removed"
+	if err := h.db.SetDiscussionPatchRemoved(record.Patch, removed); err != nil {
+		return fmt.Errorf("failed to update discussion patch: %w", err)
+	}
+	if removed {
+		return nil
+	}
+
+	discussionAt, err := syntax.ParseATURI(patch.DiscussionAt)
+	if err != nil {
+		return fmt.Errorf("failed to parse ATURI: %w", err)
+	}
+
+	target, err := h.discussionTarget(ctx, discussionAt)
+	if err != nil {
+		return err
+	}
+
+	return h.triggerDiscussionPipeline(target, patch.Hash, "readd")
+}
+
+// discussionTarget fetches the discussion record and the repo record it
+// points to, and checks that the repo is a pijul repo on this knot
+func (h *Knot) discussionTarget(ctx context.Context, discussionAt syntax.ATURI) (*discussionTarget, error) {
+	discussionVal, _, err := h.getRecord(ctx, discussionAt, tangled.RepoDiscussionNSID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve discussion: %w", err)
+	}
+	discussion, ok := discussionVal.(*tangled.RepoDiscussion)
+	if !ok {
+		return nil, fmt.Errorf("unexpected discussion record: %T", discussionVal)
+	}
+
+	repoAt, err := syntax.ParseATURI(discussion.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ATURI: %w", err)
+	}
+
+	repoVal, owner, err := h.getRecord(ctx, repoAt, tangled.RepoNSID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve repo: %w", err)
+	}
+	repo, ok := repoVal.(*tangled.Repo)
+	if !ok {
+		return nil, fmt.Errorf("unexpected repo record: %T", repoVal)
+	}
+
+	if repo.Knot != h.c.Server.Hostname {
+		return nil, fmt.Errorf("rejected discussion: not this knot, %s != %s", repo.Knot, h.c.Server.Hostname)
+	}
+
+	didSlashRepo, err := securejoin.SecureJoin(owner.DID.String(), repo.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct relative repo path: %w", err)
+	}
+
+	repoPath, err := securejoin.SecureJoin(h.c.Repo.ScanPath, didSlashRepo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct absolute repo path: %w", err)
+	}
+
+	if !pijul.IsPijulRepo(repoPath) {
+		return nil, fmt.Errorf("ignoring discussion: not a pijul repo")
+	}
+
+	var channel string
+	if discussion.TargetChannel != nil && *discussion.TargetChannel != "" {
+		channel = *discussion.TargetChannel
+	} else {
+		pr, err := pijul.PlainOpen(repoPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open repo: %w", err)
+		}
+		if channel, err = pr.FindDefaultChannel(); err != nil {
+			return nil, fmt.Errorf("failed to find default channel: %w", err)
+		}
+	}
+
+	return &discussionTarget{
+		ownerDid:     owner.DID.String(),
+		repoName:     repo.Name,
+		repoPath:     repoPath,
+		channel:      channel,
+		knot:         repo.Knot,
+		discussion:   discussionAt,
+		didSlashRepo: didSlashRepo,
+	}, nil
+}
+
+// getRecord fetches a record from the pds of its author
+func (h *Knot) getRecord(ctx context.Context, uri syntax.ATURI, collection string) (any, *identity.Identity, error) {
+	ident, err := h.resolver.ResolveIdent(ctx, uri.Authority().String())
+	if err != nil || ident.Handle.IsInvalidHandle() {
+		return nil, nil, fmt.Errorf("failed to resolve handle: %w", err)
+	}
+
+	xrpcc := xrpc.Client{
+		Host: ident.PDSEndpoint(),
+	}
+
+	resp, err := comatproto.RepoGetRecord(ctx, &xrpcc, "", collection, uri.Authority().String(), uri.RecordKey().String())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp.Value.Val, ident, nil
+}
+
+// triggerDiscussionPipeline runs the workflows of the target channel
+// against the active patches of the discussion
+func (h *Knot) triggerDiscussionPipeline(target *discussionTarget, patchHash, action string) error {
+	patches, err := h.db.GetActiveDiscussionPatches(target.discussion.String())
+	if err != nil {
+		return fmt.Errorf("failed to get active patches: %w", err)
+	}
+
+	pr, err := pijul.Open(target.repoPath, target.channel)
+	if err != nil {
+		return fmt.Errorf("failed to open pijul repository: %w", err)
+	}
+
+	workflowDir, err := pr.FileTree(context.Background(), workflow.WorkflowDir)
+	if err != nil {
+		return fmt.Errorf("failed to open workflow directory: %w", err)
+	}
+
+	var pipeline workflow.RawPipeline
+	for _, e := range workflowDir {
+		if !e.IsFile() {
+			continue
+		}
+
+		fpath := filepath.Join(workflow.WorkflowDir, e.Name)
+		contents, err := pr.RawContent(fpath)
+		if err != nil {
+			continue
+		}
+
+		pipeline = append(pipeline, workflow.RawWorkflow{
+			Name:     e.Name,
+			Contents: contents,
+		})
+	}
+
+	vcs := "pijul"
+	trigger := tangled.Pipeline_DiscussionTriggerData{
+		Action:        action,
+		Discussion:    target.discussion.String(),
+		PatchHash:     patchHash,
+		Patches:       patches,
+		TargetChannel: target.channel,
+	}
+
+	compiler := workflow.Compiler{
+		Trigger: tangled.Pipeline_TriggerMetadata{
+			Kind:       string(workflow.TriggerKindDiscussion),
+			Discussion: &trigger,
+			Repo: &tangled.Pipeline_TriggerRepo{
+				Did:  target.ownerDid,
+				Knot: target.knot,
+				Repo: target.repoName,
+				Vcs:  &vcs,
+			},
+		},
+	}
+
+	cp := compiler.Compile(compiler.Parse(pipeline))
+	eventJson, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pipeline event: %w", err)
+	}
+
+	// do not run empty pipelines
+	if cp.Workflows == nil {
+		return nil
+	}
+
+	ev := db.Event{
+		Rkey:      TID(),
+		Nsid:      tangled.PipelineNSID,
+		EventJson: string(eventJson),
+	}
+
+	return h.db.InsertEvent(ev, h.n)
+}
@@ -23,6 +23,7 @@ import (
 	"tangled.org/core/log"
 	"tangled.org/core/notifier"
 	"tangled.org/core/rbac"
+	"tangled.org/core/types"
 	"tangled.org/core/workflow"
 )
 
@@ -136,6 +137,88 @@ func (h *InternalHandle) Guard(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, qualifiedRepo)
 }
 
+// PreReceiveHook checks the ref updates of a push against the branch
+// protection rules of the repo. Rejecting any update fails the whole push, as
+// git either accepts all updates of a push or none of them.
+func (h *InternalHandle) PreReceiveHook(w http.ResponseWriter, r *http.Request) {
+	l := h.l.With("handler", "PreReceiveHook")
+
+	gitAbsoluteDir := r.Header.Get("X-Git-Dir")
+	gitRelativeDir, err := filepath.Rel(h.c.Repo.ScanPath, gitAbsoluteDir)
+	if err != nil {
+		l.Error("failed to calculate relative git dir", "scanPath", h.c.Repo.ScanPath, "gitAbsoluteDir", gitAbsoluteDir)
+		writeError(w, "invalid git dir", http.StatusBadRequest)
+		return
+	}
+
+	parts := strings.SplitN(gitRelativeDir, "/", 2)
+	if len(parts) != 2 {
+		l.Error("invalid git dir", "gitRelativeDir", gitRelativeDir)
+		writeError(w, "invalid git dir", http.StatusBadRequest)
+		return
+	}
+
+	didSlashRepo, err := securejoin.SecureJoin(parts[0], parts[1])
+	if err != nil {
+		writeError(w, "invalid git dir", http.StatusBadRequest)
+		return
+	}
+
+	resp := hook.HookResponse{
+		Messages: make([]string, 0),
+	}
+
+	// pushes made by the knot itself, such as merges, have no user; they are
+	// checked by the handlers making them
+	gitUserDid := r.Header.Get("X-Git-User-Did")
+	if gitUserDid == "" {
+		writeJSON(w, resp)
+		return
+	}
+
+	var updates []hook.RefUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+		l.Error("failed to parse pre-receive payload", "err", err)
+		writeError(w, "invalid ref updates", http.StatusBadRequest)
+		return
+	}
+
+	rules, err := h.db.GetBranchProtections(didSlashRepo)
+	if err != nil {
+		l.Error("failed to get branch protections", "err", err, "repo", didSlashRepo)
+		writeError(w, "failed to get branch protections", http.StatusInternalServerError)
+		return
+	}
+
+	for _, u := range updates {
+		branch, ok := strings.CutPrefix(u.Ref, "refs/heads/")
+		if !ok {
+			continue
+		}
+
+		err := types.CheckBranchUpdate(rules, types.BranchUpdate{
+			Branch: branch,
+			Pusher: gitUserDid,
+			Create: u.OldSha == plumbing.ZeroHash.String(),
+			Delete: u.NewSha == plumbing.ZeroHash.String(),
+			Force:  u.Forced,
+		})
+		if err != nil {
+			l.Info("rejected ref update", "ref", u.Ref, "did", gitUserDid, "repo", didSlashRepo, "reason", err)
+			resp.Messages = append(resp.Messages, fmt.Sprintf("rejected %s: %s", branch, err))
+		}
+	}
+
+	if len(resp.Messages) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	writeJSON(w, resp)
+}
+
 type PushOptions struct {
 	skipCi    bool
 	verboseCi bool
@@ -180,30 +263,40 @@ func (h *InternalHandle) PostReceiveHook(w http.ResponseWriter, r *http.Request)
 	}
 
 	resp := hook.HookResponse{
-		Messages: make([]string, 0),
+		Messages: h.processGitPush(lines, gitUserDid, repoDid, repoName, pushOptions),
 	}
 
+	writeJSON(w, resp)
+}
+
+// processGitPush records the refs updated by a git push, and runs the
+// pipelines for them. It returns the messages to relay to the pusher.
+func (h *InternalHandle) processGitPush(lines []git.PostReceiveLine, gitUserDid, repoDid, repoName string, pushOptions PushOptions) []string {
+	l := h.l.With("handler", "processGitPush")
+	didSlashRepo := filepath.Join(repoDid, repoName)
+	messages := make([]string, 0)
+
 	for _, line := range lines {
 		err := h.insertRefUpdate(line, gitUserDid, repoDid, repoName)
 		if err != nil {
-			l.Error("failed to insert op", "err", err, "line", line, "did", gitUserDid, "repo", gitRelativeDir)
+			l.Error("failed to insert op", "err", err, "line", line, "did", gitUserDid, "repo", didSlashRepo)
 			// non-fatal
 		}
 
-		err = h.emitCompareLink(&resp.Messages, line, repoDid, repoName)
+		err = h.emitCompareLink(&messages, line, repoDid, repoName)
 		if err != nil {
-			l.Error("failed to reply with compare link", "err", err, "line", line, "did", gitUserDid, "repo", gitRelativeDir)
+			l.Error("failed to reply with compare link", "err", err, "line", line, "did", gitUserDid, "repo", didSlashRepo)
 			// non-fatal
 		}
 
-		err = h.triggerPipeline(&resp.Messages, line, gitUserDid, repoDid, repoName, pushOptions)
+		err = h.triggerPipeline(&messages, line, gitUserDid, repoDid, repoName, pushOptions)
 		if err != nil {
-			l.Error("failed to trigger pipeline", "err", err, "line", line, "did", gitUserDid, "repo", gitRelativeDir)
+			l.Error("failed to trigger pipeline", "err", err, "line", line, "did", gitUserDid, "repo", didSlashRepo)
 			// non-fatal
 		}
 	}
 
-	writeJSON(w, resp)
+	return messages
 }
 
 func (h *InternalHandle) insertRefUpdate(line git.PostReceiveLine, gitUserDid, repoDid, repoName string) error {
@@ -307,16 +400,27 @@ func (h *InternalHandle) triggerPipeline(
 		NewSha: line.NewSha.String(),
 	}
 
-	compiler := workflow.Compiler{
-		Trigger: tangled.Pipeline_TriggerMetadata{
-			Kind: string(workflow.TriggerKindPush),
-			Push: &trigger,
-			Repo: &tangled.Pipeline_TriggerRepo{
-				Did:  repoDid,
-				Knot: h.c.Server.Hostname,
-				Repo: repoName,
-			},
+	return h.compilePipeline(clientMsgs, pipeline, tangled.Pipeline_TriggerMetadata{
+		Kind: string(workflow.TriggerKindPush),
+		Push: &trigger,
+		Repo: &tangled.Pipeline_TriggerRepo{
+			Did:  repoDid,
+			Knot: h.c.Server.Hostname,
+			Repo: repoName,
 		},
+	}, pushOptions)
+}
+
+// compilePipeline compiles the raw workflows against the given trigger and
+// inserts the resulting pipeline as an event
+func (h *InternalHandle) compilePipeline(
+	clientMsgs *[]string,
+	pipeline workflow.RawPipeline,
+	trigger tangled.Pipeline_TriggerMetadata,
+	pushOptions PushOptions,
+) error {
+	compiler := workflow.Compiler{
+		Trigger: trigger,
 	}
 
 	cp := compiler.Compile(compiler.Parse(pipeline))
@@ -435,7 +539,9 @@ func Internal(ctx context.Context, c *config.Config, db *db.DB, e *rbac.Enforcer
 	r.Get("/push-allowed", h.PushAllowed)
 	r.Get("/keys", h.InternalKeys)
 	r.Get("/guard", h.Guard)
+	r.Post("/hooks/pre-receive", h.PreReceiveHook)
 	r.Post("/hooks/post-receive", h.PostReceiveHook)
+	r.Post("/hooks/pijul-post-push", h.PijulPostPushHook)
 	r.Mount("/debug", middleware.Profiler())
 
 	return r
@@ -0,0 +1,221 @@
+package knotserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/go-git/go-git/v5/plumbing"
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/hook"
+	"tangled.org/core/knotserver/db"
+	"tangled.org/core/knotserver/pijul"
+	"tangled.org/core/workflow"
+)
+
+// PijulPostPushHook is called by guard once a pijul protocol session has
+// finished, with one line for every channel whose state changed
+func (h *InternalHandle) PijulPostPushHook(w http.ResponseWriter, r *http.Request) {
+	l := h.l.With("handler", "PijulPostPushHook")
+
+	pijulAbsoluteDir := r.Header.Get("X-Pijul-Dir")
+	pijulRelativeDir, err := filepath.Rel(h.c.Repo.ScanPath, pijulAbsoluteDir)
+	if err != nil {
+		l.Error("failed to calculate relative pijul dir", "scanPath", h.c.Repo.ScanPath, "pijulAbsoluteDir", pijulAbsoluteDir)
+		return
+	}
+
+	parts := strings.SplitN(pijulRelativeDir, "/", 2)
+	if len(parts) != 2 {
+		l.Error("invalid pijul dir", "pijulRelativeDir", pijulRelativeDir)
+		return
+	}
+	repoDid := parts[0]
+	repoName := parts[1]
+
+	pijulUserDid := r.Header.Get("X-Pijul-User-Did")
+
+	lines, err := pijul.ParsePostPush(r.Body)
+	if err != nil {
+		l.Error("failed to parse post-push payload", "err", err)
+		// non-fatal
+	}
+
+	resp := hook.HookResponse{
+		Messages: h.processPijulPush(lines, repoDid, repoName, pijulUserDid),
+	}
+
+	writeJSON(w, resp)
+}
+
+// processPijulPush records the channels updated by a pijul push, and runs
+// the conflict checks and pipelines for them. It returns the messages to
+// relay to the pusher.
+func (h *InternalHandle) processPijulPush(lines []pijul.PostPushLine, repoDid, repoName, pijulUserDid string) []string {
+	l := h.l.With("handler", "processPijulPush")
+	didSlashRepo := filepath.Join(repoDid, repoName)
+	messages := make([]string, 0)
+
+	for _, line := range lines {
+		err := h.insertPijulRefUpdate(line, repoDid, repoName)
+		if err != nil {
+			l.Error("failed to insert op", "err", err, "channel", line.Channel, "did", pijulUserDid, "repo", didSlashRepo)
+			// non-fatal
+		}
+
+		err = h.emitPijulConflicts(&messages, line, repoDid, repoName)
+		if err != nil {
+			l.Error("failed to check for conflicts", "err", err, "channel", line.Channel, "did", pijulUserDid, "repo", didSlashRepo)
+			// non-fatal
+		}
+
+		err = h.triggerPijulPipeline(&messages, line, repoDid, repoName)
+		if err != nil {
+			l.Error("failed to trigger pipeline", "err", err, "channel", line.Channel, "did", pijulUserDid, "repo", didSlashRepo)
+			// non-fatal
+		}
+	}
+
+	return messages
+}
+
+func (h *InternalHandle) insertPijulRefUpdate(line pijul.PostPushLine, repoDid, repoName string) error {
+	didSlashRepo, err := securejoin.SecureJoin(repoDid, repoName)
+	if err != nil {
+		return err
+	}
+
+	refUpdate := tangled.PijulRefUpdate{
+		Repo:     didSlashRepo,
+		Channel:  line.Channel,
+		NewState: line.NewState,
+		Changes:  line.Changes,
+	}
+	if line.OldState != "" {
+		refUpdate.OldState = &line.OldState
+	}
+
+	eventJson, err := json.Marshal(refUpdate)
+	if err != nil {
+		return err
+	}
+
+	event := db.Event{
+		Rkey:      TID(),
+		Nsid:      tangled.PijulRefUpdateNSID,
+		EventJson: string(eventJson),
+	}
+
+	return h.db.InsertEvent(event, h.n)
+}
+
+// emitPijulConflicts warns the pusher about files on the channel that were
+// left with unresolved conflicts
+func (h *InternalHandle) emitPijulConflicts(
+	clientMsgs *[]string,
+	line pijul.PostPushLine,
+	repoDid string,
+	repoName string,
+) error {
+	didSlashRepo, err := securejoin.SecureJoin(repoDid, repoName)
+	if err != nil {
+		return err
+	}
+
+	repoPath, err := securejoin.SecureJoin(h.c.Repo.ScanPath, didSlashRepo)
+	if err != nil {
+		return err
+	}
+
+	pr, err := pijul.Open(repoPath, line.Channel)
+	if err != nil {
+		return err
+	}
+
+	conflicts, err := pr.Conflicts()
+	if err != nil {
+		return err
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	ZWS := "\u200B"
+	*clientMsgs = append(*clientMsgs, ZWS)
+	*clientMsgs = append(*clientMsgs, fmt.Sprintf("warning: channel %s has unresolved conflicts in %d file(s)", line.Channel, len(conflicts)))
+	for _, c := range conflicts {
+		*clientMsgs = append(*clientMsgs, fmt.Sprintf("\t%s (%s)", c.Path, c.Reason()))
+	}
+	*clientMsgs = append(*clientMsgs, ZWS)
+
+	return nil
+}
+
+func (h *InternalHandle) triggerPijulPipeline(
+	clientMsgs *[]string,
+	line pijul.PostPushLine,
+	repoDid string,
+	repoName string,
+) error {
+	didSlashRepo, err := securejoin.SecureJoin(repoDid, repoName)
+	if err != nil {
+		return err
+	}
+
+	repoPath, err := securejoin.SecureJoin(h.c.Repo.ScanPath, didSlashRepo)
+	if err != nil {
+		return err
+	}
+
+	pr, err := pijul.Open(repoPath, line.Channel)
+	if err != nil {
+		return err
+	}
+
+	workflowDir, err := pr.FileTree(context.Background(), workflow.WorkflowDir)
+	if err != nil {
+		return err
+	}
+
+	var pipeline workflow.RawPipeline
+	for _, e := range workflowDir {
+		if !e.IsFile() {
+			continue
+		}
+
+		fpath := filepath.Join(workflow.WorkflowDir, e.Name)
+		contents, err := pr.RawContent(fpath)
+		if err != nil {
+			continue
+		}
+
+		pipeline = append(pipeline, workflow.RawWorkflow{
+			Name:     e.Name,
+			Contents: contents,
+		})
+	}
+
+	vcs := "pijul"
+
+	// channels are matched against branch constraints in workflows
+	trigger := tangled.Pipeline_PushTriggerData{
+		Ref:    plumbing.NewBranchReferenceName(line.Channel).String(),
+		OldSha: line.OldState,
+		NewSha: line.NewState,
+	}
+
+	return h.compilePipeline(clientMsgs, pipeline, tangled.Pipeline_TriggerMetadata{
+		Kind: string(workflow.TriggerKindPush),
+		Push: &trigger,
+		Repo: &tangled.Pipeline_TriggerRepo{
+			Did:  repoDid,
+			Knot: h.c.Server.Hostname,
+			Repo: repoName,
+			Vcs:  &vcs,
+		},
+	}, PushOptions{})
+}
@@ -0,0 +1,219 @@
+package pijul
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrApplyFailed is returned when a change could not be applied to a channel
+var ErrApplyFailed = errors.New("failed to apply change")
+
+// ApplyError describes which change of an atomic apply failed, and whether
+// the channel could be restored to where it was before
+type ApplyError struct {
+	Hash        string
+	Err         error
+	RollbackErr error
+}
+
+func (e *ApplyError) Error() string {
+	msg := fmt.Sprintf("%s %s: %v", ErrApplyFailed, e.Hash, e.Err)
+	if e.RollbackErr != nil {
+		msg += fmt.Sprintf(" (rollback failed: %v)", e.RollbackErr)
+	}
+	return msg
+}
+
+func (e *ApplyError) Unwrap() error {
+	return ErrApplyFailed
+}
+
+// ApplyCheck is the outcome of trying changes against a channel without
+// touching it
+type ApplyCheck struct {
+	// Changes is the plan that would be applied, dependencies first
+	Changes []string
+
+	// Conflicts are the conflicts the changes would introduce
+	Conflicts []Conflict
+}
+
+// ApplyPlan returns the changes that need to be applied to bring the given
+// changes onto the current channel: the changes themselves along with any of
+// their dependencies missing from the channel, each after its dependencies.
+func (p *PijulRepo) ApplyPlan(hashes []string) ([]string, error) {
+	onChannel, err := p.ChangeHashes(p.channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	present := map[string]bool{}
+	for _, h := range onChannel {
+		present[h] = true
+	}
+
+	deps := map[string][]string{}
+	var roots []string
+	for _, h := range hashes {
+		if full, ok := resolveHash(h, onChannel); ok {
+			h = full
+		}
+		if present[h] {
+			continue
+		}
+		c, err := p.GetChange(h)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrChangeNotFound, h)
+		}
+		deps[c.Hash] = c.Dependencies
+		roots = append(roots, c.Hash)
+	}
+
+	var missing error
+	plan := applyOrder(roots, present, func(h string) []string {
+		if d, ok := deps[h]; ok {
+			return d
+		}
+		c, err := p.GetChange(h)
+		if err != nil {
+			missing = fmt.Errorf("loading dependency %s: %w", h, err)
+			return nil
+		}
+		deps[h] = c.Dependencies
+		return c.Dependencies
+	})
+	if missing != nil {
+		return nil, missing
+	}
+
+	return plan, nil
+}
+
+// ApplyChanges applies the given changes and their missing dependencies to
+// the current channel, all or nothing. If any of them fails to apply, the
+// ones applied so far are unrecorded again and an *ApplyError is returned.
+// The hashes applied are returned in the order they were applied.
+func (p *PijulRepo) ApplyChanges(hashes []string) ([]string, error) {
+	plan, err := p.ApplyPlan(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	for _, h := range plan {
+		if err := p.Apply(h); err != nil {
+			return nil, &ApplyError{
+				Hash:        h,
+				Err:         err,
+				RollbackErr: p.rollback(applied),
+			}
+		}
+		applied = append(applied, h)
+	}
+
+	return applied, nil
+}
+
+// rollback unrecords the given changes, most recent first
+func (p *PijulRepo) rollback(applied []string) error {
+	var errs []error
+	for _, h := range slices.Backward(applied) {
+		if err := p.Unrecord(h); err != nil {
+			errs = append(errs, fmt.Errorf("pijul unrecord %s: %w", h, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckApply applies the given changes to a throwaway copy of the repo and
+// reports the conflicts they leave behind. The repo itself is never
+// modified. Results are cached per state of the current channel and set of
+// changes applied, so checking the same changes again is cheap.
+func (p *PijulRepo) CheckApply(hashes []string) (*ApplyCheck, error) {
+	plan, err := p.ApplyPlan(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &ApplyCheck{
+		Changes:   plan,
+		Conflicts: []Conflict{},
+	}
+	if len(plan) == 0 {
+		return check, nil
+	}
+
+	state, err := p.ChannelState(p.channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	key := p.path + "\x00" + state + "\x00" + strings.Join(plan, ",")
+	if cached, ok := applyCheckCache.get(key); ok {
+		return cached, nil
+	}
+
+	before, err := p.Conflicts()
+	if err != nil {
+		return nil, err
+	}
+
+	scratch, cleanup, err := p.scratchCopy()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	for _, h := range plan {
+		if err := scratch.Apply(h); err != nil {
+			return nil, &ApplyError{Hash: h, Err: err}
+		}
+	}
+
+	after, err := scratch.Conflicts()
+	if err != nil {
+		return nil, err
+	}
+	check.Conflicts = newConflicts(before, after)
+
+	applyCheckCache.put(key, check)
+	return check, nil
+}
+
+// applyCheckCacheSize bounds the number of apply checks kept in memory
+const applyCheckCacheSize = 256
+
+// applyCheckCache holds recent apply checks. Channel states are content
+// addressed, so a cached check never goes stale; it is only evicted.
+var applyCheckCache = newLRU[*ApplyCheck](applyCheckCacheSize)
+
+// applyOrder lists roots and everything they depend on that is not yet
+// present, each change after its dependencies, without duplicates
+func applyOrder(roots []string, present map[string]bool, deps func(string) []string) []string {
+	seen := map[string]bool{}
+	var order []string
+
+	for _, root := range roots {
+		if present[root] || seen[root] {
+			continue
+		}
+		closure := dependencyClosure(root, func(h string) []string {
+			if present[h] {
+				// everything a present change needs is present as well
+				return nil
+			}
+			return deps(h)
+		})
+		for _, h := range append(closure, root) {
+			if present[h] || seen[h] {
+				continue
+			}
+			seen[h] = true
+			order = append(order, h)
+		}
+	}
+
+	return order
+}
@@ -0,0 +1,25 @@
+package pijul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyOrder(t *testing.T) {
+	// A is on the channel, B and C are not
+	// A <- B <- C
+	// A <- D
+	deps := map[string][]string{
+		"B": {"A"},
+		"C": {"B"},
+		"D": {"A"},
+	}
+	lookup := func(h string) []string { return deps[h] }
+	present := map[string]bool{"A": true}
+
+	assert.Equal(t, []string{"B", "C"}, applyOrder([]string{"C"}, present, lookup))
+	assert.Equal(t, []string{"B", "C", "D"}, applyOrder([]string{"C", "B", "D"}, present, lookup))
+	assert.Equal(t, []string{"D"}, applyOrder([]string{"A", "D"}, present, lookup))
+	assert.Empty(t, applyOrder([]string{"A"}, present, lookup))
+}
@@ -4,10 +4,13 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
+
+	"tangled.org/core/knotserver/pijul/native"
 )
 
 // Change represents a Pijul change (analogous to a Git commit)
@@ -21,6 +24,9 @@ type Change struct {
 	// Message is the change description
 	Message string `json:"message"`
 
+	// Description is the longer text recorded after the message, if any
+	Description string `json:"description,omitempty"`
+
 	// Timestamp when the change was recorded
 	Timestamp time.Time `json:"timestamp"`
 
@@ -40,6 +46,10 @@ type Author struct {
 // Changes returns a list of changes in the repository
 // offset and limit control pagination
 func (p *PijulRepo) Changes(offset, limit int) ([]Change, error) {
+	if changes, err := p.nativeChanges(offset, limit); err == nil {
+		return changes, nil
+	}
+
 	args := []string{"--offset", strconv.Itoa(offset), "--limit", strconv.Itoa(limit)}
 
 	if p.channelName != "" {
@@ -94,13 +104,109 @@ func isNoChangesError(err error) bool {
 
 // GetChange retrieves details for a specific change by hash
 func (p *PijulRepo) GetChange(hash string) (*Change, error) {
-	// Use pijul change to get change details
-	output, err := p.change(hash)
+	if cached, ok := changeCache.get(hash); ok {
+		change := *cached
+		change.Channel = p.channelName
+		return &change, nil
+	}
+
+	// Read the change file directly when we can
+	change, err := p.nativeChange(hash)
+	if errors.Is(err, native.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrChangeNotFound, hash)
+	}
+	if err != nil {
+		// Otherwise use pijul change to get change details
+		output, err := p.change(hash)
+		if err != nil {
+			return nil, fmt.Errorf("pijul change %s: %w", hash, err)
+		}
+		change, err = parseChangeOutput(hash, output)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the CLI is also given abbreviated hashes, which are not cached as
+	// they could match another change later
+	if _, err := native.ParseHash(change.Hash); err == nil {
+		cached := *change
+		cached.Channel = ""
+		changeCache.put(change.Hash, &cached)
+	}
+
+	return change, nil
+}
+
+// changeCacheSize bounds the number of changes kept in memory
+const changeCacheSize = 4096
+
+// changeCache holds recently read changes by full hash. Changes are content
+// addressed, so the same hash is the same change in every repo, and a cached
+// change never goes stale.
+var changeCache = newLRU[*Change](changeCacheSize)
+
+// nativeChange reads a change from its change file without the pijul CLI
+func (p *PijulRepo) nativeChange(hash string) (*Change, error) {
+	nr, err := native.Open(p.path)
 	if err != nil {
-		return nil, fmt.Errorf("pijul change %s: %w", hash, err)
+		return nil, err
 	}
 
-	return parseChangeOutput(hash, output)
+	nc, err := nr.Change(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &Change{
+		Hash:         nc.Hash,
+		Message:      nc.Message,
+		Description:  nc.Description,
+		Timestamp:    nc.Timestamp,
+		Dependencies: nc.Dependencies,
+		Channel:      p.channelName,
+	}
+	for _, a := range nc.Authors {
+		name := a.Name
+		if name == "" {
+			name = a.Key
+		}
+		change.Authors = append(change.Authors, Author{
+			Name:  name,
+			Email: a.Email,
+		})
+	}
+
+	return change, nil
+}
+
+// nativeChanges lists a page of change hashes with the CLI, as the order of
+// a channel's log lives in the pristine, and reads each change from its
+// change file, which avoids parsing the CLI's human readable log format
+func (p *PijulRepo) nativeChanges(offset, limit int) ([]Change, error) {
+	args := []string{"--hash-only", "--offset", strconv.Itoa(offset), "--limit", strconv.Itoa(limit)}
+	if p.channelName != "" {
+		args = append(args, "--channel", p.channelName)
+	}
+
+	output, err := p.log(args...)
+	if err != nil {
+		if isNoChangesError(err) {
+			return []Change{}, nil
+		}
+		return nil, fmt.Errorf("pijul log: %w", err)
+	}
+
+	changes := []Change{}
+	for _, hash := range strings.Fields(string(output)) {
+		change, err := p.nativeChange(hash)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *change)
+	}
+
+	return changes, nil
 }
 
 // parseLogOutput parses the output of pijul log
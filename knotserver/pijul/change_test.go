@@ -0,0 +1,35 @@
+package pijul
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tangled.org/core/knotserver/pijul/native"
+)
+
+func TestGetChangeNotFound(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".pijul", "changes"), 0755))
+	p := &PijulRepo{path: dir, channelName: "main"}
+
+	var h native.Hash
+	h[0] = 1
+	_, err := p.GetChange(h.String())
+	assert.ErrorIs(t, err, ErrChangeNotFound)
+}
+
+func TestGetChangeCached(t *testing.T) {
+	var h native.Hash
+	h[0] = 2
+	changeCache.put(h.String(), &Change{Hash: h.String(), Message: "cached"})
+
+	// the repo has no change store, so only the cache can answer
+	p := &PijulRepo{path: t.TempDir(), channelName: "feature"}
+	c, err := p.GetChange(h.String())
+	require.NoError(t, err)
+	assert.Equal(t, "cached", c.Message)
+	assert.Equal(t, "feature", c.Channel)
+}
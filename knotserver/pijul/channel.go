@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"unicode"
 )
 
 // Channel represents a Pijul channel (analogous to a Git branch)
@@ -99,10 +100,35 @@ func (p *PijulRepo) DeleteChannel(name string) error {
 	return err
 }
 
-// RenameChannel renames a channel
+// RenameChannel renames a channel, the default channel keeps being the
+// default under its new name
 func (p *PijulRepo) RenameChannel(oldName, newName string) error {
-	_, err := p.channelCmd("rename", oldName, newName)
-	return err
+	defaultChannel, err := p.FindDefaultChannel()
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.channelCmd("rename", oldName, newName); err != nil {
+		return err
+	}
+
+	if defaultChannel == oldName {
+		return p.SetDefaultChannel(newName)
+	}
+	return nil
+}
+
+// ValidateChannelName checks that a channel name can be passed to pijul
+func ValidateChannelName(name string) error {
+	if name == "" || len(name) > 255 || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("%w: %q", ErrInvalidChannel, name)
+	}
+	for _, r := range name {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return fmt.Errorf("%w: %q", ErrInvalidChannel, name)
+		}
+	}
+	return nil
 }
 
 // SwitchChannel switches to a different channel
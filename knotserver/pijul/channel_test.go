@@ -0,0 +1,17 @@
+package pijul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateChannelName(t *testing.T) {
+	for _, name := range []string{"main", "feature/foo", "v1.2", "ünïcode"} {
+		assert.NoError(t, ValidateChannelName(name), name)
+	}
+
+	for _, name := range []string{"", "-rf", "has space", "tab\there", "new\nline"} {
+		assert.ErrorIs(t, ValidateChannelName(name), ErrInvalidChannel, name)
+	}
+}
@@ -11,8 +11,17 @@ const (
 	recordSeparator = "\x1e" // ASCII Record Separator
 )
 
-// runPijulCmd executes a pijul command in the repository directory
+// runPijulCmd executes a pijul command in the repository directory, holding
+// the repo lock for commands that write to it
 func (p *PijulRepo) runPijulCmd(command string, extraArgs ...string) ([]byte, error) {
+	if !p.scratch && writesRepo(command, extraArgs) {
+		unlock, err := LockRepo(p.path)
+		if err != nil {
+			return nil, fmt.Errorf("locking repository: %w", err)
+		}
+		defer unlock()
+	}
+
 	var args []string
 	args = append(args, command)
 	args = append(args, extraArgs...)
@@ -37,6 +46,14 @@ func (p *PijulRepo) runPijulCmd(command string, extraArgs ...string) ([]byte, er
 
 // runPijulCmdWithStdin executes a pijul command with stdin input
 func (p *PijulRepo) runPijulCmdWithStdin(stdin []byte, command string, extraArgs ...string) ([]byte, error) {
+	if !p.scratch && writesRepo(command, extraArgs) {
+		unlock, err := LockRepo(p.path)
+		if err != nil {
+			return nil, fmt.Errorf("locking repository: %w", err)
+		}
+		defer unlock()
+	}
+
 	var args []string
 	args = append(args, command)
 	args = append(args, extraArgs...)
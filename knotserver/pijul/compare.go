@@ -0,0 +1,222 @@
+package pijul
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+	"tangled.org/core/patchutil"
+)
+
+// ChannelComparison compares a head channel with a base channel
+type ChannelComparison struct {
+	// Ahead are the changes on head that base lacks, oldest first
+	Ahead []string
+
+	// Behind are the changes on base that head lacks, oldest first
+	Behind []string
+
+	// Patch is a unified diff of the files of base against the files base
+	// would have once the changes of Ahead are applied to it
+	Patch string
+}
+
+// size estimates the memory held by a comparison, in bytes
+func (c *ChannelComparison) size() int64 {
+	return int64(len(c.Patch)) + int64(len(c.Ahead)+len(c.Behind))*64
+}
+
+// CompareChannels compares two channels of the repo. Pijul has no diff
+// between channels, so the changes head would bring are applied to base in a
+// throwaway copy of the repo, and the files of both are diffed. The repo
+// itself is never modified.
+//
+// Comparisons are cached by the states of both channels. Those that are not
+// are rate limited across the knot, waiting for their turn until ctx is
+// done, as each one copies the repo.
+func (p *PijulRepo) CompareChannels(ctx context.Context, base, head string) (*ChannelComparison, error) {
+	for _, ch := range []string{base, head} {
+		exists, err := p.ChannelExists(ch)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ErrChannelNotFound, ch)
+		}
+	}
+
+	baseState, err := p.ChannelState(base)
+	if err != nil {
+		return nil, err
+	}
+	headState, err := p.ChannelState(head)
+	if err != nil {
+		return nil, err
+	}
+
+	key := p.path + "\x00" + baseState + "\x00" + headState
+	if cached, ok := comparisonCache.get(key); ok {
+		return cached, nil
+	}
+
+	if err := compareLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	baseChanges, err := p.ChangeHashes(base)
+	if err != nil {
+		return nil, err
+	}
+	headChanges, err := p.ChangeHashes(head)
+	if err != nil {
+		return nil, err
+	}
+
+	status := CompareFork(headChanges, baseChanges)
+	comparison := &ChannelComparison{
+		Ahead:  status.Ahead,
+		Behind: status.Behind,
+	}
+	if len(comparison.Ahead) == 0 {
+		comparisonCache.put(key, comparison)
+		return comparison, nil
+	}
+
+	baseRepo := &PijulRepo{path: p.path, channelName: base}
+	baseTree, err := baseRepo.stateTree()
+	if err != nil {
+		return nil, err
+	}
+
+	scratch, cleanup, err := baseRepo.scratchCopy()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	for _, h := range comparison.Ahead {
+		if err := scratch.Apply(h); err != nil {
+			return nil, &ApplyError{Hash: h, Err: err}
+		}
+	}
+
+	mergedTree, err := scratch.stateTree()
+	if err != nil {
+		return nil, err
+	}
+
+	comparison.Patch, err = treePatch(baseTree, mergedTree)
+	if err != nil {
+		return nil, err
+	}
+
+	comparisonCache.put(key, comparison)
+	return comparison, nil
+}
+
+// comparisonCacheSize and comparisonCacheBudget bound the comparisons kept
+// in memory, by count and by the bytes of their patches
+const (
+	comparisonCacheSize   = 256
+	comparisonCacheBudget = 64 << 20
+)
+
+// comparisonCache holds recent comparisons. Channel states are content
+// addressed, so a cached comparison never goes stale; it is only evicted.
+var comparisonCache = newCostLRU(comparisonCacheSize, comparisonCacheBudget, (*ChannelComparison).size)
+
+// compareLimiter lets through a burst of comparisons, then one a second
+var compareLimiter = rate.NewLimiter(rate.Every(time.Second), 5)
+
+// treePatch renders the differences between the files of two trees as a
+// git style patch, files in lexical order
+func treePatch(old, new *stateTree) (string, error) {
+	seen := map[string]bool{}
+	var paths []string
+	for _, t := range []*stateTree{old, new} {
+		for p, f := range t.files {
+			if !f.isDir && !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+	slices.Sort(paths)
+
+	var b strings.Builder
+	for _, p := range paths {
+		diff, err := filePatch(p, regularFile(old, p), regularFile(new, p))
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(diff)
+	}
+
+	return b.String(), nil
+}
+
+// filePatch renders the differences of one file, either side being nil if
+// the file does not exist there
+func filePatch(p string, oldFile, newFile *stateFile) (string, error) {
+	oldName, newName := "a/"+p, "b/"+p
+	header := fmt.Sprintf("diff --git %s %s\n", oldName, newName)
+
+	var oldData, newData []byte
+	switch {
+	case oldFile == nil:
+		header += fmt.Sprintf("new file mode %s\n", gitMode(newFile.mode))
+		oldName = "/dev/null"
+		newData = newFile.data
+	case newFile == nil:
+		header += fmt.Sprintf("deleted file mode %s\n", gitMode(oldFile.mode))
+		newName = "/dev/null"
+		oldData = oldFile.data
+	default:
+		oldData, newData = oldFile.data, newFile.data
+		if gitMode(oldFile.mode) != gitMode(newFile.mode) {
+			header += fmt.Sprintf("old mode %s\nnew mode %s\n", gitMode(oldFile.mode), gitMode(newFile.mode))
+		} else if bytes.Equal(oldData, newData) {
+			return "", nil
+		}
+	}
+
+	if bytes.Equal(oldData, newData) {
+		return header, nil
+	}
+
+	if isBinary(oldData) || isBinary(newData) {
+		return header + fmt.Sprintf("Binary files %s and %s differ\n", oldName, newName), nil
+	}
+
+	diff, err := patchutil.Unified(string(oldData), oldName, string(newData), newName)
+	if err != nil {
+		return "", fmt.Errorf("diffing %s: %w", p, err)
+	}
+
+	return header + diff, nil
+}
+
+// regularFile looks up a file that is not a directory
+func regularFile(t *stateTree, p string) *stateFile {
+	f, ok := t.files[p]
+	if !ok || f.isDir {
+		return nil
+	}
+	return f
+}
+
+// gitMode formats the permissions of a file the way git patches do
+func gitMode(mode fs.FileMode) string {
+	if mode&fs.ModeSymlink != 0 {
+		return "120000"
+	}
+	if mode&0111 != 0 {
+		return "100755"
+	}
+	return "100644"
+}
@@ -0,0 +1,75 @@
+package pijul
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTree(files map[string]string) *stateTree {
+	tree := &stateTree{
+		files:    map[string]*stateFile{},
+		children: map[string][]string{"": {}},
+	}
+	for name, data := range files {
+		tree.addDir(parentDir(name))
+		tree.files[name] = &stateFile{name: name, mode: 0644, data: []byte(data)}
+		tree.addChild(name)
+	}
+	return tree
+}
+
+func parentDir(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+func TestTreePatch(t *testing.T) {
+	old := testTree(map[string]string{
+		"README":      "hello\n",
+		"src/main.rs": "fn main() {}\n",
+		"gone.txt":    "bye\n",
+	})
+	new := testTree(map[string]string{
+		"README":      "hello\n",
+		"src/main.rs": "fn main() {\n    println!(\"hi\");\n}\n",
+		"src/lib.rs":  "pub fn f() {}\n",
+	})
+
+	patch, err := treePatch(old, new)
+	require.NoError(t, err)
+
+	assert.Equal(t, `diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/src/lib.rs b/src/lib.rs
+new file mode 100644
+--- /dev/null
++++ b/src/lib.rs
+@@ -0,0 +1 @@
++pub fn f() {}
+diff --git a/src/main.rs b/src/main.rs
+--- a/src/main.rs
++++ b/src/main.rs
+@@ -1 +1,3 @@
+-fn main() {}
++fn main() {
++    println!("hi");
++}
+`, patch)
+}
+
+func TestTreePatchUnchanged(t *testing.T) {
+	tree := testTree(map[string]string{"a.txt": "a\n"})
+
+	patch, err := treePatch(tree, testTree(map[string]string{"a.txt": "a\n"}))
+	require.NoError(t, err)
+	assert.Empty(t, patch)
+}
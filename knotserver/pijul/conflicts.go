@@ -0,0 +1,170 @@
+package pijul
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Markers pijul writes around the sides of a conflict when outputting a
+// file. Each marker is followed by the number of the conflict, and may be
+// followed by the abbreviated hashes of the changes involved, e.g.
+// `>>>>>>> 1 [5BKRN2ZF fix typo]`. The separator is a longer run of '='.
+// Lines of the file that only look like markers, such as a git conflict
+// committed by mistake or a markdown heading underline, lack the number.
+var conflictMarker = regexp.MustCompile(`^(>{7}|={7,}|<{7}) (\d+)(?: |$)`)
+
+// Conflict is a file on a channel whose contents hold unresolved conflicts.
+// Conflicts are found from the markers pijul writes in file contents, so
+// only conflicts between edits of the same lines are found: name conflicts,
+// where a file was given two names or two files the same name, and zombie
+// conflicts, where deleted lines are still needed as context, are not.
+type Conflict struct {
+	// Path is the path of the conflicted file
+	Path string
+
+	// Regions is the number of conflicting regions in the file
+	Regions int
+
+	// Changes are the hashes of the competing changes. They are printed
+	// abbreviated in the conflict markers, and expanded when the change is
+	// on the channel.
+	Changes []string
+}
+
+// Reason describes the conflict for display
+func (c Conflict) Reason() string {
+	if c.Regions == 1 {
+		return "1 conflicting region"
+	}
+	return fmt.Sprintf("%d conflicting regions", c.Regions)
+}
+
+// Conflicts lists the files on the current channel, at its pinned or latest
+// state, whose contents hold conflict markers
+func (p *PijulRepo) Conflicts() ([]Conflict, error) {
+	tree, err := p.stateTree()
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := findConflicts(tree)
+	if len(conflicts) == 0 {
+		return conflicts, nil
+	}
+
+	hashes, err := p.ChangeHashes(p.channelName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range conflicts {
+		for j, h := range conflicts[i].Changes {
+			if full, ok := resolveHash(h, hashes); ok {
+				conflicts[i].Changes[j] = full
+			}
+		}
+	}
+
+	return conflicts, nil
+}
+
+// findConflicts scans every text file of the tree for conflict markers
+func findConflicts(tree *stateTree) []Conflict {
+	conflicts := []Conflict{}
+	tree.walk("", func(p string, f *stateFile) error {
+		if f.isDir || isBinary(f.data) {
+			return nil
+		}
+		if regions, changes := parseConflictMarkers(f.data); regions > 0 {
+			conflicts = append(conflicts, Conflict{
+				Path:    p,
+				Regions: regions,
+				Changes: changes,
+			})
+		}
+		return nil
+	})
+	return conflicts
+}
+
+// parseConflictMarkers counts the conflicting regions in a file and collects
+// the changes named by their markers. Conflicts may nest, a region is only
+// counted at the outermost one.
+func parseConflictMarkers(data []byte) (int, []string) {
+	var regions int
+	var open []string
+	var changes []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		m := conflictMarker.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		marker, id := m[1], m[2]
+		switch {
+		case strings.HasPrefix(marker, ">"):
+			if len(open) == 0 {
+				regions++
+			}
+			open = append(open, id)
+		case len(open) == 0 || !slices.Contains(open, id):
+			// a separator or end of a conflict that was never opened
+			continue
+		case strings.HasPrefix(marker, "<"):
+			open = open[:slices.Index(open, id)]
+		}
+
+		for _, h := range markerChanges(line) {
+			if !slices.Contains(changes, h) {
+				changes = append(changes, h)
+			}
+		}
+	}
+
+	return regions, changes
+}
+
+// markerChanges extracts the hashes from the bracketed groups of a marker
+// line
+func markerChanges(line string) []string {
+	var hashes []string
+	for {
+		start := strings.IndexByte(line, '[')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(line[start:], ']')
+		if end < 0 {
+			break
+		}
+		if fields := strings.Fields(line[start+1 : start+end]); len(fields) > 0 {
+			hashes = append(hashes, fields[0])
+		}
+		line = line[start+end+1:]
+	}
+	return hashes
+}
+
+// newConflicts returns the conflicts in after that were not already in
+// before, either because the file was clean or because it gained regions
+func newConflicts(before, after []Conflict) []Conflict {
+	existing := map[string]int{}
+	for _, c := range before {
+		existing[c.Path] = c.Regions
+	}
+
+	out := []Conflict{}
+	for _, c := range after {
+		if c.Regions > existing[c.Path] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package pijul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConflictMarkers(t *testing.T) {
+	data := []byte(`fn main() {
+>>>>>>> 1 [5BKRN2ZF fix typo]
+    println!("hello");
+================================ 1 [XH2QNPLA greet the world]
+    println!("hello, world");
+<<<<<<< 1
+}
+
+Heading
+=======
+`)
+
+	regions, changes := parseConflictMarkers(data)
+	assert.Equal(t, 1, regions)
+	assert.Equal(t, []string{"5BKRN2ZF", "XH2QNPLA"}, changes)
+
+	regions, changes = parseConflictMarkers([]byte("Heading\n=======\n"))
+	assert.Zero(t, regions)
+	assert.Empty(t, changes)
+}
+
+func TestParseConflictMarkersStrict(t *testing.T) {
+	// a git conflict committed as is has no conflict numbers
+	regions, _ := parseConflictMarkers([]byte(`<<<<<<< HEAD
+ours
+=======
+theirs
+>>>>>>> feature
+>>>>>>>
+`))
+	assert.Zero(t, regions)
+
+	// nested conflicts count as one region
+	regions, changes := parseConflictMarkers([]byte(`>>>>>>> 1 [AAAAAAAA]
+>>>>>>> 2 [BBBBBBBB]
+a
+================================ 2 [CCCCCCCC]
+b
+<<<<<<< 2
+================================ 1 [DDDDDDDD]
+c
+<<<<<<< 1
+>>>>>>> 3
+d
+<<<<<<< 3
+`))
+	assert.Equal(t, 2, regions)
+	assert.Equal(t, []string{"AAAAAAAA", "BBBBBBBB", "CCCCCCCC", "DDDDDDDD"}, changes)
+}
+
+func TestNewConflicts(t *testing.T) {
+	before := []Conflict{
+		{Path: "a.txt", Regions: 1},
+		{Path: "b.txt", Regions: 2},
+	}
+	after := []Conflict{
+		{Path: "a.txt", Regions: 2},
+		{Path: "b.txt", Regions: 2},
+		{Path: "c.txt", Regions: 1},
+	}
+
+	got := newConflicts(before, after)
+	assert.Equal(t, []Conflict{
+		{Path: "a.txt", Regions: 2},
+		{Path: "c.txt", Regions: 1},
+	}, got)
+}
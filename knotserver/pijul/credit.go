@@ -0,0 +1,77 @@
+package pijul
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// CreditLine is a line of a file along with the change that introduced it
+type CreditLine struct {
+	// Line is the 1-based line number
+	Line int
+
+	// Content is the text of the line, without the line ending
+	Content string
+
+	// Change is the hash of the change that introduced the line
+	Change string
+}
+
+// Credit attributes every line of a file on the current channel to the
+// change that introduced it
+func (p *PijulRepo) Credit(filePath string) ([]CreditLine, error) {
+	args := []string{}
+	if p.channelName != "" {
+		args = append(args, "--channel", p.channelName)
+	}
+	args = append(args, filePath)
+
+	output, err := p.runPijulCmd("credit", args...)
+	if err != nil {
+		return nil, fmt.Errorf("pijul credit: %w", err)
+	}
+
+	return parseCreditOutput(output)
+}
+
+// parseCreditOutput parses the output of `pijul credit`, which prints the
+// hashes of the changes introducing a run of lines, followed by the lines
+// themselves quoted with "> ":
+//
+//	MDS3XBQ3E5ZBQ..., QJ4KZBHGV6PGN...
+//
+//	> first line
+//	> second line
+//
+// When a run is credited to several changes, the first one is kept.
+func parseCreditOutput(output []byte) ([]CreditLine, error) {
+	var lines []CreditLine
+	var current string
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), len(output)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == ">" || strings.HasPrefix(line, "> "):
+			content := strings.TrimPrefix(strings.TrimPrefix(line, ">"), " ")
+			lines = append(lines, CreditLine{
+				Line:    len(lines) + 1,
+				Content: strings.TrimSuffix(content, "\r"),
+				Change:  current,
+			})
+		case strings.TrimSpace(line) == "":
+			continue
+		default:
+			hash, _, _ := strings.Cut(line, ",")
+			current = strings.TrimSpace(hash)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
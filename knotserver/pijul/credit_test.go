@@ -0,0 +1,35 @@
+package pijul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCreditOutput(t *testing.T) {
+	output := []byte(`MDS3XBQ3E5ZBQMVRJ2ZBDCMVMJYZXYKHR4XMRXQTLNWB3K3OHRAC
+
+> package main
+>
+> func main() {
+QJ4KZBHGV6PGN2PHNVXAYXT2EDTXPOBMNJU5A3DO7X7KPRYFLB4AC, MDS3XBQ3E5ZBQMVRJ2ZBDCMVMJYZXYKHR4XMRXQTLNWB3K3OHRAC
+
+> 	println("hello")
+MDS3XBQ3E5ZBQMVRJ2ZBDCMVMJYZXYKHR4XMRXQTLNWB3K3OHRAC
+
+> }
+`)
+
+	lines, err := parseCreditOutput(output)
+	require.NoError(t, err)
+	require.Len(t, lines, 5)
+
+	first := "MDS3XBQ3E5ZBQMVRJ2ZBDCMVMJYZXYKHR4XMRXQTLNWB3K3OHRAC"
+	second := "QJ4KZBHGV6PGN2PHNVXAYXT2EDTXPOBMNJU5A3DO7X7KPRYFLB4AC"
+
+	assert.Equal(t, CreditLine{Line: 1, Content: "package main", Change: first}, lines[0])
+	assert.Equal(t, CreditLine{Line: 2, Content: "", Change: first}, lines[1])
+	assert.Equal(t, CreditLine{Line: 4, Content: "\tprintln(\"hello\")", Change: second}, lines[3])
+	assert.Equal(t, CreditLine{Line: 5, Content: "}", Change: first}, lines[4])
+}
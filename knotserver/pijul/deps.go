@@ -0,0 +1,147 @@
+package pijul
+
+import (
+	"fmt"
+	"slices"
+
+	"tangled.org/core/knotserver/pijul/native"
+)
+
+// DependencyGraph describes where a change sits in the dependency DAG
+type DependencyGraph struct {
+	// Change is the change the graph was built for
+	Change *Change `json:"change"`
+
+	// Dependencies is the transitive closure of the changes this change
+	// depends on, dependencies first
+	Dependencies []Change `json:"dependencies"`
+
+	// Dependents are the changes on the channel that depend on this change,
+	// directly or transitively, dependencies first
+	Dependents []Change `json:"dependents"`
+}
+
+// DependencyGraph returns the dependencies and dependents of a change. The
+// dependencies are followed through the change store, while dependents are
+// only looked up on the current channel. Changes are read through the change
+// cache, so only changes new to the knot are read from disk. A change that
+// does not exist is reported as ErrChangeNotFound.
+func (p *PijulRepo) DependencyGraph(hash string) (*DependencyGraph, error) {
+	onChannel, err := p.ChangeHashes(p.channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	// abbreviated hashes are looked up on the channel, anything else must
+	// name a change of the change store in full
+	if full, ok := resolveHash(hash, onChannel); ok {
+		hash = full
+	} else if _, err := native.ParseHash(hash); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrChangeNotFound, hash)
+	}
+
+	root, err := p.GetChange(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]*Change{root.Hash: root}
+	load := func(h string) (*Change, error) {
+		if c, ok := changes[h]; ok {
+			return c, nil
+		}
+		c, err := p.GetChange(h)
+		if err != nil {
+			return nil, fmt.Errorf("loading change %s: %w", h, err)
+		}
+		changes[h] = c
+		return c, nil
+	}
+
+	deps := map[string][]string{}
+	for _, h := range onChannel {
+		c, err := load(h)
+		if err != nil {
+			return nil, err
+		}
+		deps[h] = c.Dependencies
+	}
+
+	// dependencies may live outside the channel, fetch them as we go
+	var missing error
+	closure := dependencyClosure(root.Hash, func(h string) []string {
+		if d, ok := deps[h]; ok {
+			return d
+		}
+		c, err := load(h)
+		if err != nil {
+			missing = err
+			return nil
+		}
+		deps[h] = c.Dependencies
+		return c.Dependencies
+	})
+	if missing != nil {
+		return nil, missing
+	}
+
+	graph := &DependencyGraph{
+		Change:       root,
+		Dependencies: []Change{},
+		Dependents:   []Change{},
+	}
+	for _, h := range closure {
+		graph.Dependencies = append(graph.Dependencies, *changes[h])
+	}
+	for _, h := range dependents([]string{root.Hash}, onChannel, deps) {
+		graph.Dependents = append(graph.Dependents, *changes[h])
+	}
+
+	return graph, nil
+}
+
+// dependencyClosure returns every change reachable from root through deps,
+// excluding root itself. Changes come after all of their dependencies.
+func dependencyClosure(root string, deps func(string) []string) []string {
+	var order []string
+	visited := map[string]bool{root: true}
+
+	var visit func(h string)
+	visit = func(h string) {
+		for _, d := range deps(h) {
+			if visited[d] {
+				continue
+			}
+			visited[d] = true
+			visit(d)
+			order = append(order, d)
+		}
+	}
+	visit(root)
+
+	return order
+}
+
+// dependents returns the changes among hashes that depend on any of roots,
+// directly or transitively. hashes are expected oldest first, which is also
+// the order of the result since a change is always applied after its
+// dependencies.
+func dependents(roots []string, hashes []string, deps map[string][]string) []string {
+	reached := map[string]bool{}
+	for _, r := range roots {
+		reached[r] = true
+	}
+
+	var out []string
+	for _, h := range hashes {
+		if reached[h] {
+			continue
+		}
+		if slices.ContainsFunc(deps[h], func(d string) bool { return reached[d] }) {
+			reached[h] = true
+			out = append(out, h)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,37 @@
+package pijul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyClosure(t *testing.T) {
+	// A <- B <- D
+	// A <- C <- D
+	deps := map[string][]string{
+		"B": {"A"},
+		"C": {"A"},
+		"D": {"B", "C"},
+	}
+	lookup := func(h string) []string { return deps[h] }
+
+	assert.Equal(t, []string{"A", "B", "C"}, dependencyClosure("D", lookup))
+	assert.Equal(t, []string{"A"}, dependencyClosure("B", lookup))
+	assert.Empty(t, dependencyClosure("A", lookup))
+}
+
+func TestDependents(t *testing.T) {
+	hashes := []string{"A", "B", "C", "D", "E"}
+	deps := map[string][]string{
+		"B": {"A"},
+		"C": {"A"},
+		"D": {"B"},
+		"E": {},
+	}
+
+	assert.Equal(t, []string{"B", "C", "D"}, dependents([]string{"A"}, hashes, deps))
+	assert.Equal(t, []string{"D"}, dependents([]string{"B"}, hashes, deps))
+	assert.Empty(t, dependents([]string{"E"}, hashes, deps))
+	assert.Equal(t, []string{"D"}, dependents([]string{"B", "C"}, hashes, deps))
+}
@@ -6,9 +6,9 @@ import (
 
 // Diff represents the difference between two states
 type Diff struct {
-	Raw     string      `json:"raw"`
-	Files   []FileDiff  `json:"files,omitempty"`
-	Stats   *DiffStats  `json:"stats,omitempty"`
+	Raw   string     `json:"raw"`
+	Files []FileDiff `json:"files,omitempty"`
+	Stats *DiffStats `json:"stats,omitempty"`
 }
 
 // FileDiff represents changes to a single file
@@ -19,6 +19,7 @@ type FileDiff struct {
 	Additions int    `json:"additions"`
 	Deletions int    `json:"deletions"`
 	Patch     string `json:"patch,omitempty"`
+	Hunks     []Hunk `json:"hunks,omitempty"`
 }
 
 // DiffStats contains summary statistics for a diff
@@ -35,9 +36,7 @@ func (p *PijulRepo) Diff() (*Diff, error) {
 		return nil, fmt.Errorf("pijul diff: %w", err)
 	}
 
-	return &Diff{
-		Raw: string(output),
-	}, nil
+	return parseDiff(string(output)), nil
 }
 
 // DiffChange returns the diff for a specific change
@@ -47,27 +46,15 @@ func (p *PijulRepo) DiffChange(hash string) (*Diff, error) {
 		return nil, fmt.Errorf("pijul change %s: %w", hash, err)
 	}
 
-	return &Diff{
-		Raw: string(output),
-	}, nil
+	return parseDiff(string(output)), nil
 }
 
-// DiffBetween returns the diff between two channels or states
-func (p *PijulRepo) DiffBetween(from, to string) (*Diff, error) {
-	args := []string{}
-	if from != "" {
-		args = append(args, "--channel", from)
-	}
-	if to != "" {
-		args = append(args, "--channel", to)
-	}
-
-	output, err := p.diff(args...)
-	if err != nil {
-		return nil, fmt.Errorf("pijul diff: %w", err)
-	}
-
+// parseDiff builds a Diff from the text representation of a change
+func parseDiff(raw string) *Diff {
+	files := parseHunks(raw)
 	return &Diff{
-		Raw: string(output),
-	}, nil
+		Raw:   raw,
+		Files: files,
+		Stats: diffStats(files),
+	}
 }
@@ -0,0 +1,162 @@
+package pijul
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// remoteTimeout bounds requests to remote repos, which may be slow or
+	// unreachable
+	remoteTimeout = 10 * time.Second
+
+	// remoteChangelistTTL is how long the changelist of a remote channel is
+	// reused before it is fetched again
+	remoteChangelistTTL = time.Minute
+
+	remoteChangelistCacheSize = 64
+)
+
+var remoteClient = &http.Client{Timeout: remoteTimeout}
+
+type remoteChangelist struct {
+	hashes  []string
+	fetched time.Time
+}
+
+var remoteChangelistCache = newLRU[remoteChangelist](remoteChangelistCacheSize)
+
+// ForkStatus compares a channel of a fork with a channel of its upstream
+type ForkStatus struct {
+	// Ahead are the changes on the fork that upstream lacks, oldest first
+	Ahead []string
+
+	// Behind are the changes on upstream that the fork lacks, oldest first
+	Behind []string
+}
+
+// RemoteChangeHashes lists the changes on a channel of a remote repo, oldest
+// first, using the pijul HTTP remote protocol. Lists are cached for a short
+// while, so that showing a fork does not fetch from its upstream every time.
+func RemoteChangeHashes(ctx context.Context, remote, channel string) ([]string, error) {
+	key := remote + "\x00" + channel
+	if cached, ok := remoteChangelistCache.get(key); ok && time.Since(cached.fetched) < remoteChangelistTTL {
+		return slices.Clone(cached.hashes), nil
+	}
+
+	hashes, err := fetchChangelist(ctx, remote, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteChangelistCache.put(key, remoteChangelist{hashes: hashes, fetched: time.Now()})
+	return slices.Clone(hashes), nil
+}
+
+func fetchChangelist(ctx context.Context, remote, channel string) ([]string, error) {
+	query := url.Values{}
+	query.Set("channel", channel)
+	query.Set("changelist", "0")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(remote, "/")+"/.pijul?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := remoteClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching changelist: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching changelist: %s", resp.Status)
+	}
+
+	return parseChangelist(resp.Body)
+}
+
+// parseChangelist extracts the hashes from a changelist response, where
+// every line is <position>.<hash>.<state>, with a trailing dot for tagged
+// states. The list ends at the first empty line.
+func parseChangelist(r io.Reader) ([]string, error) {
+	var hashes []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			break
+		}
+
+		parts := strings.Split(line, ".")
+		if len(parts) < 3 || parts[1] == "" {
+			return nil, fmt.Errorf("invalid changelist line: %q", line)
+		}
+		hashes = append(hashes, parts[1])
+	}
+
+	return hashes, scanner.Err()
+}
+
+// CompareFork compares the changes of a fork channel with the changes of an
+// upstream channel
+func CompareFork(fork, upstream []string) ForkStatus {
+	status := ForkStatus{
+		Ahead:  []string{},
+		Behind: []string{},
+	}
+
+	inFork := make(map[string]struct{}, len(fork))
+	for _, h := range fork {
+		inFork[h] = struct{}{}
+	}
+
+	inUpstream := make(map[string]struct{}, len(upstream))
+	for _, h := range upstream {
+		inUpstream[h] = struct{}{}
+		if _, ok := inFork[h]; !ok {
+			status.Behind = append(status.Behind, h)
+		}
+	}
+
+	for _, h := range fork {
+		if _, ok := inUpstream[h]; !ok {
+			status.Ahead = append(status.Ahead, h)
+		}
+	}
+
+	return status
+}
+
+// PullAll pulls every change of a remote channel that the current channel
+// lacks, and returns the hashes that were pulled, oldest first
+func (p *PijulRepo) PullAll(remote, fromChannel string) ([]string, error) {
+	before, err := p.ChangeHashes(p.channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{remote, "--all", "--from-channel", fromChannel}
+	if p.channelName != "" {
+		args = append(args, "--channel", p.channelName)
+	}
+
+	if _, err := p.runPijulCmd("pull", args...); err != nil {
+		return nil, err
+	}
+
+	after, err := p.ChangeHashes(p.channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	return CompareFork(after, before).Ahead, nil
+}
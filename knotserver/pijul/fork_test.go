@@ -0,0 +1,56 @@
+package pijul
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChangelist(t *testing.T) {
+	body := "0.AAAA.S1\n1.BBBB.S2.\n2.CCCC.S3\n\n3.DDDD.S4\n"
+
+	hashes, err := parseChangelist(strings.NewReader(body))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AAAA", "BBBB", "CCCC"}, hashes)
+
+	_, err = parseChangelist(strings.NewReader("garbage\n"))
+	assert.Error(t, err)
+}
+
+func TestRemoteChangeHashesCached(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/.pijul", r.URL.Path)
+		assert.Equal(t, "main", r.URL.Query().Get("channel"))
+		fmt.Fprint(w, "0.AAAA.S1\n1.BBBB.S2\n\n")
+	}))
+	defer srv.Close()
+
+	for range 2 {
+		hashes, err := RemoteChangeHashes(context.Background(), srv.URL, "main")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"AAAA", "BBBB"}, hashes)
+	}
+	assert.Equal(t, 1, requests, "the changelist should be fetched once")
+}
+
+func TestCompareFork(t *testing.T) {
+	status := CompareFork(
+		[]string{"A", "B", "F1", "F2"},
+		[]string{"A", "U1", "B", "U2"},
+	)
+
+	assert.Equal(t, []string{"F1", "F2"}, status.Ahead)
+	assert.Equal(t, []string{"U1", "U2"}, status.Behind)
+
+	status = CompareFork([]string{"A"}, []string{"A"})
+	assert.Empty(t, status.Ahead)
+	assert.Empty(t, status.Behind)
+}
@@ -0,0 +1,259 @@
+package pijul
+
+import (
+	"bufio"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Hunk kinds, as printed in the text representation of a change
+const (
+	HunkFileAddition         = "file_addition"
+	HunkFileDeletion         = "file_deletion"
+	HunkFileUndeletion       = "file_undeletion"
+	HunkMove                 = "move"
+	HunkEdit                 = "edit"
+	HunkReplacement          = "replacement"
+	HunkSolveNameConflict    = "solve_name_conflict"
+	HunkUnsolveNameConflict  = "unsolve_name_conflict"
+	HunkSolveOrderConflict   = "solve_order_conflict"
+	HunkUnsolveOrderConflict = "unsolve_order_conflict"
+	HunkResurrectZombies     = "resurrect_zombies"
+)
+
+// File statuses reported in FileDiff.Status
+const (
+	FileAdded    = "added"
+	FileDeleted  = "deleted"
+	FileRenamed  = "renamed"
+	FileModified = "modified"
+)
+
+// Hunk is a single hunk of a change touching one file
+type Hunk struct {
+	Kind      string     `json:"kind"`
+	Line      int        `json:"line,omitempty"` // first line in the new file, when known
+	Additions int        `json:"additions"`
+	Deletions int        `json:"deletions"`
+	Lines     []HunkLine `json:"lines,omitempty"`
+}
+
+// HunkLine is a line added or removed by a hunk
+type HunkLine struct {
+	Op   string `json:"op"` // "+" or "-"
+	Text string `json:"text"`
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^\d+\. (.*)$`)
+
+// hunkPrefixes maps the description pijul prints for a hunk to its kind.
+// Longer prefixes come first so "File un-deletion" is not taken for a
+// deletion.
+var hunkPrefixes = []struct {
+	prefix string
+	kind   string
+}{
+	{"File addition: ", HunkFileAddition},
+	{"File un-deletion: ", HunkFileUndeletion},
+	{"File deletion: ", HunkFileDeletion},
+	{"Moved: ", HunkMove},
+	{"Edit in ", HunkEdit},
+	{"Replacement in ", HunkReplacement},
+	{"Solving a name conflict in ", HunkSolveNameConflict},
+	{"Un-solving a name conflict in ", HunkUnsolveNameConflict},
+	{"Solving an order conflict in ", HunkSolveOrderConflict},
+	{"Un-solving an order conflict in ", HunkUnsolveOrderConflict},
+	{"Resurrecting zombie lines in ", HunkResurrectZombies},
+}
+
+// parseHunks reads the text representation of a change, as printed by
+// `pijul change` or `pijul diff`, and groups its hunks by file. Files are
+// returned in the order they first appear.
+//
+// Each hunk starts with a numbered header, e.g. `2. Edit in "src/main.rs":12
+// 4.37 "UTF-8"`, followed by indented lines describing graph edges, which are
+// skipped, and by the lines it adds or removes, prefixed with "+ " or "- ".
+func parseHunks(raw string) []FileDiff {
+	var files []FileDiff
+	index := map[string]int{}
+
+	var current *FileDiff
+	var hunk *Hunk
+	var patch *strings.Builder
+	patches := map[string]*strings.Builder{}
+
+	flush := func() {
+		if current == nil || hunk == nil {
+			return
+		}
+		current.Hunks = append(current.Hunks, *hunk)
+		current.Additions += hunk.Additions
+		current.Deletions += hunk.Deletions
+		hunk = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := hunkHeaderRe.FindStringSubmatch(line); m != nil {
+			flush()
+			current = nil
+
+			kind, filePath, oldPath, lineNo, ok := parseHunkHeader(m[1])
+			if !ok {
+				continue
+			}
+
+			i, seen := index[filePath]
+			if !seen {
+				i = len(files)
+				index[filePath] = i
+				files = append(files, FileDiff{Path: filePath, Status: FileModified})
+				patches[filePath] = &strings.Builder{}
+			}
+			current = &files[i]
+			patch = patches[filePath]
+
+			switch kind {
+			case HunkFileAddition:
+				current.Status = FileAdded
+			case HunkFileDeletion:
+				current.Status = FileDeleted
+			case HunkMove:
+				if current.Status == FileModified {
+					current.Status = FileRenamed
+				}
+				current.OldPath = oldPath
+			}
+
+			hunk = &Hunk{Kind: kind, Line: lineNo}
+			patch.WriteString(line)
+			patch.WriteByte('\n')
+			continue
+		}
+
+		if current == nil || hunk == nil {
+			continue
+		}
+
+		switch {
+		case line == "+" || strings.HasPrefix(line, "+ "):
+			hunk.Additions++
+			hunk.Lines = append(hunk.Lines, HunkLine{Op: "+", Text: strings.TrimPrefix(line[1:], " ")})
+		case line == "-" || strings.HasPrefix(line, "- "):
+			hunk.Deletions++
+			hunk.Lines = append(hunk.Lines, HunkLine{Op: "-", Text: strings.TrimPrefix(line[1:], " ")})
+		case strings.HasPrefix(line, "#"):
+			// a new section ends the hunk list
+			flush()
+			current = nil
+			continue
+		default:
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+		}
+
+		patch.WriteString(line)
+		patch.WriteByte('\n')
+	}
+	flush()
+
+	for i := range files {
+		files[i].Patch = patches[files[i].Path].String()
+	}
+
+	return files
+}
+
+// parseHunkHeader extracts the kind, path, old path (for moves) and line
+// number from the description following "N. " in a hunk header
+func parseHunkHeader(header string) (kind, filePath, oldPath string, line int, ok bool) {
+	rest := ""
+	for _, p := range hunkPrefixes {
+		if strings.HasPrefix(header, p.prefix) {
+			kind = p.kind
+			rest = header[len(p.prefix):]
+			break
+		}
+	}
+	if kind == "" {
+		return "", "", "", 0, false
+	}
+
+	first, rest, ok := nextQuoted(rest)
+	if !ok {
+		return "", "", "", 0, false
+	}
+
+	switch kind {
+	case HunkFileAddition:
+		// File addition: "name" in "parent" [+x|+dx] "encoding"
+		filePath = first
+		if strings.HasPrefix(rest, " in ") {
+			parent, attrs, ok := nextQuoted(rest)
+			if ok && parent != "" {
+				filePath = path.Join(parent, first)
+			}
+			// directories carry no content, only files are reported
+			if slices.Contains(strings.Fields(attrs), "+dx") {
+				return "", "", "", 0, false
+			}
+		}
+	case HunkMove:
+		// Moved: "old" "new" ...
+		oldPath = first
+		filePath = first
+		if second, _, ok := nextQuoted(rest); ok {
+			filePath = second
+		}
+	default:
+		filePath = first
+		if strings.HasPrefix(rest, ":") {
+			end := 1
+			for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+				end++
+			}
+			line, _ = strconv.Atoi(rest[1:end])
+		}
+	}
+
+	return kind, filePath, oldPath, line, true
+}
+
+// nextQuoted returns the first double-quoted string in s and the text
+// following it
+func nextQuoted(s string) (string, string, bool) {
+	start := strings.IndexByte(s, '"')
+	if start < 0 {
+		return "", s, false
+	}
+
+	if quoted, err := strconv.QuotedPrefix(s[start:]); err == nil {
+		if unquoted, err := strconv.Unquote(quoted); err == nil {
+			return unquoted, s[start+len(quoted):], true
+		}
+	}
+
+	// fall back to the raw text up to the closing quote
+	end := strings.IndexByte(s[start+1:], '"')
+	if end < 0 {
+		return "", s, false
+	}
+	return s[start+1 : start+1+end], s[start+end+2:], true
+}
+
+// diffStats sums up the additions and deletions of the given files
+func diffStats(files []FileDiff) *DiffStats {
+	stats := &DiffStats{FilesChanged: len(files)}
+	for _, f := range files {
+		stats.Additions += f.Additions
+		stats.Deletions += f.Deletions
+	}
+	return stats
+}
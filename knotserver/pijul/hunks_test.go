@@ -0,0 +1,89 @@
+package pijul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleChange = `message = 'rework greeting'
+timestamp = '2024-01-01T12:00:00.000000Z'
+
+[[authors]]
+key = 'abcdef'
+
+# Dependencies
+[2] AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAC
+
+# Hunks
+
+1. File addition: "src" in "" +dx
+  up 1.0, new 0:5
+
+2. File addition: "hello.txt" in "src" "UTF-8"
+  up 2.1, new 6:16
++ hello
++ world
+
+3. Edit in "README.md":3 4.37 "UTF-8"
+  up 4.120, new 17:30, down 4.120
++ a new paragraph
+
+4. Replacement in "README.md":10 4.37 "UTF-8"
+  B:BD 4.150 -> 4.150:170/4
+- old line
+  up 4.150, new 31:40, down 4.170
++ new line
++ another line
+
+5. Moved: "old.txt" "renamed.txt" 4.200
+
+6. File deletion: "gone.txt" 4.300 "UTF-8"
+  B:BD 4.310 -> 4.310:320/4
+- bye
+
+7. Solving an order conflict in "README.md":20 4.37 "UTF-8"
+  up 4.400, new 41:42, down 4.500
+`
+
+func TestParseDiff(t *testing.T) {
+	diff := parseDiff(sampleChange)
+	require.Len(t, diff.Files, 4)
+
+	added := diff.Files[0]
+	assert.Equal(t, "src/hello.txt", added.Path)
+	assert.Equal(t, FileAdded, added.Status)
+	assert.Equal(t, 2, added.Additions)
+	assert.Equal(t, []HunkLine{{Op: "+", Text: "hello"}, {Op: "+", Text: "world"}}, added.Hunks[0].Lines)
+
+	readme := diff.Files[1]
+	assert.Equal(t, "README.md", readme.Path)
+	assert.Equal(t, FileModified, readme.Status)
+	assert.Equal(t, 3, readme.Additions)
+	assert.Equal(t, 1, readme.Deletions)
+	require.Len(t, readme.Hunks, 3)
+	assert.Equal(t, HunkEdit, readme.Hunks[0].Kind)
+	assert.Equal(t, 3, readme.Hunks[0].Line)
+	assert.Equal(t, HunkReplacement, readme.Hunks[1].Kind)
+	assert.Equal(t, 10, readme.Hunks[1].Line)
+	assert.Equal(t, HunkSolveOrderConflict, readme.Hunks[2].Kind)
+
+	moved := diff.Files[2]
+	assert.Equal(t, "renamed.txt", moved.Path)
+	assert.Equal(t, "old.txt", moved.OldPath)
+	assert.Equal(t, FileRenamed, moved.Status)
+
+	deleted := diff.Files[3]
+	assert.Equal(t, "gone.txt", deleted.Path)
+	assert.Equal(t, FileDeleted, deleted.Status)
+	assert.Equal(t, 1, deleted.Deletions)
+
+	assert.Equal(t, &DiffStats{FilesChanged: 4, Additions: 5, Deletions: 2}, diff.Stats)
+}
+
+func TestParseDiffEmpty(t *testing.T) {
+	diff := parseDiff("")
+	assert.Empty(t, diff.Files)
+	assert.Equal(t, &DiffStats{}, diff.Stats)
+}
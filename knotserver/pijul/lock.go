@@ -0,0 +1,53 @@
+package pijul
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockFile is taken in .pijul by whatever writes to the repo, so that
+// nothing reads the pristine halfway through a write. It is a file lock
+// rather than a mutex, as pushes are written by pijul protocol processes
+// that the guard runs, outside the knot.
+const lockFile = "tangled.lock"
+
+// LockRepo takes the write lock of the repo at path, waiting for any other
+// writer or scratch copy to be done. The returned func releases it.
+func LockRepo(path string) (func(), error) {
+	return lockRepo(path, syscall.LOCK_EX)
+}
+
+// rLockRepo takes the lock of the repo at path shared, for reads that must
+// not see a write halfway through, such as copying the pristine
+func rLockRepo(path string) (func(), error) {
+	return lockRepo(path, syscall.LOCK_SH)
+}
+
+func lockRepo(path string, how int) (func(), error) {
+	f, err := os.OpenFile(filepath.Join(path, ".pijul", lockFile), os.O_CREATE|os.O_RDONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(f.Fd()), how); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	// closing the file releases the lock
+	return func() { f.Close() }, nil
+}
+
+// writesRepo reports whether the pijul command writes to the repo
+func writesRepo(command string, args []string) bool {
+	switch command {
+	case "apply", "unrecord", "pull", "record", "add", "remove", "protocol":
+		return true
+	case "channel":
+		return len(args) > 0 && args[0] != "list"
+	case "tag":
+		return len(args) > 0 && (args[0] == "create" || args[0] == "delete")
+	}
+	return false
+}
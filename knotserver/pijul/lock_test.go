@@ -0,0 +1,29 @@
+package pijul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritesRepo(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+		want    bool
+	}{
+		{"apply", []string{"abc"}, true},
+		{"protocol", []string{"--repository", "."}, true},
+		{"channel", nil, false},
+		{"channel", []string{"list"}, false},
+		{"channel", []string{"fork", "feature"}, true},
+		{"tag", []string{"--channel", "main"}, false},
+		{"tag", []string{"create", "-m", "v1"}, true},
+		{"log", []string{"--hash-only"}, false},
+		{"archive", []string{"-o", "x.tar.gz"}, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, writesRepo(tt.command, tt.args), "%s %v", tt.command, tt.args)
+	}
+}
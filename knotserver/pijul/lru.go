@@ -0,0 +1,96 @@
+package pijul
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lru is a concurrency safe cache keeping the most recently used values. It
+// holds at most size values, and when it has a cost func, values whose costs
+// add up to at most budget.
+type lru[T any] struct {
+	mu      sync.Mutex
+	size    int
+	budget  int64
+	cost    func(T) int64
+	used    int64
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry[T any] struct {
+	key   string
+	value T
+	cost  int64
+}
+
+func newLRU[T any](size int) *lru[T] {
+	return &lru[T]{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// newCostLRU makes an lru that also evicts values once their costs add up
+// to more than budget. Values costing more than the whole budget are not
+// cached at all.
+func newCostLRU[T any](size int, budget int64, cost func(T) int64) *lru[T] {
+	c := newLRU[T](size)
+	c.budget = budget
+	c.cost = cost
+	return c
+}
+
+func (c *lru[T]) get(key string) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruEntry[T]).value, true
+}
+
+func (c *lru[T]) put(key string, value T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var cost int64
+	if c.cost != nil {
+		cost = c.cost(value)
+		if cost > c.budget {
+			c.remove(key)
+			return
+		}
+	}
+
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*lruEntry[T])
+		c.used += cost - entry.cost
+		entry.value = value
+		entry.cost = cost
+		c.order.MoveToFront(el)
+	} else {
+		c.entries[key] = c.order.PushFront(&lruEntry[T]{key: key, value: value, cost: cost})
+		c.used += cost
+	}
+
+	for c.order.Len() > c.size || (c.cost != nil && c.used > c.budget) {
+		c.remove(c.order.Back().Value.(*lruEntry[T]).key)
+	}
+}
+
+// remove drops a key, the lock being held
+func (c *lru[T]) remove(key string) {
+	el, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	c.order.Remove(el)
+	c.used -= el.Value.(*lruEntry[T]).cost
+	delete(c.entries, key)
+}
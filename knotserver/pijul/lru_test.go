@@ -0,0 +1,42 @@
+package pijul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	c := newLRU[int](2)
+	c.put("a", 1)
+	c.put("b", 2)
+	c.get("a")
+	c.put("c", 3)
+
+	_, ok := c.get("b")
+	assert.False(t, ok, "the least recently used value should be evicted")
+	v, ok := c.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+}
+
+func TestCostLRU(t *testing.T) {
+	c := newCostLRU(10, 10, func(s string) int64 { return int64(len(s)) })
+	c.put("a", "aaaa")
+	c.put("b", "bbbb")
+	c.put("c", "cccc")
+
+	_, ok := c.get("a")
+	assert.False(t, ok, "values over the budget should be evicted")
+	_, ok = c.get("b")
+	assert.True(t, ok)
+	assert.Equal(t, int64(8), c.used)
+
+	c.put("huge", "hhhhhhhhhhh")
+	_, ok = c.get("huge")
+	assert.False(t, ok, "a value costing more than the budget should not be cached")
+	assert.Equal(t, int64(8), c.used)
+
+	c.put("b", "bb")
+	assert.Equal(t, int64(6), c.used)
+}
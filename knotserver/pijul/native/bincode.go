@@ -0,0 +1,159 @@
+package native
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var errShortBuffer = errors.New("unexpected end of data")
+
+// decoder reads values in bincode's default encoding, which is what pijul
+// uses for change files: fixed-width little endian integers, u64 length
+// prefixes for strings and sequences, and u32 enum tags
+type decoder struct {
+	buf []byte
+	off int
+}
+
+func (d *decoder) take(n int) ([]byte, error) {
+	if n < 0 || d.off+n > len(d.buf) {
+		return nil, errShortBuffer
+	}
+	b := d.buf[d.off : d.off+n]
+	d.off += n
+	return b, nil
+}
+
+func (d *decoder) u8() (uint8, error) {
+	b, err := d.take(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *decoder) u32() (uint32, error) {
+	b, err := d.take(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (d *decoder) u64() (uint64, error) {
+	b, err := d.take(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+// length reads a sequence length and checks it against the remaining data
+func (d *decoder) length(elemSize int) (int, error) {
+	n, err := d.u64()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.buf)-d.off)/uint64(max(elemSize, 1)) {
+		return 0, fmt.Errorf("sequence length %d exceeds data", n)
+	}
+	return int(n), nil
+}
+
+func (d *decoder) bytes() ([]byte, error) {
+	n, err := d.length(1)
+	if err != nil {
+		return nil, err
+	}
+	return d.take(n)
+}
+
+func (d *decoder) string() (string, error) {
+	b, err := d.bytes()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (d *decoder) optionalString() (*string, error) {
+	tag, err := d.u8()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case 0:
+		return nil, nil
+	case 1:
+		s, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		return &s, nil
+	default:
+		return nil, fmt.Errorf("invalid option tag %d", tag)
+	}
+}
+
+// hash reads a libpijul Hash enum, declared as `Hash { None, Blake3(..) }`,
+// so bincode tags None with 0 and Blake3 with 1, the same numbering as
+// hashAlgorithmBlake3. The None variant decodes to nil.
+func (d *decoder) hash() (*Hash, error) {
+	tag, err := d.u32()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case 0: // None
+		return nil, nil
+	case hashAlgorithmBlake3:
+		b, err := d.take(hashLen)
+		if err != nil {
+			return nil, err
+		}
+		var h Hash
+		copy(h[:], b)
+		return &h, nil
+	default:
+		return nil, fmt.Errorf("invalid hash tag %d", tag)
+	}
+}
+
+func (d *decoder) hashes() ([]Hash, error) {
+	n, err := d.length(4)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Hash, 0, n)
+	for range n {
+		h, err := d.hash()
+		if err != nil {
+			return nil, err
+		}
+		if h != nil {
+			out = append(out, *h)
+		}
+	}
+	return out, nil
+}
+
+func (d *decoder) stringMap() (map[string]string, error) {
+	n, err := d.length(16)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, n)
+	for range n {
+		k, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		out[k] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,195 @@
+package native
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// changeVersion is the change file format version understood by this reader
+const changeVersion = 6
+
+// offsetsSize is the size of the fixed header at the start of a change file
+const offsetsSize = 56
+
+var ErrUnsupportedVersion = errors.New("unsupported change file version")
+
+// offsets is the fixed header of a change file, locating its sections
+type offsets struct {
+	version     uint64
+	hashedLen   uint64 // decompressed length of the hashed section
+	unhashedOff uint64
+	unhashedLen uint64
+	contentsOff uint64
+	contentsLen uint64 // decompressed length of the contents section
+	total       uint64
+}
+
+// Change is the hashed part of a change file: its header and dependencies
+type Change struct {
+	Hash         string
+	Message      string
+	Description  string
+	Timestamp    time.Time
+	Authors      []Author
+	Dependencies []string
+	ExtraKnown   []string
+	ContentsHash string
+}
+
+// Author is a change author; pijul stores authors as free-form key/value maps
+type Author struct {
+	Name  string
+	Email string
+	Key   string // public key identifying the author, if signed
+}
+
+var zstdDecoder, _ = zstd.NewReader(nil)
+
+// ReadChange decodes the change file at the given path
+func ReadChange(path string) (*Change, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeChange(data)
+}
+
+func decodeChange(data []byte) (*Change, error) {
+	if len(data) < offsetsSize {
+		return nil, fmt.Errorf("change file too short: %d bytes", len(data))
+	}
+
+	d := decoder{buf: data[:offsetsSize]}
+	var o offsets
+	for _, field := range []*uint64{&o.version, &o.hashedLen, &o.unhashedOff, &o.unhashedLen, &o.contentsOff, &o.contentsLen, &o.total} {
+		v, err := d.u64()
+		if err != nil {
+			return nil, err
+		}
+		*field = v
+	}
+
+	if o.version != changeVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, o.version)
+	}
+	if o.unhashedOff < offsetsSize || o.unhashedOff > uint64(len(data)) {
+		return nil, fmt.Errorf("invalid hashed section end %d", o.unhashedOff)
+	}
+
+	hashed, err := zstdDecoder.DecodeAll(data[offsetsSize:o.unhashedOff], make([]byte, 0, o.hashedLen))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing change: %w", err)
+	}
+
+	return decodeHashed(hashed)
+}
+
+// decodeHashed decodes libpijul's Hashed struct:
+//
+//	version: u64
+//	header: { message: String, description: Option<String>,
+//	          timestamp: String, authors: Vec<BTreeMap<String, String>> }
+//	dependencies: Vec<Hash>
+//	extra_known: Vec<Hash>
+//	metadata: Vec<u8>
+//	changes: Vec<Hunk>
+//	contents_hash: Hash
+func decodeHashed(data []byte) (*Change, error) {
+	d := decoder{buf: data}
+	c := &Change{}
+
+	version, err := d.u64()
+	if err != nil {
+		return nil, err
+	}
+	if version != changeVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	if c.Message, err = d.string(); err != nil {
+		return nil, fmt.Errorf("reading message: %w", err)
+	}
+
+	description, err := d.optionalString()
+	if err != nil {
+		return nil, fmt.Errorf("reading description: %w", err)
+	}
+	if description != nil {
+		c.Description = *description
+	}
+
+	timestamp, err := d.string()
+	if err != nil {
+		return nil, fmt.Errorf("reading timestamp: %w", err)
+	}
+	if t, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+		c.Timestamp = t
+	}
+
+	n, err := d.length(8)
+	if err != nil {
+		return nil, fmt.Errorf("reading authors: %w", err)
+	}
+	for range n {
+		m, err := d.stringMap()
+		if err != nil {
+			return nil, fmt.Errorf("reading authors: %w", err)
+		}
+		c.Authors = append(c.Authors, authorFromMap(m))
+	}
+
+	deps, err := d.hashes()
+	if err != nil {
+		return nil, fmt.Errorf("reading dependencies: %w", err)
+	}
+	for _, h := range deps {
+		c.Dependencies = append(c.Dependencies, h.String())
+	}
+
+	extra, err := d.hashes()
+	if err != nil {
+		return nil, fmt.Errorf("reading extra known changes: %w", err)
+	}
+	for _, h := range extra {
+		c.ExtraKnown = append(c.ExtraKnown, h.String())
+	}
+
+	// metadata is opaque to us
+	if _, err := d.bytes(); err != nil {
+		return nil, fmt.Errorf("reading metadata: %w", err)
+	}
+
+	// hunks are not decoded; they are followed by the contents hash, which
+	// is a fixed 36 byte trailer
+	const trailer = 4 + hashLen
+	if len(data)-d.off < trailer {
+		return nil, errShortBuffer
+	}
+
+	d.off = len(data) - trailer
+	contentsHash, err := d.hash()
+	if err != nil {
+		return nil, fmt.Errorf("reading contents hash: %w", err)
+	}
+	if contentsHash != nil {
+		c.ContentsHash = contentsHash.String()
+	}
+
+	return c, nil
+}
+
+func authorFromMap(m map[string]string) Author {
+	a := Author{
+		Name:  m["name"],
+		Email: m["email"],
+		Key:   m["key"],
+	}
+	if full, ok := m["full_name"]; ok && full != "" {
+		a.Name = full
+	}
+	return a
+}
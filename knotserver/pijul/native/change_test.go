@@ -0,0 +1,162 @@
+package native
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encoder is the inverse of decoder, used to build change files in tests
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) u8(v uint8)   { e.buf = append(e.buf, v) }
+func (e *encoder) u32(v uint32) { e.buf = binary.LittleEndian.AppendUint32(e.buf, v) }
+func (e *encoder) u64(v uint64) { e.buf = binary.LittleEndian.AppendUint64(e.buf, v) }
+
+func (e *encoder) string(s string) {
+	e.u64(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) hash(h *Hash) {
+	// libpijul declares Hash { None, Blake3(..) }
+	if h == nil {
+		e.u32(0)
+		return
+	}
+	e.u32(1)
+	e.buf = append(e.buf, h[:]...)
+}
+
+func testHash(b byte) Hash {
+	var h Hash
+	for i := range h {
+		h[i] = b
+	}
+	return h
+}
+
+func encodeChange(t *testing.T, message string, deps []Hash) []byte {
+	t.Helper()
+
+	var e encoder
+	e.u64(changeVersion)
+	e.string(message)
+	e.u8(1)
+	e.string("a longer description")
+	e.string("2024-03-01T12:30:00.5Z")
+	e.u64(1) // authors
+	e.u64(2)
+	e.string("email")
+	e.string("alice@example.com")
+	e.string("name")
+	e.string("alice")
+	e.u64(uint64(len(deps)))
+	for _, d := range deps {
+		e.hash(&d)
+	}
+	e.u64(0) // extra_known
+	e.u64(0) // metadata
+	e.u64(0) // hunks
+	contents := testHash(9)
+	e.hash(&contents)
+
+	enc, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	compressed := enc.EncodeAll(e.buf, nil)
+
+	var header encoder
+	header.u64(changeVersion)
+	header.u64(uint64(len(e.buf)))
+	header.u64(uint64(offsetsSize + len(compressed)))
+	header.u64(0)
+	header.u64(uint64(offsetsSize + len(compressed)))
+	header.u64(0)
+	header.u64(uint64(offsetsSize + len(compressed)))
+
+	return append(header.buf, compressed...)
+}
+
+func TestHashRoundTrip(t *testing.T) {
+	h := testHash(7)
+	s := h.String()
+	assert.Len(t, s, 53)
+
+	parsed, err := ParseHash(s)
+	require.NoError(t, err)
+	assert.Equal(t, h, parsed)
+
+	_, err = ParseHash("not-a-hash")
+	assert.Error(t, err)
+}
+
+func TestDecodeHash(t *testing.T) {
+	// bincode writes the variant index of libpijul's Hash { None, Blake3 }
+	// as a little endian u32 before its contents
+	blake3 := append([]byte{1, 0, 0, 0}, bytes.Repeat([]byte{0xab}, hashLen)...)
+	d := &decoder{buf: blake3}
+	h, err := d.hash()
+	require.NoError(t, err)
+	require.NotNil(t, h)
+	assert.Equal(t, byte(0xab), h[0])
+	assert.Equal(t, len(blake3), d.off)
+
+	d = &decoder{buf: []byte{0, 0, 0, 0}}
+	h, err = d.hash()
+	require.NoError(t, err)
+	assert.Nil(t, h)
+
+	d = &decoder{buf: []byte{2, 0, 0, 0}}
+	_, err = d.hash()
+	assert.Error(t, err)
+}
+
+func TestReadChange(t *testing.T) {
+	dep := testHash(3)
+	data := encodeChange(t, "fix the thing", []Hash{dep})
+
+	root := t.TempDir()
+	repo := &Repo{changesDir: filepath.Join(root, ".pijul", "changes")}
+
+	h := testHash(5)
+	path := changePath(repo.changesDir, h)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	c, err := repo.Change(h.String())
+	require.NoError(t, err)
+
+	assert.Equal(t, h.String(), c.Hash)
+	assert.Equal(t, "fix the thing", c.Message)
+	assert.Equal(t, "a longer description", c.Description)
+	assert.Equal(t, time.Date(2024, 3, 1, 12, 30, 0, 500000000, time.UTC), c.Timestamp)
+	assert.Equal(t, []Author{{Name: "alice", Email: "alice@example.com"}}, c.Authors)
+	assert.Equal(t, []string{dep.String()}, c.Dependencies)
+	assert.Equal(t, testHash(9).String(), c.ContentsHash)
+
+	assert.True(t, repo.HasChange(h.String()))
+
+	hashes, err := repo.ChangeHashes()
+	require.NoError(t, err)
+	assert.Equal(t, []string{h.String()}, hashes)
+
+	_, err = repo.Change(testHash(6).String())
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestReadChangeUnsupportedVersion(t *testing.T) {
+	data := encodeChange(t, "msg", nil)
+	binary.LittleEndian.PutUint64(data, 4)
+
+	_, err := decodeChange(data)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
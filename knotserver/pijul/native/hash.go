@@ -0,0 +1,49 @@
+package native
+
+import (
+	"encoding/base32"
+	"fmt"
+	"path/filepath"
+)
+
+// hashAlgorithmBlake3 is the trailing byte pijul appends to a blake3 hash
+// before base32 encoding it
+const hashAlgorithmBlake3 = 1
+
+// hashLen is the length of a blake3 hash in bytes
+const hashLen = 32
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Hash is a pijul change or state hash
+type Hash [hashLen]byte
+
+// String returns the base32 representation used by the pijul CLI
+func (h Hash) String() string {
+	var buf [hashLen + 1]byte
+	copy(buf[:], h[:])
+	buf[hashLen] = hashAlgorithmBlake3
+	return encoding.EncodeToString(buf[:])
+}
+
+// ParseHash parses the base32 representation of a hash
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+
+	buf, err := encoding.DecodeString(s)
+	if err != nil {
+		return h, fmt.Errorf("invalid hash %q: %w", s, err)
+	}
+	if len(buf) != hashLen+1 || buf[hashLen] != hashAlgorithmBlake3 {
+		return h, fmt.Errorf("invalid hash %q: unsupported algorithm", s)
+	}
+
+	copy(h[:], buf[:hashLen])
+	return h, nil
+}
+
+// changePath returns the location of a change file inside .pijul/changes
+func changePath(changesDir string, h Hash) string {
+	s := h.String()
+	return filepath.Join(changesDir, s[:2], s[2:]+".change")
+}
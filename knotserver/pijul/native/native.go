@@ -0,0 +1,94 @@
+// Package native reads pijul change files directly from disk, without
+// spawning the pijul CLI.
+//
+// Only the header of a change file (.pijul/changes) is decoded: its message,
+// description, authors, timestamp and dependencies. Hunks are not, and
+// neither is the pristine, a sanakirja database holding the channels and
+// their logs, so listing channels, walking a log and reading file contents
+// are still answered by the CLI. Callers should treat any error from this
+// package as a signal to fall back to it, except ErrNotFound: the CLI reads
+// the same change files, so it would not find the change either.
+package native
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrNotFound = errors.New("change not found")
+
+// Repo is a read-only handle on a pijul repository
+type Repo struct {
+	changesDir string
+}
+
+// Open opens the pijul repository at path
+func Open(path string) (*Repo, error) {
+	changesDir := filepath.Join(path, ".pijul", "changes")
+	info, err := os.Stat(changesDir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", changesDir)
+	}
+
+	return &Repo{changesDir: changesDir}, nil
+}
+
+// Change reads the change with the given base32 hash
+func (r *Repo) Change(hash string) (*Change, error) {
+	h, err := ParseHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := ReadChange(changePath(r.changesDir, h))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, hash)
+		}
+		return nil, err
+	}
+
+	c.Hash = h.String()
+	return c, nil
+}
+
+// HasChange reports whether the repository stores the given change
+func (r *Repo) HasChange(hash string) bool {
+	h, err := ParseHash(hash)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(changePath(r.changesDir, h))
+	return err == nil
+}
+
+// ChangeHashes lists every change stored in the repository, whether or not
+// it is applied to a channel
+func (r *Repo) ChangeHashes() ([]string, error) {
+	var hashes []string
+
+	err := filepath.WalkDir(r.changesDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".change") {
+			return nil
+		}
+
+		prefix := filepath.Base(filepath.Dir(path))
+		hash := prefix + strings.TrimSuffix(d.Name(), ".change")
+		if _, err := ParseHash(hash); err == nil {
+			hashes = append(hashes, hash)
+		}
+		return nil
+	})
+
+	return hashes, err
+}
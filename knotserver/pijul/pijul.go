@@ -15,18 +15,26 @@ import (
 )
 
 var (
-	ErrBinaryFile    = errors.New("binary file")
-	ErrNotBinaryFile = errors.New("not binary file")
-	ErrNoPijulRepo   = errors.New("not a pijul repository")
+	ErrBinaryFile      = errors.New("binary file")
+	ErrNotBinaryFile   = errors.New("not binary file")
+	ErrNoPijulRepo     = errors.New("not a pijul repository")
 	ErrChannelNotFound = errors.New("channel not found")
 	ErrChangeNotFound  = errors.New("change not found")
 	ErrPathNotFound    = errors.New("path not found")
+	ErrChannelExists   = errors.New("channel already exists")
+	ErrDefaultChannel  = errors.New("cannot delete the default channel")
+	ErrInvalidChannel  = errors.New("invalid channel name")
 )
 
 // PijulRepo represents a Pijul repository
 type PijulRepo struct {
 	path        string
 	channelName string // current channel (empty means default)
+	state       string // pinned channel state (empty means latest)
+
+	tree *stateTree // lazily loaded recorded files at the current state
+
+	scratch bool // throwaway copy made by scratchCopy, never cached
 }
 
 // Open opens a Pijul repository at the given path with optional channel
@@ -84,13 +92,27 @@ func (p *PijulRepo) CurrentChannel() string {
 	return p.channelName
 }
 
-// FindDefaultChannel returns the default channel name
+// defaultChannelFile records the default channel of a repository, as pijul
+// has no equivalent of git's HEAD
+const defaultChannelFile = "default_channel"
+
+// FindDefaultChannel returns the default channel name. This is the channel
+// set with SetDefaultChannel, or "main", or else the first channel.
 func (p *PijulRepo) FindDefaultChannel() (string, error) {
 	channels, err := p.Channels()
 	if err != nil {
 		return "", err
 	}
 
+	if data, err := os.ReadFile(filepath.Join(p.path, ".pijul", defaultChannelFile)); err == nil {
+		name := strings.TrimSpace(string(data))
+		for _, ch := range channels {
+			if ch.Name == name {
+				return name, nil
+			}
+		}
+	}
+
 	// Look for 'main' first, then fall back to first channel
 	for _, ch := range channels {
 		if ch.Name == "main" {
@@ -106,83 +128,55 @@ func (p *PijulRepo) FindDefaultChannel() (string, error) {
 }
 
 // SetDefaultChannel changes which channel is considered default
-// In Pijul, this would typically be done by renaming channels
 func (p *PijulRepo) SetDefaultChannel(channel string) error {
-	// Pijul doesn't have a built-in default branch concept like git HEAD
-	// This is typically managed at application level
-	// For now, just verify the channel exists
-	channels, err := p.Channels()
+	exists, err := p.ChannelExists(channel)
 	if err != nil {
 		return err
 	}
-
-	for _, ch := range channels {
-		if ch.Name == channel {
-			return nil
-		}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrChannelNotFound, channel)
 	}
 
-	return fmt.Errorf("%w: %s", ErrChannelNotFound, channel)
+	return os.WriteFile(filepath.Join(p.path, ".pijul", defaultChannelFile), []byte(channel+"\n"), 0644)
 }
 
-// FileContent reads a file from the working copy at a specific path
-// Note: Pijul doesn't have the concept of reading files at a specific revision
-// like git. We read from the working directory or need to use pijul credit.
+// FileContent reads a file as recorded on the current channel
 func (p *PijulRepo) FileContent(filePath string) ([]byte, error) {
-	fullPath := filepath.Join(p.path, filePath)
-
-	content, err := os.ReadFile(fullPath)
+	tree, err := p.stateTree()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, filePath)
-		}
 		return nil, err
 	}
 
-	return content, nil
+	f, ok := tree.lookup(filePath)
+	if !ok || f.isDir {
+		return nil, fmt.Errorf("%w: %s", ErrPathNotFound, filePath)
+	}
+
+	return f.data, nil
 }
 
 // FileContentN reads up to cap bytes of a file
 func (p *PijulRepo) FileContentN(filePath string, cap int64) ([]byte, error) {
-	fullPath := filepath.Join(p.path, filePath)
-
-	f, err := os.Open(fullPath)
+	content, err := p.FileContent(filePath)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, filePath)
-		}
 		return nil, err
 	}
-	defer f.Close()
 
 	// Check if binary
-	buf := make([]byte, 512)
-	n, err := f.Read(buf)
-	if err != nil && err != io.EOF {
-		return nil, err
-	}
-	if isBinary(buf[:n]) {
+	if isBinary(content[:min(len(content), 512)]) {
 		return nil, ErrBinaryFile
 	}
 
-	// Reset and read up to cap
-	if _, err := f.Seek(0, 0); err != nil {
-		return nil, err
-	}
-
-	content := make([]byte, cap)
-	n, err = f.Read(content)
-	if err != nil && err != io.EOF {
-		return nil, err
+	if int64(len(content)) > cap {
+		content = content[:cap]
 	}
 
-	return content[:n], nil
+	return content, nil
 }
 
 // RawContent reads raw file content without binary check
 func (p *PijulRepo) RawContent(filePath string) ([]byte, error) {
-	fullPath := filepath.Join(p.path, filePath)
-	return os.ReadFile(fullPath)
+	return p.FileContent(filePath)
 }
 
 // isBinary checks if data appears to be binary
@@ -195,52 +189,31 @@ func isBinary(data []byte) bool {
 	return false
 }
 
-// WriteTar writes the repository contents to a tar archive
+// WriteTar writes the files recorded on the current channel to a tar archive
 func (p *PijulRepo) WriteTar(w io.Writer, prefix string) error {
+	tree, err := p.stateTree()
+	if err != nil {
+		return err
+	}
+
 	tw := tar.NewWriter(w)
 	defer tw.Close()
 
-	return filepath.Walk(p.path, func(path string, info fs.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Skip .pijul directory
-		if strings.Contains(path, ".pijul") {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		relPath, err := filepath.Rel(p.path, path)
-		if err != nil {
-			return err
-		}
-
-		if relPath == "." {
-			return nil
-		}
-
-		header, err := tar.FileInfoHeader(info, "")
+	return tree.walk("", func(relPath string, f *stateFile) error {
+		header, err := tar.FileInfoHeader(f.info(), "")
 		if err != nil {
 			return err
 		}
 
 		header.Name = filepath.Join(prefix, relPath)
+		header.ModTime = zeroTime
 
 		if err := tw.WriteHeader(header); err != nil {
 			return err
 		}
 
-		if !info.IsDir() {
-			f, err := os.Open(path)
-			if err != nil {
-				return err
-			}
-			defer f.Close()
-
-			if _, err := io.Copy(tw, f); err != nil {
+		if !f.isDir {
+			if _, err := tw.Write(f.data); err != nil {
 				return err
 			}
 		}
@@ -0,0 +1,177 @@
+package pijul
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ChannelSnapshot records the state of a single channel at a point in time
+type ChannelSnapshot struct {
+	// State is the Merkle state of the channel (empty for an empty channel)
+	State string `json:"state"`
+
+	// Changes are the hashes applied to the channel, oldest first
+	Changes []string `json:"changes"`
+}
+
+// PostPushLine describes a channel that was updated by a push
+// This is the Pijul equivalent of a git post-receive line
+type PostPushLine struct {
+	Channel  string   `json:"channel"`
+	OldState string   `json:"old_state,omitempty"` // empty for new channels
+	NewState string   `json:"new_state"`
+	Changes  []string `json:"changes"` // hashes added by the push, oldest first
+}
+
+// ChannelState returns the current Merkle state of a channel
+func (p *PijulRepo) ChannelState(channel string) (string, error) {
+	args := []string{"--state", "--limit", "1"}
+	if channel != "" {
+		args = append(args, "--channel", channel)
+	}
+
+	output, err := p.log(args...)
+	if err != nil {
+		if isNoChangesError(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("pijul log: %w", err)
+	}
+
+	return parseStateOutput(output), nil
+}
+
+// ChangeHashes returns the hashes of all changes on a channel, oldest first
+func (p *PijulRepo) ChangeHashes(channel string) ([]string, error) {
+	args := []string{"--hash-only"}
+	if channel != "" {
+		args = append(args, "--channel", channel)
+	}
+
+	output, err := p.log(args...)
+	if err != nil {
+		if isNoChangesError(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("pijul log: %w", err)
+	}
+
+	var hashes []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if h := strings.TrimSpace(scanner.Text()); h != "" {
+			hashes = append(hashes, h)
+		}
+	}
+
+	// pijul log lists the most recent change first
+	slices.Reverse(hashes)
+
+	return hashes, scanner.Err()
+}
+
+// Snapshot records the state of every channel in the repository
+func (p *PijulRepo) Snapshot() (map[string]ChannelSnapshot, error) {
+	channels, err := p.Channels()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make(map[string]ChannelSnapshot, len(channels))
+	for _, ch := range channels {
+		state, err := p.ChannelState(ch.Name)
+		if err != nil {
+			return nil, fmt.Errorf("reading state of channel %s: %w", ch.Name, err)
+		}
+
+		hashes, err := p.ChangeHashes(ch.Name)
+		if err != nil {
+			return nil, fmt.Errorf("reading changes of channel %s: %w", ch.Name, err)
+		}
+
+		snapshot[ch.Name] = ChannelSnapshot{
+			State:   state,
+			Changes: hashes,
+		}
+	}
+
+	return snapshot, nil
+}
+
+// DiffSnapshots compares two snapshots and returns a line for every channel
+// that was created or whose state changed. Deleted channels are ignored.
+func DiffSnapshots(before, after map[string]ChannelSnapshot) []PostPushLine {
+	names := make([]string, 0, len(after))
+	for name := range after {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var lines []PostPushLine
+	for _, name := range names {
+		newSnap := after[name]
+		oldSnap, existed := before[name]
+
+		if existed && oldSnap.State == newSnap.State {
+			continue
+		}
+
+		// an empty new channel has nothing to report
+		if !existed && newSnap.State == "" {
+			continue
+		}
+
+		known := make(map[string]struct{}, len(oldSnap.Changes))
+		for _, h := range oldSnap.Changes {
+			known[h] = struct{}{}
+		}
+
+		added := []string{}
+		for _, h := range newSnap.Changes {
+			if _, ok := known[h]; !ok {
+				added = append(added, h)
+			}
+		}
+
+		lines = append(lines, PostPushLine{
+			Channel:  name,
+			OldState: oldSnap.State,
+			NewState: newSnap.State,
+			Changes:  added,
+		})
+	}
+
+	return lines
+}
+
+// ParsePostPush decodes the payload sent by guard after a pijul push
+func ParsePostPush(r io.Reader) ([]PostPushLine, error) {
+	var lines []PostPushLine
+	if err := json.NewDecoder(r).Decode(&lines); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseStateOutput extracts the state from pijul log --state output
+// Expected format:
+//
+//	Change XXXXX
+//	Author: Name <email>
+//	Date: 2024-01-01 12:00:00
+//	State: YYYYY
+func parseStateOutput(output []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "State: ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "State: "))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,34 @@
+package pijul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	before := map[string]ChannelSnapshot{
+		"main":    {State: "S1", Changes: []string{"A", "B"}},
+		"feature": {State: "S2", Changes: []string{"A"}},
+		"stale":   {State: "S3", Changes: []string{"A"}},
+	}
+	after := map[string]ChannelSnapshot{
+		"main":    {State: "S4", Changes: []string{"A", "B", "C", "D"}},
+		"feature": {State: "S2", Changes: []string{"A"}},
+		"new":     {State: "S5", Changes: []string{"A", "E"}},
+		"empty":   {State: "", Changes: []string{}},
+	}
+
+	lines := DiffSnapshots(before, after)
+
+	assert.Equal(t, []PostPushLine{
+		{Channel: "main", OldState: "S1", NewState: "S4", Changes: []string{"C", "D"}},
+		{Channel: "new", NewState: "S5", Changes: []string{"A", "E"}},
+	}, lines)
+}
+
+func TestParseStateOutput(t *testing.T) {
+	output := []byte("Change AAAA\nAuthor: alice <alice@example.com>\nDate: 2024-01-01 12:00:00\nState: BBBB\n\n    message\n")
+	assert.Equal(t, "BBBB", parseStateOutput(output))
+	assert.Equal(t, "", parseStateOutput([]byte("")))
+}
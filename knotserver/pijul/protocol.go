@@ -0,0 +1,144 @@
+package pijul
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var ErrUnsupportedRequest = errors.New("unsupported pijul remote request")
+
+// ProtocolRequest is a single command of the pijul remote protocol, as spoken
+// by `pijul protocol` over SSH
+type ProtocolRequest struct {
+	// Line is the command line sent to `pijul protocol`, without the newline
+	Line string
+
+	// Framed responses are prefixed with their length as a big-endian u64,
+	// which the HTTP protocol does not use
+	Framed bool
+
+	// Apply is set for requests that modify the repository
+	Apply bool
+}
+
+// ParseHTTPRequest translates the query of a request to the pijul HTTP remote
+// protocol (served under /.pijul) to the equivalent protocol command.
+//
+// Read requests are:
+//   - channel=<c>&id
+//   - channel=<c>&state=[<n>]
+//   - channel=<c>&changelist=<from>[&path=<p>...]
+//   - change=<hash>
+//   - tag=<state>
+//
+// Uploads are apply=<hash>&to_channel=<c>, with the change in the body.
+func ParseHTTPRequest(query url.Values, bodySize int64) (*ProtocolRequest, error) {
+	channel := query.Get("channel")
+
+	// arguments are separated by whitespace, except for quoted paths
+	for k, v := range query {
+		if k == "path" {
+			continue
+		}
+		for _, s := range v {
+			if strings.ContainsAny(s, " \t\r\n") {
+				return nil, fmt.Errorf("%w: whitespace in argument", ErrUnsupportedRequest)
+			}
+		}
+	}
+
+	switch {
+	case query.Has("apply"):
+		hash := query.Get("apply")
+		toChannel := query.Get("to_channel")
+		if hash == "" || toChannel == "" {
+			return nil, fmt.Errorf("%w: apply needs a hash and a channel", ErrUnsupportedRequest)
+		}
+		return &ProtocolRequest{
+			Line:  fmt.Sprintf("apply %s %s %d", toChannel, hash, bodySize),
+			Apply: true,
+		}, nil
+
+	case query.Has("change"):
+		hash := query.Get("change")
+		if hash == "" {
+			return nil, fmt.Errorf("%w: missing change hash", ErrUnsupportedRequest)
+		}
+		return &ProtocolRequest{Line: "change " + hash, Framed: true}, nil
+
+	case query.Has("tag"):
+		state := query.Get("tag")
+		if state == "" {
+			return nil, fmt.Errorf("%w: missing tag state", ErrUnsupportedRequest)
+		}
+		return &ProtocolRequest{Line: "tag " + state, Framed: true}, nil
+
+	case channel == "":
+		return nil, fmt.Errorf("%w: missing channel", ErrUnsupportedRequest)
+
+	case query.Has("id"):
+		return &ProtocolRequest{Line: "id " + channel}, nil
+
+	case query.Has("state"):
+		line := "state " + channel
+		if n := query.Get("state"); n != "" {
+			if _, err := strconv.ParseUint(n, 10, 64); err != nil {
+				return nil, fmt.Errorf("%w: invalid state position", ErrUnsupportedRequest)
+			}
+			line += " " + n
+		}
+		return &ProtocolRequest{Line: line}, nil
+
+	case query.Has("changelist"):
+		from := query.Get("changelist")
+		if from == "" {
+			from = "0"
+		}
+		if _, err := strconv.ParseUint(from, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid changelist position", ErrUnsupportedRequest)
+		}
+		line := fmt.Sprintf("changelist %s %s", channel, from)
+		for _, p := range query["path"] {
+			line += " " + strconv.Quote(p)
+		}
+		return &ProtocolRequest{Line: line}, nil
+	}
+
+	return nil, ErrUnsupportedRequest
+}
+
+// Protocol runs a single protocol request against the repository and
+// returns the response in the form expected by HTTP clients
+func (p *PijulRepo) Protocol(req *ProtocolRequest, body []byte) ([]byte, error) {
+	stdin := append([]byte(req.Line+"\n"), body...)
+
+	output, err := p.runPijulCmdWithStdin(stdin, "protocol", "--repository", p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Framed {
+		return unframe(output)
+	}
+
+	return output, nil
+}
+
+// unframe strips the length prefix from a framed protocol response
+func unframe(output []byte) ([]byte, error) {
+	if len(output) < 8 {
+		return nil, ErrChangeNotFound
+	}
+
+	size := binary.BigEndian.Uint64(output[:8])
+	output = output[8:]
+	if uint64(len(output)) < size {
+		return nil, fmt.Errorf("truncated protocol response: expected %d bytes, got %d", size, len(output))
+	}
+
+	return output[:size], nil
+}
@@ -0,0 +1,72 @@
+package pijul
+
+import (
+	"encoding/binary"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHTTPRequest(t *testing.T) {
+	tests := []struct {
+		query string
+		want  ProtocolRequest
+	}{
+		{"channel=main&id", ProtocolRequest{Line: "id main"}},
+		{"state=&channel=main", ProtocolRequest{Line: "state main"}},
+		{"state=12&channel=main", ProtocolRequest{Line: "state main 12"}},
+		{"channel=main&changelist=0", ProtocolRequest{Line: "changelist main 0"}},
+		{"channel=main&changelist=3&path=a%20b.txt", ProtocolRequest{Line: `changelist main 3 "a b.txt"`}},
+		{"change=AAAA", ProtocolRequest{Line: "change AAAA", Framed: true}},
+		{"tag=SSSS", ProtocolRequest{Line: "tag SSSS", Framed: true}},
+		{"apply=AAAA&to_channel=dev", ProtocolRequest{Line: "apply dev AAAA 42", Apply: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			req, err := ParseHTTPRequest(query, 42)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *req)
+		})
+	}
+}
+
+func TestParseHTTPRequestInvalid(t *testing.T) {
+	for _, q := range []string{
+		"",
+		"id",
+		"channel=main",
+		"channel=main&state=x",
+		"channel=ma%20in&id",
+		"apply=AAAA",
+		"change=",
+	} {
+		t.Run(q, func(t *testing.T) {
+			query, err := url.ParseQuery(q)
+			require.NoError(t, err)
+
+			_, err = ParseHTTPRequest(query, 0)
+			assert.ErrorIs(t, err, ErrUnsupportedRequest)
+		})
+	}
+}
+
+func TestUnframe(t *testing.T) {
+	framed := binary.BigEndian.AppendUint64(nil, 3)
+	framed = append(framed, "abc"...)
+
+	out, err := unframe(framed)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("abc"), out)
+
+	_, err = unframe(framed[:9])
+	assert.Error(t, err)
+
+	_, err = unframe(nil)
+	assert.ErrorIs(t, err, ErrChangeNotFound)
+}
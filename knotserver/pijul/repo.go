@@ -100,9 +100,13 @@ func (p *PijulRepo) Pull(remote string, channel string) error {
 	return err
 }
 
-// Apply applies a change to the repository
+// Apply applies a change to the current channel
 func (p *PijulRepo) Apply(changeHash string) error {
-	_, err := p.runPijulCmd("apply", changeHash)
+	args := []string{changeHash}
+	if p.channelName != "" {
+		args = append(args, "--channel", p.channelName)
+	}
+	_, err := p.runPijulCmd("apply", args...)
 	return err
 }
 
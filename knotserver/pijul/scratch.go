@@ -0,0 +1,107 @@
+package pijul
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// scratchCopy makes a throwaway copy of the repo, for operations that write
+// to the pristine, such as applying changes to see what they would do,
+// without touching the repo that is served. The pristine and the rest of
+// .pijul are copied. Change files are hard linked instead, as they are
+// content addressed and never written to once recorded; they are copied
+// when the temp dir is on another filesystem.
+//
+// The copy is taken under a shared repo lock, so no write is halfway
+// through while it is made. It is opened on the same channel and state as p. Callers must call
+// the returned cleanup func once done with it.
+func (p *PijulRepo) scratchCopy() (*PijulRepo, func(), error) {
+	tmp, err := os.MkdirTemp("", "pijul-scratch-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmp) }
+
+	unlock, err := rLockRepo(p.path)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("locking repository: %w", err)
+	}
+	defer unlock()
+
+	src := filepath.Join(p.path, ".pijul")
+	dst := filepath.Join(tmp, ".pijul")
+	changes := filepath.Join(src, "changes")
+
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0755)
+		case !d.Type().IsRegular() || rel == lockFile:
+			// pijul keeps nothing but directories and files in .pijul
+			return nil
+		case isWithin(changes, path):
+			if err := os.Link(path, target); err == nil {
+				return nil
+			}
+			return copyFile(path, target)
+		default:
+			return copyFile(path, target)
+		}
+	})
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("copying repository: %w", err)
+	}
+
+	scratch := &PijulRepo{
+		path:        tmp,
+		channelName: p.channelName,
+		state:       p.state,
+		scratch:     true,
+	}
+	return scratch, cleanup, nil
+}
+
+// isWithin reports whether path is inside dir
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && filepath.IsLocal(rel)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
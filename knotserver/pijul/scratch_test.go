@@ -0,0 +1,53 @@
+package pijul
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScratchCopy(t *testing.T) {
+	repo := t.TempDir()
+	files := map[string]string{
+		".pijul/pristine/db":            "pristine",
+		".pijul/changes/AB/CDEF.change": "change",
+		".pijul/config":                 "config",
+		".pijul/" + defaultChannelFile:  "main",
+		"README":                        "working copy",
+	}
+	for name, data := range files {
+		path := filepath.Join(repo, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	}
+
+	p := &PijulRepo{path: repo, channelName: "dev"}
+	scratch, cleanup, err := p.scratchCopy()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, repo, scratch.Path())
+	assert.Equal(t, "dev", scratch.CurrentChannel())
+
+	for name, data := range files {
+		got, err := os.ReadFile(filepath.Join(scratch.Path(), name))
+		if name == "README" {
+			assert.True(t, os.IsNotExist(err), "the working copy should not be copied")
+			continue
+		}
+		require.NoError(t, err, name)
+		assert.Equal(t, data, string(got), name)
+	}
+
+	// writes to the pristine of the copy leave the repo alone
+	require.NoError(t, os.WriteFile(filepath.Join(scratch.Path(), ".pijul/pristine/db"), []byte("applied"), 0644))
+	got, err := os.ReadFile(filepath.Join(repo, ".pijul/pristine/db"))
+	require.NoError(t, err)
+	assert.Equal(t, "pristine", string(got))
+
+	cleanup()
+	_, err = os.Stat(scratch.Path())
+	assert.True(t, os.IsNotExist(err))
+}
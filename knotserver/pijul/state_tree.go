@@ -0,0 +1,354 @@
+package pijul
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// stateTree is an in-memory, read-only view of the files recorded on a
+// channel at a given state. It is built from `pijul archive`, which works on
+// bare repositories and never touches the working copy.
+type stateTree struct {
+	files    map[string]*stateFile
+	children map[string][]string // directory path -> sorted child names
+	size     int64               // total size of the file contents
+}
+
+type stateFile struct {
+	name  string
+	mode  fs.FileMode
+	data  []byte
+	isDir bool
+}
+
+func (f *stateFile) info() fs.FileInfo {
+	return &infoWrapper{
+		name:  path.Base(f.name),
+		size:  int64(len(f.data)),
+		mode:  f.mode,
+		isDir: f.isDir,
+	}
+}
+
+const (
+	// treeCacheSize bounds the number of channel states kept in memory
+	treeCacheSize = 64
+
+	// treeCacheBytes bounds the file contents kept in memory across all
+	// cached states. Larger states are read again on every request.
+	treeCacheBytes = 256 << 20
+)
+
+// treeCache holds recently used state trees. States are content addressed,
+// so a cached tree never goes stale; it is only evicted.
+var treeCache = newCostLRU(treeCacheSize, treeCacheBytes, func(t *stateTree) int64 {
+	return t.size
+})
+
+// latestStateCacheSize bounds the number of channels whose latest state is
+// remembered
+const latestStateCacheSize = 1024
+
+// latestStateCache remembers the latest state of channels, keyed by the
+// modification time and size of the pristine they were read from, so that
+// reads do not run pijul log while nothing was applied to the repo.
+var latestStateCache = newLRU[string](latestStateCacheSize)
+
+// AtChange pins reads of files to the state of the channel right after the
+// given change was applied, instead of the latest state
+func (p *PijulRepo) AtChange(hash string) error {
+	state, err := p.StateAtChange(hash)
+	if err != nil {
+		return err
+	}
+	p.state = state
+	p.tree = nil
+	return nil
+}
+
+// StateAtChange returns the state of the current channel right after the
+// given change was applied
+func (p *PijulRepo) StateAtChange(hash string) (string, error) {
+	args := []string{"--state"}
+	if p.channelName != "" {
+		args = append(args, "--channel", p.channelName)
+	}
+
+	output, err := p.log(args...)
+	if err != nil {
+		return "", fmt.Errorf("pijul log: %w", err)
+	}
+
+	return stateAfter(output, hash)
+}
+
+// stateAfter finds the state following a change in the output of pijul log
+// --state
+func stateAfter(output []byte, hash string) (string, error) {
+	var current string
+	var hashes []string
+	states := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "Change "):
+			current = strings.TrimPrefix(line, "Change ")
+		case strings.HasPrefix(line, "Hash: "):
+			current = strings.TrimPrefix(line, "Hash: ")
+		case strings.HasPrefix(line, "State: "):
+			hashes = append(hashes, current)
+			states[current] = strings.TrimPrefix(line, "State: ")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	// an abbreviated hash matching several changes names none of them
+	full, ok := resolveHash(hash, hashes)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrChangeNotFound, hash)
+	}
+
+	return states[full], nil
+}
+
+// stateTree returns the file tree of the current channel at the pinned
+// state, or at its latest state if none was pinned
+func (p *PijulRepo) stateTree() (*stateTree, error) {
+	if p.tree != nil {
+		return p.tree, nil
+	}
+
+	state := p.state
+	if state == "" {
+		var err error
+		state, err = p.latestState()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// an empty channel has no state and no files
+	if state == "" {
+		p.tree = &stateTree{
+			files:    map[string]*stateFile{},
+			children: map[string][]string{"": {}},
+		}
+		return p.tree, nil
+	}
+
+	// scratch copies are deleted right after use, so their trees are never
+	// looked up again
+	if p.scratch {
+		tree, err := p.loadStateTree(state)
+		if err != nil {
+			return nil, err
+		}
+		p.tree = tree
+		return tree, nil
+	}
+
+	key := p.path + "\x00" + state
+	if tree, ok := treeCache.get(key); ok {
+		p.tree = tree
+		return tree, nil
+	}
+
+	tree, err := p.loadStateTree(state)
+	if err != nil {
+		return nil, err
+	}
+
+	treeCache.put(key, tree)
+	p.tree = tree
+	return tree, nil
+}
+
+// latestState returns the latest state of the current channel, reusing the
+// last one read as long as the pristine was not written to since
+func (p *PijulRepo) latestState() (string, error) {
+	// scratch copies are written to while in use
+	if p.scratch {
+		return p.ChannelState(p.channelName)
+	}
+
+	info, err := os.Stat(filepath.Join(p.path, ".pijul", "pristine", "db"))
+	if err != nil {
+		return p.ChannelState(p.channelName)
+	}
+
+	key := fmt.Sprintf("%s\x00%s\x00%d\x00%d", p.path, p.channelName, info.ModTime().UnixNano(), info.Size())
+	if state, ok := latestStateCache.get(key); ok {
+		return state, nil
+	}
+
+	state, err := p.ChannelState(p.channelName)
+	if err != nil {
+		return "", err
+	}
+
+	latestStateCache.put(key, state)
+	return state, nil
+}
+
+// loadStateTree archives the channel at the given state and reads it back
+func (p *PijulRepo) loadStateTree(state string) (*stateTree, error) {
+	tmp, err := os.MkdirTemp("", "pijul-archive-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	archivePath := filepath.Join(tmp, "archive.tar.gz")
+
+	args := []string{"-o", archivePath, "--state", state}
+	if p.channelName != "" {
+		args = append(args, "--channel", p.channelName)
+	}
+
+	if _, err := p.runPijulCmd("archive", args...); err != nil {
+		return nil, fmt.Errorf("pijul archive: %w", err)
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readStateTree(f)
+}
+
+// readStateTree builds a stateTree from a gzipped tar archive
+func readStateTree(r io.Reader) (*stateTree, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tree := &stateTree{
+		files:    map[string]*stateFile{},
+		children: map[string][]string{"": {}},
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.Trim(path.Clean("/"+header.Name), "/")
+		if name == "" {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			tree.addDir(name)
+		case tar.TypeReg, tar.TypeSymlink:
+			mode := fs.FileMode(header.Mode).Perm()
+			var data []byte
+			if header.Typeflag == tar.TypeSymlink {
+				// a symlink has no contents in the archive, its target is
+				// what git stores as its blob
+				mode |= fs.ModeSymlink
+				data = []byte(header.Linkname)
+			} else if data, err = io.ReadAll(tr); err != nil {
+				return nil, err
+			}
+			tree.addDir(path.Dir(name))
+			tree.size += int64(len(data))
+			tree.files[name] = &stateFile{
+				name: name,
+				mode: mode,
+				data: data,
+			}
+			tree.addChild(name)
+		}
+	}
+
+	for _, names := range tree.children {
+		slices.Sort(names)
+	}
+
+	return tree, nil
+}
+
+func (t *stateTree) addDir(name string) {
+	if name == "." || name == "" {
+		return
+	}
+	if _, ok := t.files[name]; ok {
+		return
+	}
+	t.addDir(path.Dir(name))
+	t.files[name] = &stateFile{
+		name:  name,
+		mode:  fs.ModeDir | 0755,
+		isDir: true,
+	}
+	t.children[name] = []string{}
+	t.addChild(name)
+}
+
+func (t *stateTree) addChild(name string) {
+	parent := path.Dir(name)
+	if parent == "." {
+		parent = ""
+	}
+	t.children[parent] = append(t.children[parent], path.Base(name))
+}
+
+// lookup returns the entry at the given path, the root being a directory
+func (t *stateTree) lookup(p string) (*stateFile, bool) {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if p == "" {
+		return &stateFile{mode: fs.ModeDir | 0755, isDir: true}, true
+	}
+	f, ok := t.files[p]
+	return f, ok
+}
+
+// walk visits every entry below root in lexical order
+func (t *stateTree) walk(root string, fn func(p string, f *stateFile) error) error {
+	root = strings.Trim(path.Clean("/"+root), "/")
+	for _, name := range t.children[root] {
+		child := path.Join(root, name)
+		f := t.files[child]
+		if err := fn(child, f); err != nil {
+			if errors.Is(err, fs.SkipDir) && f.isDir {
+				continue
+			}
+			return err
+		}
+		if f.isDir {
+			if err := t.walk(child, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// zeroTime is used as the modification time of archived entries, since
+// pijul does not track file timestamps
+var zeroTime = time.Unix(0, 0)
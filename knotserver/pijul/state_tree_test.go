@@ -0,0 +1,127 @@
+package pijul
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeArchive(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(contents)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return &buf
+}
+
+func TestReadStateTree(t *testing.T) {
+	archive := makeArchive(t, map[string]string{
+		"readme.md":       "hello",
+		"src/main.go":     "package main",
+		"src/pkg/lib.go":  "package pkg",
+		".tangled/ci.yml": "when: []",
+	})
+
+	tree, err := readStateTree(archive)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{".tangled", "readme.md", "src"}, tree.children[""])
+	assert.Equal(t, []string{"main.go", "pkg"}, tree.children["src"])
+
+	assert.Equal(t, int64(len("hello")+len("package main")+len("package pkg")+len("when: []")), tree.size)
+
+	f, ok := tree.lookup("src/pkg/lib.go")
+	require.True(t, ok)
+	assert.Equal(t, "package pkg", string(f.data))
+	assert.Equal(t, fs.FileMode(0644), f.mode)
+
+	dir, ok := tree.lookup("/src/")
+	require.True(t, ok)
+	assert.True(t, dir.isDir)
+
+	_, ok = tree.lookup("missing")
+	assert.False(t, ok)
+
+	var walked []string
+	require.NoError(t, tree.walk("", func(p string, f *stateFile) error {
+		walked = append(walked, p)
+		return nil
+	}))
+	assert.Equal(t, []string{
+		".tangled", ".tangled/ci.yml",
+		"readme.md",
+		"src", "src/main.go", "src/pkg", "src/pkg/lib.go",
+	}, walked)
+}
+
+func TestReadStateTreeSymlink(t *testing.T) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name:     "link",
+		Mode:     0777,
+		Linkname: "target/file.txt",
+		Typeflag: tar.TypeSymlink,
+	}))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	tree, err := readStateTree(&buf)
+	require.NoError(t, err)
+
+	f, ok := tree.lookup("link")
+	require.True(t, ok)
+	assert.Equal(t, "target/file.txt", string(f.data))
+	assert.Equal(t, fs.ModeSymlink, f.mode.Type())
+	assert.Equal(t, "120000", gitMode(f.mode))
+}
+
+func TestStateAfter(t *testing.T) {
+	output := []byte(`Change AAAAAAAA1111
+Author: alice
+State: STATE1
+
+Change AAAAAAAA2222
+Author: bob
+State: STATE2
+
+Change BBBBBBBB3333
+Author: carol
+State: STATE3
+`)
+
+	state, err := stateAfter(output, "AAAAAAAA2222")
+	require.NoError(t, err)
+	assert.Equal(t, "STATE2", state)
+
+	state, err = stateAfter(output, "BBBBBBBB")
+	require.NoError(t, err)
+	assert.Equal(t, "STATE3", state)
+
+	_, err = stateAfter(output, "AAAAAAAA")
+	assert.ErrorIs(t, err, ErrChangeNotFound, "a prefix of several changes is ambiguous")
+
+	_, err = stateAfter(output, "CCCCCCCC")
+	assert.ErrorIs(t, err, ErrChangeNotFound)
+}
@@ -0,0 +1,201 @@
+package pijul
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrTagNotFound is returned when no tag matches a name or state
+var ErrTagNotFound = errors.New("tag not found")
+
+// Tag is a named snapshot of a channel's state. Pijul tags have no name of
+// their own, so by convention the first line of the message is used.
+type Tag struct {
+	// State is the Merkle state of the channel that was tagged
+	State string `json:"state"`
+
+	// Name is the first line of the tag message, or the abbreviated state
+	// if there is none
+	Name string `json:"name"`
+
+	// Message is the full tag message
+	Message string `json:"message"`
+
+	// Channel the tag was created on
+	Channel string `json:"channel"`
+
+	// Authors who created the tag
+	Authors []Author `json:"authors"`
+
+	// Timestamp when the tag was created
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Tags returns the tags of every channel, most recent first
+func (p *PijulRepo) Tags() ([]Tag, error) {
+	channels, err := p.Channels()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []Tag{}
+	for _, ch := range channels {
+		output, err := p.runPijulCmd("tag", "--channel", ch.Name)
+		if err != nil {
+			return nil, fmt.Errorf("pijul tag: %w", err)
+		}
+
+		channelTags, err := parseTagOutput(output, ch.Name)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, channelTags...)
+	}
+
+	slices.SortStableFunc(tags, func(a, b Tag) int {
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+
+	return tags, nil
+}
+
+// FindTag looks up a tag by name or by state. A state may be abbreviated to
+// a unique prefix of at least 8 characters. When several tags share a name,
+// the most recent one wins.
+func (p *PijulRepo) FindTag(ref string) (*Tag, error) {
+	tags, err := p.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	if t, ok := findTag(tags, ref); ok {
+		return &t, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrTagNotFound, ref)
+}
+
+// CreateTag tags the latest state of the current channel
+func (p *PijulRepo) CreateTag(message string, author *Author) (*Tag, error) {
+	args := []string{"create", "-m", message}
+	if p.channelName != "" {
+		args = append(args, "--channel", p.channelName)
+	}
+	if author != nil {
+		a := author.Name
+		if author.Email != "" {
+			a = fmt.Sprintf("%s <%s>", author.Name, author.Email)
+		}
+		args = append(args, "--author", a)
+	}
+
+	if _, err := p.runPijulCmd("tag", args...); err != nil {
+		return nil, fmt.Errorf("pijul tag create: %w", err)
+	}
+
+	state, err := p.ChannelState(p.channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.FindTag(state)
+}
+
+// DeleteTag removes the tag at the given state from the current channel
+func (p *PijulRepo) DeleteTag(state string) error {
+	args := []string{"delete", state}
+	if p.channelName != "" {
+		args = append(args, "--channel", p.channelName)
+	}
+
+	if _, err := p.runPijulCmd("tag", args...); err != nil {
+		return fmt.Errorf("pijul tag delete: %w", err)
+	}
+	return nil
+}
+
+// AtTag pins reads of files to the state of a tag, on the channel it was
+// created on, and returns the tag
+func (p *PijulRepo) AtTag(ref string) (*Tag, error) {
+	tag, err := p.FindTag(ref)
+	if err != nil {
+		return nil, err
+	}
+	p.channelName = tag.Channel
+	p.state = tag.State
+	p.tree = nil
+	return tag, nil
+}
+
+// findTag matches ref against tag states first, then names
+func findTag(tags []Tag, ref string) (Tag, bool) {
+	states := make([]string, len(tags))
+	for i, t := range tags {
+		states[i] = t.State
+	}
+	if state, ok := resolveHash(ref, states); ok {
+		return tags[slices.Index(states, state)], true
+	}
+
+	for _, t := range tags {
+		if t.Name == ref {
+			return t, true
+		}
+	}
+
+	return Tag{}, false
+}
+
+// parseTagOutput parses the output of `pijul tag`, which lists tags in the
+// same layout as `pijul log`:
+//
+//	State MDS3XBQ3E5ZBQ...
+//	Author: Alice <alice@example.com>
+//	Date: 2024-01-15 10:30:00 +0000
+//
+//	    v1.0.0
+func parseTagOutput(output []byte, channel string) ([]Tag, error) {
+	// rewrite the state lines so the log parser picks them up
+	var buf bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if state, ok := strings.CutPrefix(line, "State "); ok {
+			line = "Change " + strings.TrimSpace(state)
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := parseLogOutput(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]Tag, 0, len(entries))
+	for _, e := range entries {
+		name, _, _ := strings.Cut(e.Message, "\n")
+		name = strings.TrimSpace(name)
+		if name == "" && len(e.Hash) >= 8 {
+			name = e.Hash[:8]
+		}
+		tags = append(tags, Tag{
+			State:     e.Hash,
+			Name:      name,
+			Message:   e.Message,
+			Channel:   channel,
+			Authors:   e.Authors,
+			Timestamp: e.Timestamp,
+		})
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,57 @@
+package pijul
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTagOutput(t *testing.T) {
+	output := []byte(`State MDS3XBQ3E5ZBQMVRJ2ZBDCMVMJYZXYKHR4XMRXQTLNWB3K3OHRAC
+Author: Alice <alice@example.com>
+Date: 2024-01-15 10:30:00 +0000
+
+    v1.0.0
+
+    First stable release
+
+State QJ4KZBHGV6PGN2PHNVXAYXT2EDTXPOBMNJU5A3DO7X7KPRYFLB4AC
+Author: Bob
+Date: 2024-01-10 09:00:00 +0000
+
+`)
+
+	tags, err := parseTagOutput(output, "main")
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+
+	assert.Equal(t, "MDS3XBQ3E5ZBQMVRJ2ZBDCMVMJYZXYKHR4XMRXQTLNWB3K3OHRAC", tags[0].State)
+	assert.Equal(t, "v1.0.0", tags[0].Name)
+	assert.Equal(t, "v1.0.0\n\nFirst stable release", tags[0].Message)
+	assert.Equal(t, "main", tags[0].Channel)
+	assert.Equal(t, []Author{{Name: "Alice", Email: "alice@example.com"}}, tags[0].Authors)
+	assert.Equal(t, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), tags[0].Timestamp.UTC())
+
+	// untitled tags are named after their state
+	assert.Equal(t, "QJ4KZBHG", tags[1].Name)
+}
+
+func TestFindTag(t *testing.T) {
+	tags := []Tag{
+		{State: "MDS3XBQ3E5ZBQMVR", Name: "v1.1.0"},
+		{State: "QJ4KZBHGV6PGN2PH", Name: "v1.0.0"},
+	}
+
+	tag, ok := findTag(tags, "v1.0.0")
+	assert.True(t, ok)
+	assert.Equal(t, "QJ4KZBHGV6PGN2PH", tag.State)
+
+	tag, ok = findTag(tags, "MDS3XBQ3")
+	assert.True(t, ok)
+	assert.Equal(t, "v1.1.0", tag.Name)
+
+	_, ok = findTag(tags, "v2.0.0")
+	assert.False(t, ok)
+}
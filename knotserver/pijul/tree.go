@@ -2,10 +2,9 @@ package pijul
 
 import (
 	"context"
+	"fmt"
 	"io/fs"
-	"os"
 	"path"
-	"path/filepath"
 	"strings"
 
 	"tangled.org/core/types"
@@ -19,46 +18,34 @@ type TreeEntry struct {
 	IsDir bool        `json:"is_dir"`
 }
 
-// FileTree returns the file tree at the given path
-// For Pijul, we read directly from the working directory
+// FileTree returns the file tree at the given path, as recorded on the
+// current channel
 func (p *PijulRepo) FileTree(ctx context.Context, treePath string) ([]types.NiceTree, error) {
-	fullPath := filepath.Join(p.path, treePath)
-
-	info, err := os.Stat(fullPath)
+	tree, err := p.stateTree()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, ErrPathNotFound
-		}
 		return nil, err
 	}
 
-	// If it's a file, return empty (no tree for files)
-	if !info.IsDir() {
-		return []types.NiceTree{}, nil
+	entry, ok := tree.lookup(treePath)
+	if !ok {
+		return nil, ErrPathNotFound
 	}
 
-	entries, err := os.ReadDir(fullPath)
-	if err != nil {
-		return nil, err
+	// If it's a file, return empty (no tree for files)
+	if !entry.isDir {
+		return []types.NiceTree{}, nil
 	}
 
-	trees := make([]types.NiceTree, 0, len(entries))
-
-	for _, entry := range entries {
-		// Skip .pijul directory
-		if entry.Name() == ".pijul" {
-			continue
-		}
-
-		info, err := entry.Info()
-		if err != nil {
-			continue
-		}
+	dir := strings.Trim(path.Clean("/"+treePath), "/")
+	names := tree.children[dir]
+	trees := make([]types.NiceTree, 0, len(names))
 
+	for _, name := range names {
+		f := tree.files[path.Join(dir, name)]
 		trees = append(trees, types.NiceTree{
-			Name: entry.Name(),
-			Mode: fileModeToString(info.Mode()),
-			Size: info.Size(),
+			Name: name,
+			Mode: fileModeToString(f.mode),
+			Size: int64(len(f.data)),
 			// LastCommit would require additional work to implement
 			// For now, we leave it nil
 		})
@@ -87,13 +74,12 @@ type WalkCallback func(path string, info fs.FileInfo, isDir bool) error
 
 // Walk traverses the file tree
 func (p *PijulRepo) Walk(ctx context.Context, root string, cb WalkCallback) error {
-	startPath := filepath.Join(p.path, root)
-
-	return filepath.WalkDir(startPath, func(walkPath string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
+	tree, err := p.stateTree()
+	if err != nil {
+		return err
+	}
 
+	return tree.walk(root, func(walkPath string, f *stateFile) error {
 		// Check context
 		select {
 		case <-ctx.Done():
@@ -101,46 +87,26 @@ func (p *PijulRepo) Walk(ctx context.Context, root string, cb WalkCallback) erro
 		default:
 		}
 
-		// Skip .pijul directory
-		if strings.Contains(walkPath, ".pijul") {
-			if d.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		// Get relative path
-		relPath, err := filepath.Rel(p.path, walkPath)
-		if err != nil {
-			return err
-		}
-
-		if relPath == "." {
-			return nil
-		}
-
-		info, err := d.Info()
-		if err != nil {
-			return err
-		}
-
-		return cb(relPath, info, d.IsDir())
+		return cb(walkPath, f.info(), f.isDir)
 	})
 }
 
-// ListFiles returns all tracked files in the repository
+// ListFiles returns all tracked files on the current channel
 func (p *PijulRepo) ListFiles() ([]string, error) {
-	output, err := p.runPijulCmd("ls")
+	tree, err := p.stateTree()
 	if err != nil {
 		return nil, err
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 1 && lines[0] == "" {
-		return []string{}, nil
-	}
+	files := []string{}
+	err = tree.walk("", func(walkPath string, f *stateFile) error {
+		if !f.isDir {
+			files = append(files, walkPath)
+		}
+		return nil
+	})
 
-	return lines, nil
+	return files, err
 }
 
 // IsTracked checks if a file is tracked by Pijul
@@ -159,21 +125,27 @@ func (p *PijulRepo) IsTracked(filePath string) (bool, error) {
 	return false, nil
 }
 
-// FileExists checks if a file exists in the working directory
+// FileExists checks if a file is recorded on the current channel
 func (p *PijulRepo) FileExists(filePath string) bool {
-	fullPath := filepath.Join(p.path, filePath)
-	_, err := os.Stat(fullPath)
-	return err == nil
+	tree, err := p.stateTree()
+	if err != nil {
+		return false
+	}
+	_, ok := tree.lookup(filePath)
+	return ok
 }
 
 // IsDir checks if a path is a directory
 func (p *PijulRepo) IsDir(treePath string) (bool, error) {
-	fullPath := filepath.Join(p.path, treePath)
-	info, err := os.Stat(fullPath)
+	tree, err := p.stateTree()
 	if err != nil {
 		return false, err
 	}
-	return info.IsDir(), nil
+	f, ok := tree.lookup(treePath)
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrPathNotFound, treePath)
+	}
+	return f.isDir, nil
 }
 
 // MakeNiceTree creates a NiceTree from file info
@@ -0,0 +1,101 @@
+package pijul
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrHasDependents is returned when unrecording a change would leave other
+// changes on the channel without one of their dependencies
+var ErrHasDependents = errors.New("change has dependents")
+
+// DependentsError lists the changes that still depend on the changes being
+// unrecorded
+type DependentsError struct {
+	Dependents []string
+}
+
+func (e *DependentsError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrHasDependents, strings.Join(e.Dependents, ", "))
+}
+
+func (e *DependentsError) Unwrap() error {
+	return ErrHasDependents
+}
+
+// UnrecordChanges removes the given changes from the current channel. If
+// other changes on the channel depend on them, a *DependentsError is
+// returned, unless cascade is set, in which case those are unrecorded too.
+// The hashes actually unrecorded are returned, most recent first.
+func (p *PijulRepo) UnrecordChanges(hashes []string, cascade bool) ([]string, error) {
+	onChannel, err := p.ChangeHashes(p.channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := map[string]bool{}
+	var roots []string
+	for _, h := range hashes {
+		full, ok := resolveHash(h, onChannel)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrChangeNotFound, h)
+		}
+		targets[full] = true
+		roots = append(roots, full)
+	}
+
+	deps := map[string][]string{}
+	for _, h := range onChannel {
+		c, err := p.GetChange(h)
+		if err != nil {
+			return nil, fmt.Errorf("loading change %s: %w", h, err)
+		}
+		deps[h] = c.Dependencies
+	}
+
+	blocking := dependents(roots, onChannel, deps)
+	if len(blocking) > 0 && !cascade {
+		return nil, &DependentsError{Dependents: blocking}
+	}
+	for _, h := range blocking {
+		targets[h] = true
+	}
+
+	// unrecord newest first, so no change ever loses a dependency
+	var removed []string
+	for _, h := range slices.Backward(onChannel) {
+		if !targets[h] {
+			continue
+		}
+		if err := p.Unrecord(h); err != nil {
+			return removed, fmt.Errorf("pijul unrecord %s: %w", h, err)
+		}
+		removed = append(removed, h)
+	}
+
+	return removed, nil
+}
+
+// resolveHash finds the full hash on the channel for a possibly abbreviated
+// one
+func resolveHash(hash string, hashes []string) (string, bool) {
+	if slices.Contains(hashes, hash) {
+		return hash, true
+	}
+	if len(hash) < 8 {
+		return "", false
+	}
+
+	var match string
+	for _, h := range hashes {
+		if strings.HasPrefix(h, hash) {
+			if match != "" {
+				return "", false
+			}
+			match = h
+		}
+	}
+	return match, match != ""
+}
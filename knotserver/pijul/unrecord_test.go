@@ -0,0 +1,21 @@
+package pijul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveHash(t *testing.T) {
+	hashes := []string{"AAAAAAAAAAAA", "AAAAAAAABBBB", "CCCCCCCCCCCC"}
+
+	h, ok := resolveHash("CCCCCCCC", hashes)
+	assert.True(t, ok)
+	assert.Equal(t, "CCCCCCCCCCCC", h)
+
+	_, ok = resolveHash("AAAAAAAA", hashes)
+	assert.False(t, ok, "ambiguous prefix")
+
+	_, ok = resolveHash("CCCC", hashes)
+	assert.False(t, ok, "prefix too short")
+}
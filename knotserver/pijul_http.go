@@ -0,0 +1,158 @@
+package knotserver
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/go-chi/chi/v5"
+	"tangled.org/core/knotserver/pijul"
+	"tangled.org/core/rbac"
+	"tangled.org/core/xrpc/serviceauth"
+)
+
+// changes larger than this are rejected by PijulApply
+const maxPijulChangeSize = 256 << 20
+
+// PijulRemote serves read requests of the pijul HTTP remote protocol, used
+// by anonymous clones and pulls
+func (h *Knot) PijulRemote(w http.ResponseWriter, r *http.Request) {
+	l := h.l.With("handler", "PijulRemote")
+
+	_, pr, ok := h.pijulRepo(w, r)
+	if !ok {
+		return
+	}
+
+	req, err := pijul.ParseHTTPRequest(r.URL.Query(), 0)
+	if err != nil || req.Apply {
+		gitError(w, "unsupported pijul request", http.StatusBadRequest)
+		return
+	}
+
+	h.pijulProtocol(w, l, pr, req, nil)
+}
+
+// PijulApply serves uploads of the pijul HTTP remote protocol. Requests are
+// authenticated with a service auth token, and the caller must be allowed
+// to apply changes to the repo.
+func (h *Knot) PijulApply(w http.ResponseWriter, r *http.Request) {
+	l := h.l.With("handler", "PijulApply")
+
+	didSlashRepo, pr, ok := h.pijulRepo(w, r)
+	if !ok {
+		return
+	}
+
+	actorDid, ok := r.Context().Value(serviceauth.ActorDid).(syntax.DID)
+	if !ok {
+		gitError(w, "missing actor did", http.StatusUnauthorized)
+		return
+	}
+
+	if ok, err := h.e.E.Enforce(actorDid.String(), rbac.ThisServer, didSlashRepo, rbac.PijulApply); !ok || err != nil {
+		l.Error("insufficient permissions", "did", actorDid.String(), "repo", didSlashRepo)
+		gitError(w, "insufficient permissions", http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPijulChangeSize+1))
+	if err != nil {
+		gitError(w, "failed to read change", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxPijulChangeSize {
+		gitError(w, "change too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	req, err := pijul.ParseHTTPRequest(r.URL.Query(), int64(len(body)))
+	if err != nil || !req.Apply {
+		gitError(w, "unsupported pijul request", http.StatusBadRequest)
+		return
+	}
+
+	// pijul has no server-side hooks, so snapshot the channels around the
+	// apply to find out what it changed, as guard does for ssh pushes
+	before, err := pr.Snapshot()
+	if err != nil {
+		l.Error("failed to snapshot pijul repo", "error", err)
+		// non-fatal
+	}
+
+	if !h.pijulProtocol(w, l, pr, req, body) {
+		return
+	}
+
+	if before == nil {
+		return
+	}
+
+	after, err := pr.Snapshot()
+	if err != nil {
+		l.Error("failed to snapshot pijul repo", "error", err)
+		return
+	}
+
+	if lines := pijul.DiffSnapshots(before, after); len(lines) > 0 {
+		ih := InternalHandle{db: h.db, c: h.c, e: h.e, l: h.l, n: h.n, res: h.resolver}
+		repoDid, repoName, _ := strings.Cut(didSlashRepo, "/")
+		// the response is the pijul protocol's, and it has already been
+		// written, so there is nowhere to show the messages to the pusher
+		messages := ih.processPijulPush(lines, repoDid, repoName, actorDid.String())
+		for _, m := range messages {
+			l.Debug("pijul push message", "message", m)
+		}
+	}
+}
+
+// pijulRepo opens the pijul repo of a request, and writes a not found
+// response if there is none
+func (h *Knot) pijulRepo(w http.ResponseWriter, r *http.Request) (string, *pijul.PijulRepo, bool) {
+	did := chi.URLParam(r, "did")
+	name := chi.URLParam(r, "name")
+
+	didSlashRepo, err := securejoin.SecureJoin(did, name)
+	if err != nil {
+		gitError(w, "repository not found", http.StatusNotFound)
+		return "", nil, false
+	}
+
+	repoPath, err := securejoin.SecureJoin(h.c.Repo.ScanPath, didSlashRepo)
+	if err != nil {
+		gitError(w, "repository not found", http.StatusNotFound)
+		return "", nil, false
+	}
+
+	pr, err := pijul.PlainOpen(repoPath)
+	if err != nil {
+		gitError(w, "repository not found", http.StatusNotFound)
+		return "", nil, false
+	}
+
+	return didSlashRepo, pr, true
+}
+
+// pijulProtocol runs a protocol request and writes its response
+func (h *Knot) pijulProtocol(w http.ResponseWriter, l *slog.Logger, pr *pijul.PijulRepo, req *pijul.ProtocolRequest, body []byte) bool {
+	out, err := pr.Protocol(req, body)
+	if errors.Is(err, pijul.ErrChangeNotFound) {
+		gitError(w, "change not found", http.StatusNotFound)
+		return false
+	}
+	if err != nil {
+		l.Error("pijul protocol failed", "request", req.Line, "error", err)
+		gitError(w, "pijul protocol failed", http.StatusInternalServerError)
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Cache-Control", "no-cache, max-age=0, must-revalidate")
+	w.WriteHeader(http.StatusOK)
+	w.Write(out)
+	return true
+}
@@ -12,6 +12,8 @@ import (
 	"tangled.org/core/jetstream"
 	"tangled.org/core/knotserver/config"
 	"tangled.org/core/knotserver/db"
+	"tangled.org/core/knotserver/git"
+	"tangled.org/core/knotserver/pijul"
 	"tangled.org/core/knotserver/xrpc"
 	"tangled.org/core/log"
 	"tangled.org/core/notifier"
@@ -71,6 +73,7 @@ func Setup(ctx context.Context, c *config.Config, db *db.DB, e *rbac.Enforcer, j
 
 func (h *Knot) Router() http.Handler {
 	r := chi.NewRouter()
+	serviceAuth := serviceauth.NewServiceAuth(h.l, h.resolver, h.c.Server.Did().String())
 
 	r.Use(h.CORS)
 	r.Use(h.RequestLogger)
@@ -87,6 +90,10 @@ func (h *Knot) Router() http.Handler {
 			r.Post("/git-upload-archive", h.UploadArchive)
 			r.Post("/git-upload-pack", h.UploadPack)
 			r.Post("/git-receive-pack", h.ReceivePack)
+
+			// routes for pijul operations
+			r.Get("/.pijul", h.PijulRemote)
+			r.With(serviceAuth.VerifyServiceAuth).Post("/.pijul", h.PijulApply)
 		})
 	})
 
@@ -113,6 +120,14 @@ func (h *Knot) XrpcRouter() http.Handler {
 		Notifier:    h.n,
 		Resolver:    h.resolver,
 		ServiceAuth: serviceAuth,
+		ProcessGitPush: func(lines []git.PostReceiveLine, repoDid, repoName, pusherDid string) []string {
+			ih := InternalHandle{db: h.db, c: h.c, e: h.e, l: h.l, n: h.n, res: h.resolver}
+			return ih.processGitPush(lines, pusherDid, repoDid, repoName, PushOptions{})
+		},
+		ProcessPijulPush: func(lines []pijul.PostPushLine, repoDid, repoName, pusherDid string) []string {
+			ih := InternalHandle{db: h.db, c: h.c, e: h.e, l: h.l, n: h.n, res: h.resolver}
+			return ih.processPijulPush(lines, repoDid, repoName, pusherDid)
+		},
 	}
 
 	return xrpc.Router()
@@ -80,6 +80,8 @@ func Run(ctx context.Context, cmd *cli.Command) error {
 		tangled.PublicKeyNSID,
 		tangled.KnotMemberNSID,
 		tangled.RepoPullNSID,
+		tangled.RepoDiscussionPatchNSID,
+		tangled.RepoDiscussionPatchStateNSID,
 		tangled.RepoCollaboratorNSID,
 	}, nil, log.SubLogger(logger, "jetstream"), db, true, c.Server.LogDids)
 	if err != nil {
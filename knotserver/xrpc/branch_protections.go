@@ -0,0 +1,201 @@
+package xrpc
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/rbac"
+	"tangled.org/core/types"
+	xrpcerr "tangled.org/core/xrpc/errors"
+)
+
+// RepoBranchProtections handles the sh.tangled.repo.branchProtections endpoint
+// Lists the protection rules of a repo, sorted by pattern
+func (x *Xrpc) RepoBranchProtections(w http.ResponseWriter, r *http.Request) {
+	repo := r.URL.Query().Get("repo")
+	if _, err := x.parseRepoParam(repo); err != nil {
+		writeError(w, err.(xrpcerr.XrpcError), http.StatusBadRequest)
+		return
+	}
+
+	repoParts := strings.SplitN(repo, "/", 2)
+	didSlashRepo, err := securejoin.SecureJoin(repoParts[0], repoParts[1])
+	if err != nil {
+		writeError(w, xrpcerr.InvalidRepoError(repo), http.StatusBadRequest)
+		return
+	}
+
+	rules, err := x.Db.GetBranchProtections(didSlashRepo)
+	if err != nil {
+		x.Logger.Error("listing branch protections", "error", err.Error())
+		writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	response := tangled.RepoBranchProtections_Output{
+		Rules: make([]*tangled.RepoBranchProtections_Rule, len(rules)),
+	}
+	for i, p := range rules {
+		response.Rules[i] = &tangled.RepoBranchProtections_Rule{
+			Pattern:        p.Pattern,
+			NoForcePush:    p.NoForcePush,
+			NoDeletion:     p.NoDeletion,
+			RequirePull:    p.RequirePull,
+			AllowedPushers: p.AllowedPushers,
+		}
+	}
+
+	writeJson(w, response)
+}
+
+// RepoSetBranchProtection handles the sh.tangled.repo.setBranchProtection endpoint
+// Adds a protection rule, replacing the rule with the same pattern
+func (x *Xrpc) RepoSetBranchProtection(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoSetBranchProtection")
+	fail := func(e xrpcerr.XrpcError, status int) {
+		l.Error("failed", "kind", e.Tag, "error", e.Message)
+		writeError(w, e, status)
+	}
+
+	actorDid, ok := r.Context().Value(ActorDid).(syntax.DID)
+	if !ok {
+		fail(xrpcerr.MissingActorDidError, http.StatusBadRequest)
+		return
+	}
+
+	var req tangled.RepoSetBranchProtection_Input
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Rule == nil {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("invalid request body"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	rule := types.BranchProtection{
+		Pattern:        req.Rule.Pattern,
+		NoForcePush:    req.Rule.NoForcePush,
+		NoDeletion:     req.Rule.NoDeletion,
+		RequirePull:    req.Rule.RequirePull,
+		AllowedPushers: req.Rule.AllowedPushers,
+	}
+	if err := rule.Validate(); err != nil {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage(err.Error()),
+		), http.StatusBadRequest)
+		return
+	}
+
+	didSlashRepo, ok := x.authorizeEditProtections(w, l, actorDid, req.Repo)
+	if !ok {
+		return
+	}
+
+	if err := x.Db.SetBranchProtection(didSlashRepo, rule); err != nil {
+		fail(xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	l.Info("set branch protection", "repo", didSlashRepo, "pattern", rule.Pattern, "did", actorDid.String())
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// RepoDeleteBranchProtection handles the sh.tangled.repo.deleteBranchProtection endpoint
+func (x *Xrpc) RepoDeleteBranchProtection(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoDeleteBranchProtection")
+	fail := func(e xrpcerr.XrpcError, status int) {
+		l.Error("failed", "kind", e.Tag, "error", e.Message)
+		writeError(w, e, status)
+	}
+
+	actorDid, ok := r.Context().Value(ActorDid).(syntax.DID)
+	if !ok {
+		fail(xrpcerr.MissingActorDidError, http.StatusBadRequest)
+		return
+	}
+
+	var req tangled.RepoDeleteBranchProtection_Input
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("invalid request body"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	if req.Pattern == "" {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("pattern is required"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	didSlashRepo, ok := x.authorizeEditProtections(w, l, actorDid, req.Repo)
+	if !ok {
+		return
+	}
+
+	if err := x.Db.RemoveBranchProtection(didSlashRepo, req.Pattern); err != nil {
+		fail(xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	l.Info("deleted branch protection", "repo", didSlashRepo, "pattern", req.Pattern, "did", actorDid.String())
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// authorizeEditProtections checks that the actor may change the settings of
+// the repo, writing the error response if not
+func (x *Xrpc) authorizeEditProtections(w http.ResponseWriter, l *slog.Logger, actorDid syntax.DID, repo string) (string, bool) {
+	if _, err := x.parseRepoParam(repo); err != nil {
+		writeError(w, err.(xrpcerr.XrpcError), http.StatusBadRequest)
+		return "", false
+	}
+
+	repoParts := strings.SplitN(repo, "/", 2)
+	didSlashRepo, err := securejoin.SecureJoin(repoParts[0], repoParts[1])
+	if err != nil {
+		writeError(w, xrpcerr.InvalidRepoError(repo), http.StatusBadRequest)
+		return "", false
+	}
+
+	if ok, err := x.Enforcer.IsSettingsAllowed(actorDid.String(), rbac.ThisServer, didSlashRepo); !ok || err != nil {
+		l.Error("insufficent permissions", "did", actorDid.String())
+		writeError(w, xrpcerr.AccessControlError(actorDid.String()), http.StatusUnauthorized)
+		return "", false
+	}
+
+	return didSlashRepo, true
+}
+
+// checkBranchProtection checks an update made through the knot, rather than
+// by pushing, against the protection rules of a repo, writing the error
+// response if it is rejected
+func (x *Xrpc) checkBranchProtection(w http.ResponseWriter, l *slog.Logger, didSlashRepo string, update types.BranchUpdate) bool {
+	rules, err := x.Db.GetBranchProtections(didSlashRepo)
+	if err != nil {
+		l.Error("failed to get branch protections", "error", err.Error())
+		writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return false
+	}
+
+	if err := types.CheckBranchUpdate(rules, update); err != nil {
+		l.Info("rejected branch update", "repo", didSlashRepo, "branch", update.Branch, "reason", err)
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("BranchProtected"),
+			xrpcerr.WithMessage(err.Error()),
+		), http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
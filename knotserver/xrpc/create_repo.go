@@ -106,6 +106,16 @@ func (h *Xrpc) CreateRepo(w http.ResponseWriter, r *http.Request) {
 			writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
 			return
 		}
+
+		// pijul has no hidden refs to track upstream with, so remember where
+		// the fork came from
+		if vcs == "pijul" {
+			if err := h.Db.AddPijulFork(relativeRepoPath, *data.Source); err != nil {
+				l.Error("recording fork upstream", "error", err.Error())
+				writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+				return
+			}
+		}
 	} else {
 		if vcs == "pijul" {
 			err = pijul.InitBare(repoPath)
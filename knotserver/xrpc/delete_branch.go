@@ -12,6 +12,7 @@ import (
 	"tangled.org/core/api/tangled"
 	"tangled.org/core/knotserver/git"
 	"tangled.org/core/rbac"
+	"tangled.org/core/types"
 
 	xrpcerr "tangled.org/core/xrpc/errors"
 )
@@ -69,6 +70,14 @@ func (x *Xrpc) DeleteBranch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !x.checkBranchProtection(w, l, didPath, types.BranchUpdate{
+		Branch: data.Branch,
+		Pusher: actorDid.String(),
+		Delete: true,
+	}) {
+		return
+	}
+
 	path, _ := securejoin.SecureJoin(x.Config.Repo.ScanPath, didPath)
 	gr, err := git.PlainOpen(path)
 	if err != nil {
@@ -117,6 +117,15 @@ func (x *Xrpc) DeleteRepo(w http.ResponseWriter, r *http.Request) {
 			writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
 			return
 		}
+		if err := x.Db.RemovePijulFork(relativeRepoPath); err != nil {
+			l.Error("failed to delete pijul fork upstream", "error", err.Error())
+			// non-fatal
+		}
+	} else {
+		if err := x.Db.RemoveBranchProtections(relativeRepoPath); err != nil {
+			l.Error("failed to delete branch protections", "error", err.Error())
+			// non-fatal
+		}
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -55,6 +55,14 @@ func (x *Xrpc) Merge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !x.checkBranchProtection(w, l, relativeRepoPath, types.BranchUpdate{
+		Branch: data.Branch,
+		Pusher: actorDid.String(),
+		Merge:  true,
+	}) {
+		return
+	}
+
 	repoPath, err := securejoin.SecureJoin(x.Config.Repo.ScanPath, relativeRepoPath)
 	if err != nil {
 		fail(xrpcerr.GenericError(err))
@@ -81,6 +89,19 @@ func (x *Xrpc) Merge(w http.ResponseWriter, r *http.Request) {
 		mo.CommitMessage = *data.CommitMessage
 	}
 
+	if data.Strategy != nil {
+		mo.Strategy = types.MergeStrategy(*data.Strategy)
+		if !mo.Strategy.IsValid() {
+			fail(xrpcerr.GenericError(fmt.Errorf("unknown merge strategy: %s", *data.Strategy)))
+			return
+		}
+	}
+
+	if mo.Strategy == types.MergeStrategyFastForward {
+		x.fastForward(w, r, gr, data, actorDid)
+		return
+	}
+
 	mo.CommitterName = x.Config.Git.UserName
 	mo.CommitterEmail = x.Config.Git.UserEmail
 	mo.FormatPatch = patchutil.IsFormatPatch(data.Patch)
@@ -112,3 +133,45 @@ func (x *Xrpc) Merge(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// fastForward moves the target branch to the tip of the source branch of the
+// repo. The patch is not used, as the commits are already in the repo.
+func (x *Xrpc) fastForward(w http.ResponseWriter, r *http.Request, gr *git.GitRepo, data tangled.RepoMerge_Input, actorDid syntax.DID) {
+	l := x.Logger.With("handler", "Merge", "strategy", types.MergeStrategyFastForward)
+
+	if data.SourceBranch == nil || *data.SourceBranch == "" {
+		writeError(w, xrpcerr.GenericError(fmt.Errorf("a source branch is required to fast-forward")), http.StatusBadRequest)
+		return
+	}
+
+	var sourceRev string
+	if data.SourceRev != nil {
+		sourceRev = *data.SourceRev
+	}
+
+	line, err := gr.FastForward(*data.SourceBranch, data.Branch, sourceRev)
+	if err != nil {
+		var mergeErr *git.ErrMerge
+		switch {
+		case errors.As(err, &mergeErr) && errors.Is(mergeErr.OtherError, git.ErrNotFastForward):
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("NotFastForward"),
+				xrpcerr.WithMessage(fmt.Sprintf("Cannot fast-forward: %s", mergeErr.Message)),
+			), http.StatusConflict)
+		case errors.As(err, &mergeErr) && errors.Is(mergeErr.OtherError, git.ErrSourceMoved):
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("SourceMoved"),
+				xrpcerr.WithMessage(fmt.Sprintf("Cannot fast-forward: %s", mergeErr.Message)),
+			), http.StatusConflict)
+		default:
+			l.Error("failed to fast-forward", "error", err.Error())
+			writeError(w, xrpcerr.GitError(err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// no hook runs for the update, so record it as a push would
+	x.ProcessGitPush([]git.PostReceiveLine{*line}, data.Did, data.Name, actorDid.String())
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -2,9 +2,14 @@ package xrpc
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	securejoin "github.com/cyphar/filepath-securejoin"
 	"tangled.org/core/knotserver/pijul"
+	"tangled.org/core/rbac"
 	xrpcerr "tangled.org/core/xrpc/errors"
 )
 
@@ -13,11 +18,12 @@ type ApplyChangesRequest struct {
 	Repo    string   `json:"repo"`
 	Channel string   `json:"channel"`
 	Changes []string `json:"changes"`
+	Atomic  bool     `json:"atomic,omitempty"`
 }
 
 // ApplyChangesResponse is the response for applying changes
 type ApplyChangesResponse struct {
-	Applied []string              `json:"applied"`
+	Applied []string             `json:"applied"`
 	Failed  []ApplyChangeFailure `json:"failed,omitempty"`
 }
 
@@ -61,6 +67,25 @@ func (x *Xrpc) RepoApplyChanges(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	actorDid, ok := r.Context().Value(ActorDid).(syntax.DID)
+	if !ok {
+		writeError(w, xrpcerr.MissingActorDidError, http.StatusBadRequest)
+		return
+	}
+
+	repoParts := strings.SplitN(req.Repo, "/", 2)
+	didSlashRepo, err := securejoin.SecureJoin(repoParts[0], repoParts[1])
+	if err != nil {
+		writeError(w, xrpcerr.InvalidRepoError(req.Repo), http.StatusBadRequest)
+		return
+	}
+
+	if ok, err := x.Enforcer.E.Enforce(actorDid.String(), rbac.ThisServer, didSlashRepo, rbac.PijulApply); !ok || err != nil {
+		x.Logger.Error("insufficent permissions", "did", actorDid.String())
+		writeError(w, xrpcerr.AccessControlError(actorDid.String()), http.StatusUnauthorized)
+		return
+	}
+
 	// Open the repository with the target channel
 	pr, err := pijul.Open(repoPath, req.Channel)
 	if err != nil {
@@ -97,6 +122,11 @@ func (x *Xrpc) RepoApplyChanges(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Atomic {
+		x.applyChangesAtomic(w, pr, req)
+		return
+	}
+
 	// Apply each change in order
 	response := ApplyChangesResponse{
 		Applied: make([]string, 0),
@@ -127,3 +157,38 @@ func (x *Xrpc) RepoApplyChanges(w http.ResponseWriter, r *http.Request) {
 
 	writeJson(w, response)
 }
+
+// applyChangesAtomic applies the requested changes and their missing
+// dependencies in order, leaving the channel untouched if any of them fails
+func (x *Xrpc) applyChangesAtomic(w http.ResponseWriter, pr *pijul.PijulRepo, req ApplyChangesRequest) {
+	l := x.Logger.With("handler", "RepoApplyChanges", "channel", req.Channel)
+
+	applied, err := pr.ApplyChanges(req.Changes)
+	if err != nil {
+		l.Error("failed to apply changes", "error", err.Error())
+
+		if errors.Is(err, pijul.ErrChangeNotFound) {
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("ChangeNotFound"),
+				xrpcerr.WithMessage(err.Error()),
+			), http.StatusNotFound)
+			return
+		}
+
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("ApplyFailed"),
+			xrpcerr.WithMessage(err.Error()),
+		), http.StatusInternalServerError)
+		return
+	}
+
+	l.Info("applied changes", "changes", applied)
+
+	if conflicts, err := pr.Conflicts(); err == nil && len(conflicts) > 0 {
+		l.Warn("channel has unresolved conflicts after apply", "files", len(conflicts))
+	}
+
+	writeJson(w, ApplyChangesResponse{
+		Applied: append([]string{}, applied...),
+	})
+}
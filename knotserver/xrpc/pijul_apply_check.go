@@ -0,0 +1,102 @@
+package xrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/knotserver/pijul"
+	"tangled.org/core/rbac"
+	xrpcerr "tangled.org/core/xrpc/errors"
+)
+
+// RepoApplyCheck handles the sh.tangled.repo.applyCheck endpoint
+// Dry-runs an apply of Pijul changes and reports the conflicts it would leave
+func (x *Xrpc) RepoApplyCheck(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoApplyCheck")
+	fail := func(e xrpcerr.XrpcError) {
+		l.Error("failed", "kind", e.Tag, "error", e.Message)
+		writeError(w, e, http.StatusBadRequest)
+	}
+
+	var data tangled.RepoApplyCheck_Input
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		fail(xrpcerr.GenericError(err))
+		return
+	}
+
+	if data.Did == "" || data.Name == "" || data.Channel == "" || len(data.Changes) == 0 {
+		fail(xrpcerr.GenericError(fmt.Errorf("did, name, channel and changes are required")))
+		return
+	}
+
+	relativeRepoPath, err := securejoin.SecureJoin(data.Did, data.Name)
+	if err != nil {
+		fail(xrpcerr.GenericError(err))
+		return
+	}
+
+	// every check that misses the cache copies the repo, so only those who
+	// could apply the changes get to ask
+	actorDid, ok := r.Context().Value(ActorDid).(syntax.DID)
+	if !ok {
+		fail(xrpcerr.MissingActorDidError)
+		return
+	}
+
+	if ok, err := x.Enforcer.E.Enforce(actorDid.String(), rbac.ThisServer, relativeRepoPath, rbac.PijulApply); !ok || err != nil {
+		l.Error("insufficent permissions", "did", actorDid.String())
+		writeError(w, xrpcerr.AccessControlError(actorDid.String()), http.StatusUnauthorized)
+		return
+	}
+
+	repoPath, err := securejoin.SecureJoin(x.Config.Repo.ScanPath, relativeRepoPath)
+	if err != nil {
+		fail(xrpcerr.GenericError(err))
+		return
+	}
+
+	pr, err := pijul.Open(repoPath, data.Channel)
+	if err != nil {
+		fail(xrpcerr.GenericError(fmt.Errorf("failed to open repository: %w", err)))
+		return
+	}
+
+	response := tangled.RepoApplyCheck_Output{
+		Is_conflicted: false,
+	}
+
+	check, err := pr.CheckApply(data.Changes)
+	if err != nil {
+		response.Is_conflicted = true
+		errMsg := err.Error()
+		response.Error = &errMsg
+
+		var applyErr *pijul.ApplyError
+		if errors.As(err, &applyErr) {
+			msg := fmt.Sprintf("change %s does not apply cleanly", applyErr.Hash)
+			response.Message = &msg
+		}
+	} else {
+		response.Changes = check.Changes
+		if len(check.Conflicts) > 0 {
+			response.Is_conflicted = true
+			response.Conflicts = make([]*tangled.RepoApplyCheck_ConflictInfo, len(check.Conflicts))
+			for i, c := range check.Conflicts {
+				response.Conflicts[i] = &tangled.RepoApplyCheck_ConflictInfo{
+					Filename: c.Path,
+					Reason:   c.Reason(),
+					Changes:  c.Changes,
+				}
+			}
+		}
+	}
+
+	l.Debug("apply check response", "isConflicted", response.Is_conflicted, "err", response.Error, "conflicts", response.Conflicts)
+
+	writeJson(w, response)
+}
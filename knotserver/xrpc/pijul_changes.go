@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"tangled.org/core/api/tangled"
 	"tangled.org/core/knotserver/pijul"
 	xrpcerr "tangled.org/core/xrpc/errors"
 )
@@ -128,9 +129,13 @@ type PijulChangeGetResponse struct {
 	Hash         string        `json:"hash"`
 	Authors      []PijulAuthor `json:"authors"`
 	Message      string        `json:"message"`
+	Description  string        `json:"description,omitempty"`
 	Timestamp    string        `json:"timestamp,omitempty"`
 	Dependencies []string      `json:"dependencies,omitempty"`
 	Diff         string        `json:"diff,omitempty"`
+
+	Files []*tangled.RepoChangeGet_FileDiff `json:"files,omitempty"`
+	Stats *tangled.RepoChangeGet_DiffStats  `json:"stats,omitempty"`
 }
 
 // RepoChangeGet handles the sh.tangled.repo.changeGet endpoint
@@ -186,6 +191,7 @@ func (x *Xrpc) RepoChangeGet(w http.ResponseWriter, r *http.Request) {
 		Hash:         change.Hash,
 		Authors:      authors,
 		Message:      change.Message,
+		Description:  change.Description,
 		Dependencies: change.Dependencies,
 	}
 
@@ -195,7 +201,50 @@ func (x *Xrpc) RepoChangeGet(w http.ResponseWriter, r *http.Request) {
 
 	if diff != nil {
 		response.Diff = diff.Raw
+		response.Files, response.Stats = changeGetFiles(diff)
 	}
 
 	writeJson(w, response)
 }
+
+// changeGetFiles converts a parsed pijul diff into its lexicon form
+func changeGetFiles(diff *pijul.Diff) ([]*tangled.RepoChangeGet_FileDiff, *tangled.RepoChangeGet_DiffStats) {
+	files := make([]*tangled.RepoChangeGet_FileDiff, 0, len(diff.Files))
+	for _, f := range diff.Files {
+		file := &tangled.RepoChangeGet_FileDiff{
+			Path:      f.Path,
+			Status:    f.Status,
+			Additions: int64(f.Additions),
+			Deletions: int64(f.Deletions),
+		}
+		if f.OldPath != "" {
+			file.OldPath = &f.OldPath
+		}
+		for _, h := range f.Hunks {
+			hunk := &tangled.RepoChangeGet_Hunk{Kind: h.Kind}
+			if h.Line > 0 {
+				line := int64(h.Line)
+				hunk.Line = &line
+			}
+			for _, l := range h.Lines {
+				hunk.Lines = append(hunk.Lines, &tangled.RepoChangeGet_HunkLine{
+					Op:   l.Op,
+					Text: l.Text,
+				})
+			}
+			file.Hunks = append(file.Hunks, hunk)
+		}
+		files = append(files, file)
+	}
+
+	var stats *tangled.RepoChangeGet_DiffStats
+	if diff.Stats != nil {
+		stats = &tangled.RepoChangeGet_DiffStats{
+			FilesChanged: int64(diff.Stats.FilesChanged),
+			Additions:    int64(diff.Stats.Additions),
+			Deletions:    int64(diff.Stats.Deletions),
+		}
+	}
+
+	return files, stats
+}
@@ -1,10 +1,18 @@
 package xrpc
 
 import (
+	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"tangled.org/core/api/tangled"
 	"tangled.org/core/knotserver/pijul"
+	"tangled.org/core/rbac"
 	xrpcerr "tangled.org/core/xrpc/errors"
 )
 
@@ -17,6 +25,7 @@ type PijulChannelListResponse struct {
 type PijulChannel struct {
 	Name      string `json:"name"`
 	IsCurrent bool   `json:"is_current,omitempty"`
+	IsDefault bool   `json:"is_default,omitempty"`
 }
 
 // RepoChannelList handles the sh.tangled.repo.channelList endpoint
@@ -63,12 +72,23 @@ func (x *Xrpc) RepoChannelList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	defaultChannel, err := pr.FindDefaultChannel()
+	if err != nil {
+		x.Logger.Error("finding default channel", "error", err.Error())
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InternalServerError"),
+			xrpcerr.WithMessage("failed to find default channel"),
+		), http.StatusInternalServerError)
+		return
+	}
+
 	// Convert to response format
 	channelList := make([]PijulChannel, len(channels))
 	for i, ch := range channels {
 		channelList[i] = PijulChannel{
 			Name:      ch.Name,
 			IsCurrent: ch.IsCurrent,
+			IsDefault: ch.Name == defaultChannel,
 		}
 	}
 
@@ -118,3 +138,313 @@ func (x *Xrpc) RepoGetDefaultChannel(w http.ResponseWriter, r *http.Request) {
 
 	writeJson(w, response)
 }
+
+// RepoCreateChannel handles the sh.tangled.repo.createChannel endpoint
+// Creates an empty channel, or forks one from an existing channel
+func (x *Xrpc) RepoCreateChannel(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoCreateChannel")
+	fail := func(e xrpcerr.XrpcError, status int) {
+		l.Error("failed", "kind", e.Tag, "error", e.Message)
+		writeError(w, e, status)
+	}
+
+	actorDid, ok := r.Context().Value(ActorDid).(syntax.DID)
+	if !ok {
+		fail(xrpcerr.MissingActorDidError, http.StatusBadRequest)
+		return
+	}
+
+	var req tangled.RepoCreateChannel_Input
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("invalid request body"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	if req.Repo == "" {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("repo is required"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	if err := pijul.ValidateChannelName(req.Name); err != nil {
+		fail(invalidChannelError(err), http.StatusBadRequest)
+		return
+	}
+
+	pr, didSlashRepo, ok := x.openChannelsForEdit(w, l, actorDid, req.Repo)
+	if !ok {
+		return
+	}
+
+	if !requireChannel(w, pr, req.Name, false) {
+		return
+	}
+
+	var from string
+	if req.From != nil {
+		from = *req.From
+	}
+
+	var err error
+	if from != "" {
+		if !requireChannel(w, pr, from, true) {
+			return
+		}
+		err = pr.ForkChannel(req.Name, from)
+	} else {
+		err = pr.CreateChannel(req.Name)
+	}
+	if err != nil {
+		fail(xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	l.Info("created channel", "repo", didSlashRepo, "channel", req.Name, "from", from, "did", actorDid.String())
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// RepoRenameChannel handles the sh.tangled.repo.renameChannel endpoint
+func (x *Xrpc) RepoRenameChannel(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoRenameChannel")
+	fail := func(e xrpcerr.XrpcError, status int) {
+		l.Error("failed", "kind", e.Tag, "error", e.Message)
+		writeError(w, e, status)
+	}
+
+	actorDid, ok := r.Context().Value(ActorDid).(syntax.DID)
+	if !ok {
+		fail(xrpcerr.MissingActorDidError, http.StatusBadRequest)
+		return
+	}
+
+	var req tangled.RepoRenameChannel_Input
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("invalid request body"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	if req.Repo == "" || req.Name == "" {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("repo and name are required"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	if err := pijul.ValidateChannelName(req.NewName); err != nil {
+		fail(invalidChannelError(err), http.StatusBadRequest)
+		return
+	}
+
+	pr, didSlashRepo, ok := x.openChannelsForEdit(w, l, actorDid, req.Repo)
+	if !ok {
+		return
+	}
+
+	if !requireChannel(w, pr, req.Name, true) || !requireChannel(w, pr, req.NewName, false) {
+		return
+	}
+
+	if err := pr.RenameChannel(req.Name, req.NewName); err != nil {
+		fail(xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	l.Info("renamed channel", "repo", didSlashRepo, "channel", req.Name, "newName", req.NewName, "did", actorDid.String())
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// RepoDeleteChannel handles the sh.tangled.repo.deleteChannel endpoint
+// The default channel cannot be deleted
+func (x *Xrpc) RepoDeleteChannel(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoDeleteChannel")
+	fail := func(e xrpcerr.XrpcError, status int) {
+		l.Error("failed", "kind", e.Tag, "error", e.Message)
+		writeError(w, e, status)
+	}
+
+	actorDid, ok := r.Context().Value(ActorDid).(syntax.DID)
+	if !ok {
+		fail(xrpcerr.MissingActorDidError, http.StatusBadRequest)
+		return
+	}
+
+	var req tangled.RepoDeleteChannel_Input
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("invalid request body"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	if req.Repo == "" || req.Name == "" {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("repo and name are required"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	pr, didSlashRepo, ok := x.openChannelsForEdit(w, l, actorDid, req.Repo)
+	if !ok {
+		return
+	}
+
+	if !requireChannel(w, pr, req.Name, true) {
+		return
+	}
+
+	defaultChannel, err := pr.FindDefaultChannel()
+	if err != nil {
+		fail(xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+	if req.Name == defaultChannel {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("DefaultChannel"),
+			xrpcerr.WithMessage(pijul.ErrDefaultChannel.Error()),
+		), http.StatusBadRequest)
+		return
+	}
+
+	if err := pr.DeleteChannel(req.Name); err != nil {
+		fail(xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	l.Info("deleted channel", "repo", didSlashRepo, "channel", req.Name, "did", actorDid.String())
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// RepoSetDefaultChannel handles the sh.tangled.repo.setDefaultChannel endpoint
+func (x *Xrpc) RepoSetDefaultChannel(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoSetDefaultChannel")
+	fail := func(e xrpcerr.XrpcError, status int) {
+		l.Error("failed", "kind", e.Tag, "error", e.Message)
+		writeError(w, e, status)
+	}
+
+	actorDid, ok := r.Context().Value(ActorDid).(syntax.DID)
+	if !ok {
+		fail(xrpcerr.MissingActorDidError, http.StatusBadRequest)
+		return
+	}
+
+	var req tangled.RepoSetDefaultChannel_Input
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("invalid request body"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	if req.Repo == "" || req.Channel == "" {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("repo and channel are required"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	pr, didSlashRepo, ok := x.openChannelsForEdit(w, l, actorDid, req.Repo)
+	if !ok {
+		return
+	}
+
+	if err := pr.SetDefaultChannel(req.Channel); err != nil {
+		if errors.Is(err, pijul.ErrChannelNotFound) {
+			fail(xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("ChannelNotFound"),
+				xrpcerr.WithMessage("channel not found"),
+			), http.StatusNotFound)
+			return
+		}
+		fail(xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	l.Info("set default channel", "repo", didSlashRepo, "channel", req.Channel, "did", actorDid.String())
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// openChannelsForEdit checks that the actor may edit channels on the repo
+// and opens it, writing the error response on failure
+func (x *Xrpc) openChannelsForEdit(w http.ResponseWriter, l *slog.Logger, actorDid syntax.DID, repo string) (*pijul.PijulRepo, string, bool) {
+	repoPath, err := x.parseRepoParam(repo)
+	if err != nil {
+		writeError(w, err.(xrpcerr.XrpcError), http.StatusBadRequest)
+		return nil, "", false
+	}
+
+	repoParts := strings.SplitN(repo, "/", 2)
+	didSlashRepo, err := securejoin.SecureJoin(repoParts[0], repoParts[1])
+	if err != nil {
+		writeError(w, xrpcerr.InvalidRepoError(repo), http.StatusBadRequest)
+		return nil, "", false
+	}
+
+	if ok, err := x.Enforcer.E.Enforce(actorDid.String(), rbac.ThisServer, didSlashRepo, rbac.PijulEditChannels); !ok || err != nil {
+		l.Error("insufficent permissions", "did", actorDid.String())
+		writeError(w, xrpcerr.AccessControlError(actorDid.String()), http.StatusUnauthorized)
+		return nil, "", false
+	}
+
+	pr, err := pijul.PlainOpen(repoPath)
+	if err != nil {
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("RepoNotFound"),
+			xrpcerr.WithMessage("failed to open pijul repository"),
+		), http.StatusNotFound)
+		return nil, "", false
+	}
+
+	return pr, didSlashRepo, true
+}
+
+// requireChannel checks whether a channel exists, writing a ChannelNotFound
+// or ChannelExists response if it does not match want
+func requireChannel(w http.ResponseWriter, pr *pijul.PijulRepo, name string, want bool) bool {
+	exists, err := pr.ChannelExists(name)
+	if err != nil {
+		writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return false
+	}
+
+	switch {
+	case want && !exists:
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("ChannelNotFound"),
+			xrpcerr.WithMessage("channel not found: "+name),
+		), http.StatusNotFound)
+		return false
+	case !want && exists:
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("ChannelExists"),
+			xrpcerr.WithMessage(pijul.ErrChannelExists.Error()+": "+name),
+		), http.StatusConflict)
+		return false
+	}
+
+	return true
+}
+
+func invalidChannelError(err error) xrpcerr.XrpcError {
+	return xrpcerr.NewXrpcError(
+		xrpcerr.WithTag("InvalidRequest"),
+		xrpcerr.WithMessage(err.Error()),
+	)
+}
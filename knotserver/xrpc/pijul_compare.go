@@ -0,0 +1,123 @@
+package xrpc
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/knotserver/pijul"
+	xrpcerr "tangled.org/core/xrpc/errors"
+)
+
+// at most this many changes are listed on either side of a comparison
+const maxComparedChanges = 100
+
+// RepoCompareChannels handles the sh.tangled.repo.compareChannels endpoint
+// Lists the changes each of two channels lacks, and the combined diff that
+// the head channel would bring onto the base channel
+func (x *Xrpc) RepoCompareChannels(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoCompareChannels")
+
+	repo := r.URL.Query().Get("repo")
+	repoPath, err := x.parseRepoParam(repo)
+	if err != nil {
+		writeError(w, err.(xrpcerr.XrpcError), http.StatusBadRequest)
+		return
+	}
+
+	base := r.URL.Query().Get("base")
+	head := r.URL.Query().Get("head")
+	if base == "" || head == "" {
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("base and head are required"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	pr, err := pijul.PlainOpen(repoPath)
+	if err != nil {
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("RepoNotFound"),
+			xrpcerr.WithMessage("failed to open pijul repository"),
+		), http.StatusNotFound)
+		return
+	}
+
+	comparison, err := pr.CompareChannels(r.Context(), base, head)
+	if err != nil {
+		if errors.Is(err, pijul.ErrChannelNotFound) {
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("ChannelNotFound"),
+				xrpcerr.WithMessage(err.Error()),
+			), http.StatusNotFound)
+			return
+		}
+		l.Error("comparing channels", "error", err.Error(), "base", base, "head", head)
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("CompareFailed"),
+			xrpcerr.WithMessage("failed to compare channels"),
+		), http.StatusInternalServerError)
+		return
+	}
+
+	ahead, err := pijulChangeEntries(pr, comparison.Ahead)
+	if err != nil {
+		l.Error("loading changes", "error", err.Error())
+		writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+	behind, err := pijulChangeEntries(pr, comparison.Behind)
+	if err != nil {
+		l.Error("loading changes", "error", err.Error())
+		writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJson(w, tangled.RepoCompareChannels_Output{
+		Base:        base,
+		Head:        head,
+		Ahead:       ahead,
+		Behind:      behind,
+		AheadCount:  int64(len(comparison.Ahead)),
+		BehindCount: int64(len(comparison.Behind)),
+		Patch:       comparison.Patch,
+	})
+}
+
+// pijulChangeEntries loads the most recent of the given changes, keeping
+// their order
+func pijulChangeEntries(pr *pijul.PijulRepo, hashes []string) ([]*tangled.RepoChangeList_ChangeEntry, error) {
+	if len(hashes) > maxComparedChanges {
+		hashes = hashes[len(hashes)-maxComparedChanges:]
+	}
+
+	entries := make([]*tangled.RepoChangeList_ChangeEntry, len(hashes))
+	for i, h := range hashes {
+		c, err := pr.GetChange(h)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &tangled.RepoChangeList_ChangeEntry{
+			Hash:         c.Hash,
+			Message:      c.Message,
+			Dependencies: c.Dependencies,
+			Authors:      make([]*tangled.RepoChangeList_Author, len(c.Authors)),
+		}
+		for j, a := range c.Authors {
+			entry.Authors[j] = &tangled.RepoChangeList_Author{Name: a.Name}
+			if a.Email != "" {
+				entry.Authors[j].Email = &a.Email
+			}
+		}
+		if !c.Timestamp.IsZero() {
+			ts := c.Timestamp.Format(time.RFC3339)
+			entry.Timestamp = &ts
+		}
+		entries[i] = entry
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,76 @@
+package xrpc
+
+import (
+	"errors"
+	"net/http"
+
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/knotserver/pijul"
+	xrpcerr "tangled.org/core/xrpc/errors"
+)
+
+// RepoChannelConflicts handles the sh.tangled.repo.channelConflicts endpoint
+// Lists the conflicted files on a channel and the changes competing in them
+func (x *Xrpc) RepoChannelConflicts(w http.ResponseWriter, r *http.Request) {
+	repo := r.URL.Query().Get("repo")
+	repoPath, err := x.parseRepoParam(repo)
+	if err != nil {
+		writeError(w, err.(xrpcerr.XrpcError), http.StatusBadRequest)
+		return
+	}
+
+	channel := r.URL.Query().Get("channel")
+	if channel == "" {
+		pr, err := pijul.PlainOpen(repoPath)
+		if err != nil {
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("RepoNotFound"),
+				xrpcerr.WithMessage("failed to open pijul repository"),
+			), http.StatusNotFound)
+			return
+		}
+		if channel, err = pr.FindDefaultChannel(); err != nil {
+			writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	pr, err := pijul.Open(repoPath, channel)
+	if err != nil {
+		if errors.Is(err, pijul.ErrChannelNotFound) {
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("ChannelNotFound"),
+				xrpcerr.WithMessage("channel not found"),
+			), http.StatusNotFound)
+			return
+		}
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("RepoNotFound"),
+			xrpcerr.WithMessage("failed to open pijul repository"),
+		), http.StatusNotFound)
+		return
+	}
+
+	conflicts, err := pr.Conflicts()
+	if err != nil {
+		x.Logger.Error("finding conflicts", "error", err.Error(), "channel", channel)
+		writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	response := tangled.RepoChannelConflicts_Output{
+		Channel:   channel,
+		Conflicts: make([]*tangled.RepoChannelConflicts_Conflict, len(conflicts)),
+	}
+	for i, c := range conflicts {
+		reason := c.Reason()
+		response.Conflicts[i] = &tangled.RepoChannelConflicts_Conflict{
+			Path:    c.Path,
+			Regions: int64(c.Regions),
+			Reason:  &reason,
+			Changes: c.Changes,
+		}
+	}
+
+	writeJson(w, response)
+}
@@ -0,0 +1,114 @@
+package xrpc
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/knotserver/pijul"
+	xrpcerr "tangled.org/core/xrpc/errors"
+)
+
+// RepoPijulCredit handles the sh.tangled.repo.pijulCredit endpoint
+// Attributes each line of a file to the change that introduced it
+func (x *Xrpc) RepoPijulCredit(w http.ResponseWriter, r *http.Request) {
+	repo := r.URL.Query().Get("repo")
+	repoPath, err := x.parseRepoParam(repo)
+	if err != nil {
+		writeError(w, err.(xrpcerr.XrpcError), http.StatusBadRequest)
+		return
+	}
+
+	channel := r.URL.Query().Get("channel")
+	path := r.URL.Query().Get("path")
+
+	if path == "" {
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("missing path parameter"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	pr, err := pijul.Open(repoPath, channel)
+	if err != nil {
+		if errors.Is(err, pijul.ErrChannelNotFound) {
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("ChannelNotFound"),
+				xrpcerr.WithMessage("channel not found"),
+			), http.StatusNotFound)
+			return
+		}
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("RepoNotFound"),
+			xrpcerr.WithMessage("failed to open pijul repository"),
+		), http.StatusNotFound)
+		return
+	}
+
+	// credit only makes sense for text files recorded on the channel
+	const maxSize = 1024 * 1024 // 1MB
+	if _, err := pr.FileContentN(path, maxSize); err != nil {
+		if errors.Is(err, pijul.ErrBinaryFile) {
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("InvalidRequest"),
+				xrpcerr.WithMessage("credit is not available for binary files"),
+			), http.StatusBadRequest)
+			return
+		}
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("FileNotFound"),
+			xrpcerr.WithMessage("file not found on channel"),
+		), http.StatusNotFound)
+		return
+	}
+
+	lines, err := pr.Credit(path)
+	if err != nil {
+		x.Logger.Error("crediting file", "error", err.Error(), "path", path, "channel", channel)
+		writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	// look up each change once, files are usually made of few of them
+	changes := map[string]*pijul.Change{}
+	for _, l := range lines {
+		if _, ok := changes[l.Change]; ok || l.Change == "" {
+			continue
+		}
+		c, err := pr.GetChange(l.Change)
+		if err != nil {
+			x.Logger.Warn("failed to load change", "error", err, "change", l.Change)
+		}
+		changes[l.Change] = c
+	}
+
+	response := tangled.RepoPijulCredit_Output{
+		Path:  path,
+		Lines: make([]*tangled.RepoPijulCredit_Line, len(lines)),
+	}
+	if ch := pr.CurrentChannel(); ch != "" {
+		response.Channel = &ch
+	}
+
+	for i, l := range lines {
+		line := &tangled.RepoPijulCredit_Line{
+			Line:    int64(l.Line),
+			Content: l.Content,
+			Change:  l.Change,
+		}
+		if c := changes[l.Change]; c != nil {
+			if len(c.Authors) > 0 {
+				line.Author = &c.Authors[0].Name
+			}
+			if !c.Timestamp.IsZero() {
+				ts := c.Timestamp.Format(time.RFC3339)
+				line.Timestamp = &ts
+			}
+		}
+		response.Lines[i] = line
+	}
+
+	writeJson(w, response)
+}
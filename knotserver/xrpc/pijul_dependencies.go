@@ -0,0 +1,83 @@
+package xrpc
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/knotserver/pijul"
+	xrpcerr "tangled.org/core/xrpc/errors"
+)
+
+// RepoChangeDependencies handles the sh.tangled.repo.changeDependencies endpoint
+// Returns the transitive dependencies of a change and the changes depending on it
+func (x *Xrpc) RepoChangeDependencies(w http.ResponseWriter, r *http.Request) {
+	repo := r.URL.Query().Get("repo")
+	repoPath, err := x.parseRepoParam(repo)
+	if err != nil {
+		writeError(w, err.(xrpcerr.XrpcError), http.StatusBadRequest)
+		return
+	}
+
+	hash := r.URL.Query().Get("hash")
+	if hash == "" {
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("missing hash parameter"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	channel := r.URL.Query().Get("channel")
+
+	pr, err := pijul.Open(repoPath, channel)
+	if err != nil {
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("RepoNotFound"),
+			xrpcerr.WithMessage("failed to open pijul repository"),
+		), http.StatusNotFound)
+		return
+	}
+
+	graph, err := pr.DependencyGraph(hash)
+	if err != nil {
+		x.Logger.Error("building dependency graph", "error", err.Error(), "hash", hash)
+		if errors.Is(err, pijul.ErrChangeNotFound) {
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("ChangeNotFound"),
+				xrpcerr.WithMessage("change not found"),
+			), http.StatusNotFound)
+			return
+		}
+		writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	response := tangled.RepoChangeDependencies_Output{
+		Change:       dependencyNode(graph.Change),
+		Dependencies: make([]*tangled.RepoChangeDependencies_Node, 0, len(graph.Dependencies)),
+		Dependents:   make([]*tangled.RepoChangeDependencies_Node, 0, len(graph.Dependents)),
+	}
+	for i := range graph.Dependencies {
+		response.Dependencies = append(response.Dependencies, dependencyNode(&graph.Dependencies[i]))
+	}
+	for i := range graph.Dependents {
+		response.Dependents = append(response.Dependents, dependencyNode(&graph.Dependents[i]))
+	}
+
+	writeJson(w, response)
+}
+
+func dependencyNode(c *pijul.Change) *tangled.RepoChangeDependencies_Node {
+	node := &tangled.RepoChangeDependencies_Node{
+		Hash:         c.Hash,
+		Message:      c.Message,
+		Dependencies: c.Dependencies,
+	}
+	if !c.Timestamp.IsZero() {
+		ts := c.Timestamp.Format(time.RFC3339)
+		node.Timestamp = &ts
+	}
+	return node
+}
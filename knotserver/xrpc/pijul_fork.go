@@ -0,0 +1,207 @@
+package xrpc
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/knotserver/pijul"
+	"tangled.org/core/rbac"
+	xrpcerr "tangled.org/core/xrpc/errors"
+)
+
+// RepoPijulForkStatus handles the sh.tangled.repo.pijulForkStatus endpoint
+// Lists the changes that a fork channel and its upstream channel each lack
+func (x *Xrpc) RepoPijulForkStatus(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoPijulForkStatus")
+
+	repo := r.URL.Query().Get("repo")
+	pr, upstream, ok := x.openPijulFork(w, repo, r.URL.Query().Get("channel"))
+	if !ok {
+		return
+	}
+
+	channel := pr.CurrentChannel()
+	upstreamChannel := r.URL.Query().Get("upstreamChannel")
+	if upstreamChannel == "" {
+		upstreamChannel = channel
+	}
+
+	forkChanges, err := pr.ChangeHashes(channel)
+	if err != nil {
+		l.Error("listing fork changes", "error", err.Error(), "channel", channel)
+		writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	upstreamChanges, err := pijul.RemoteChangeHashes(r.Context(), upstream, upstreamChannel)
+	if err != nil {
+		l.Error("listing upstream changes", "error", err.Error(), "upstream", upstream, "channel", upstreamChannel)
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("UpstreamUnavailable"),
+			xrpcerr.WithMessage("failed to list upstream changes"),
+		), http.StatusBadGateway)
+		return
+	}
+
+	status := pijul.CompareFork(forkChanges, upstreamChanges)
+
+	writeJson(w, tangled.RepoPijulForkStatus_Output{
+		Upstream:        upstream,
+		Channel:         channel,
+		UpstreamChannel: upstreamChannel,
+		Ahead:           status.Ahead,
+		Behind:          status.Behind,
+	})
+}
+
+// RepoPijulForkSync handles the sh.tangled.repo.pijulForkSync endpoint
+// Pulls the changes of an upstream channel into a channel of the fork
+func (x *Xrpc) RepoPijulForkSync(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoPijulForkSync")
+
+	actorDid, ok := r.Context().Value(ActorDid).(syntax.DID)
+	if !ok {
+		writeError(w, xrpcerr.MissingActorDidError, http.StatusBadRequest)
+		return
+	}
+
+	var req tangled.RepoPijulForkSync_Input
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("invalid request body"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	if req.Repo == "" || req.Channel == "" {
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("repo and channel are required"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	repoParts := strings.SplitN(req.Repo, "/", 2)
+	if len(repoParts) != 2 {
+		writeError(w, xrpcerr.InvalidRepoError(req.Repo), http.StatusBadRequest)
+		return
+	}
+	didSlashRepo, err := securejoin.SecureJoin(repoParts[0], repoParts[1])
+	if err != nil {
+		writeError(w, xrpcerr.InvalidRepoError(req.Repo), http.StatusBadRequest)
+		return
+	}
+
+	if ok, err := x.Enforcer.E.Enforce(actorDid.String(), rbac.ThisServer, didSlashRepo, rbac.PijulApply); !ok || err != nil {
+		l.Error("insufficent permissions", "did", actorDid.String())
+		writeError(w, xrpcerr.AccessControlError(actorDid.String()), http.StatusUnauthorized)
+		return
+	}
+
+	pr, upstream, ok := x.openPijulFork(w, req.Repo, req.Channel)
+	if !ok {
+		return
+	}
+
+	upstreamChannel := req.Channel
+	if req.UpstreamChannel != nil && *req.UpstreamChannel != "" {
+		upstreamChannel = *req.UpstreamChannel
+	}
+
+	// snapshot the channels around the pull to find out what it changed, as
+	// for pushes
+	before, err := pr.Snapshot()
+	if err != nil {
+		l.Error("failed to snapshot pijul repo", "error", err)
+		// non-fatal
+	}
+
+	pulled, err := pr.PullAll(upstream, upstreamChannel)
+	if err != nil {
+		l.Error("pulling from upstream", "error", err.Error(), "upstream", upstream, "channel", req.Channel)
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("PullFailed"),
+			xrpcerr.WithMessage("failed to pull from upstream"),
+		), http.StatusInternalServerError)
+		return
+	}
+
+	l.Info("synced fork", "repo", didSlashRepo, "channel", req.Channel, "pulled", pulled)
+
+	if before != nil && len(pulled) > 0 {
+		after, err := pr.Snapshot()
+		if err != nil {
+			l.Error("failed to snapshot pijul repo", "error", err)
+		} else if lines := pijul.DiffSnapshots(before, after); len(lines) > 0 {
+			x.ProcessPijulPush(lines, repoParts[0], repoParts[1], actorDid.String())
+		}
+	}
+
+	writeJson(w, tangled.RepoPijulForkSync_Output{
+		Pulled: pulled,
+	})
+}
+
+// openPijulFork opens a channel of a pijul fork and looks up its upstream,
+// writing an error response on failure. The default channel is opened if
+// channel is empty.
+func (x *Xrpc) openPijulFork(w http.ResponseWriter, repo, channel string) (*pijul.PijulRepo, string, bool) {
+	repoPath, err := x.parseRepoParam(repo)
+	if err != nil {
+		writeError(w, err.(xrpcerr.XrpcError), http.StatusBadRequest)
+		return nil, "", false
+	}
+
+	if channel == "" {
+		pr, err := pijul.PlainOpen(repoPath)
+		if err != nil {
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("RepoNotFound"),
+				xrpcerr.WithMessage("failed to open pijul repository"),
+			), http.StatusNotFound)
+			return nil, "", false
+		}
+		if channel, err = pr.FindDefaultChannel(); err != nil {
+			writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+			return nil, "", false
+		}
+	}
+
+	pr, err := pijul.Open(repoPath, channel)
+	if err != nil {
+		if errors.Is(err, pijul.ErrChannelNotFound) {
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("ChannelNotFound"),
+				xrpcerr.WithMessage("channel not found"),
+			), http.StatusNotFound)
+			return nil, "", false
+		}
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("RepoNotFound"),
+			xrpcerr.WithMessage("failed to open pijul repository"),
+		), http.StatusNotFound)
+		return nil, "", false
+	}
+
+	upstream, err := x.Db.GetPijulForkUpstream(repo)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("NotAFork"),
+			xrpcerr.WithMessage("repository has no recorded upstream"),
+		), http.StatusNotFound)
+		return nil, "", false
+	}
+	if err != nil {
+		writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return nil, "", false
+	}
+
+	return pr, upstream, true
+}
@@ -0,0 +1,241 @@
+package xrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/knotserver/pijul"
+	"tangled.org/core/rbac"
+	xrpcerr "tangled.org/core/xrpc/errors"
+)
+
+// RepoPijulTags handles the sh.tangled.repo.pijulTags endpoint
+// Lists the tags of every channel, most recent first
+func (x *Xrpc) RepoPijulTags(w http.ResponseWriter, r *http.Request) {
+	repo := r.URL.Query().Get("repo")
+	repoPath, err := x.parseRepoParam(repo)
+	if err != nil {
+		writeError(w, err.(xrpcerr.XrpcError), http.StatusBadRequest)
+		return
+	}
+
+	pr, err := pijul.PlainOpen(repoPath)
+	if err != nil {
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("RepoNotFound"),
+			xrpcerr.WithMessage("failed to open pijul repository"),
+		), http.StatusNotFound)
+		return
+	}
+
+	tags, err := pr.Tags()
+	if err != nil {
+		x.Logger.Error("listing tags", "error", err.Error())
+		writeError(w, xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	response := tangled.RepoPijulTags_Output{
+		Tags: make([]*tangled.RepoPijulTags_Tag, len(tags)),
+	}
+	for i, t := range tags {
+		response.Tags[i] = pijulTagOutput(t)
+	}
+
+	writeJson(w, response)
+}
+
+// RepoCreatePijulTag handles the sh.tangled.repo.createPijulTag endpoint
+// Tags the current state of a channel
+func (x *Xrpc) RepoCreatePijulTag(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoCreatePijulTag")
+	fail := func(e xrpcerr.XrpcError, status int) {
+		l.Error("failed", "kind", e.Tag, "error", e.Message)
+		writeError(w, e, status)
+	}
+
+	actorDid, ok := r.Context().Value(ActorDid).(syntax.DID)
+	if !ok {
+		fail(xrpcerr.MissingActorDidError, http.StatusBadRequest)
+		return
+	}
+
+	var req tangled.RepoCreatePijulTag_Input
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("invalid request body"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	if req.Repo == "" || req.Channel == "" || strings.TrimSpace(req.Message) == "" {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("repo, channel, and message are required"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	repoPath, didSlashRepo, ok := x.authorizeEditTags(w, l, actorDid, req.Repo)
+	if !ok {
+		return
+	}
+
+	pr, err := pijul.Open(repoPath, req.Channel)
+	if err != nil {
+		if errors.Is(err, pijul.ErrChannelNotFound) {
+			fail(xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("ChannelNotFound"),
+				xrpcerr.WithMessage("channel not found"),
+			), http.StatusNotFound)
+			return
+		}
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("RepoNotFound"),
+			xrpcerr.WithMessage("failed to open pijul repository"),
+		), http.StatusNotFound)
+		return
+	}
+
+	tag, err := pr.CreateTag(req.Message, &pijul.Author{Name: actorDid.String()})
+	if err != nil {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("TagFailed"),
+			xrpcerr.WithMessage(err.Error()),
+		), http.StatusInternalServerError)
+		return
+	}
+
+	l.Info("created tag", "repo", didSlashRepo, "channel", req.Channel, "state", tag.State, "did", actorDid.String())
+
+	writeJson(w, pijulTagOutput(*tag))
+}
+
+// RepoDeletePijulTag handles the sh.tangled.repo.deletePijulTag endpoint
+// Deletes a tag from the channel it was created on
+func (x *Xrpc) RepoDeletePijulTag(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoDeletePijulTag")
+	fail := func(e xrpcerr.XrpcError, status int) {
+		l.Error("failed", "kind", e.Tag, "error", e.Message)
+		writeError(w, e, status)
+	}
+
+	actorDid, ok := r.Context().Value(ActorDid).(syntax.DID)
+	if !ok {
+		fail(xrpcerr.MissingActorDidError, http.StatusBadRequest)
+		return
+	}
+
+	var req tangled.RepoDeletePijulTag_Input
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("invalid request body"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	if req.Repo == "" || req.Tag == "" {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("repo and tag are required"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	repoPath, didSlashRepo, ok := x.authorizeEditTags(w, l, actorDid, req.Repo)
+	if !ok {
+		return
+	}
+
+	pr, err := pijul.PlainOpen(repoPath)
+	if err != nil {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("RepoNotFound"),
+			xrpcerr.WithMessage("failed to open pijul repository"),
+		), http.StatusNotFound)
+		return
+	}
+
+	tag, err := pr.FindTag(req.Tag)
+	if err != nil {
+		if errors.Is(err, pijul.ErrTagNotFound) {
+			fail(xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("TagNotFound"),
+				xrpcerr.WithMessage(err.Error()),
+			), http.StatusNotFound)
+			return
+		}
+		fail(xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	pr, err = pijul.Open(repoPath, tag.Channel)
+	if err != nil {
+		fail(xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := pr.DeleteTag(tag.State); err != nil {
+		fail(xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	l.Info("deleted tag", "repo", didSlashRepo, "channel", tag.Channel, "state", tag.State, "did", actorDid.String())
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// authorizeEditTags checks that the actor may edit tags on the repo, writing
+// the error response if not
+func (x *Xrpc) authorizeEditTags(w http.ResponseWriter, l *slog.Logger, actorDid syntax.DID, repo string) (string, string, bool) {
+	repoPath, err := x.parseRepoParam(repo)
+	if err != nil {
+		writeError(w, err.(xrpcerr.XrpcError), http.StatusBadRequest)
+		return "", "", false
+	}
+
+	repoParts := strings.SplitN(repo, "/", 2)
+	didSlashRepo, err := securejoin.SecureJoin(repoParts[0], repoParts[1])
+	if err != nil {
+		writeError(w, xrpcerr.InvalidRepoError(repo), http.StatusBadRequest)
+		return "", "", false
+	}
+
+	if ok, err := x.Enforcer.E.Enforce(actorDid.String(), rbac.ThisServer, didSlashRepo, rbac.PijulEditTags); !ok || err != nil {
+		l.Error("insufficent permissions", "did", actorDid.String())
+		writeError(w, xrpcerr.AccessControlError(actorDid.String()), http.StatusUnauthorized)
+		return "", "", false
+	}
+
+	return repoPath, didSlashRepo, true
+}
+
+func pijulTagOutput(t pijul.Tag) *tangled.RepoPijulTags_Tag {
+	out := &tangled.RepoPijulTags_Tag{
+		State:   t.State,
+		Name:    t.Name,
+		Message: t.Message,
+		Channel: t.Channel,
+		Authors: make([]*tangled.RepoChangeList_Author, len(t.Authors)),
+	}
+	for i, a := range t.Authors {
+		out.Authors[i] = &tangled.RepoChangeList_Author{Name: a.Name}
+		if a.Email != "" {
+			out.Authors[i].Email = &a.Email
+		}
+	}
+	if !t.Timestamp.IsZero() {
+		ts := t.Timestamp.Format(time.RFC3339)
+		out.Timestamp = &ts
+	}
+	return out
+}
@@ -36,6 +36,17 @@ func (x *Xrpc) RepoPijulTree(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if change := r.URL.Query().Get("change"); change != "" {
+		if err := pr.AtChange(change); err != nil {
+			x.Logger.Error("failed to resolve change", "error", err, "change", change, "channel", channel)
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("ChangeNotFound"),
+				xrpcerr.WithMessage("change not found on channel"),
+			), http.StatusNotFound)
+			return
+		}
+	}
+
 	files, err := pr.FileTree(ctx, path)
 	if err != nil {
 		x.Logger.Error("failed to get file tree", "error", err, "path", path)
@@ -134,6 +145,17 @@ func (x *Xrpc) RepoPijulBlob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if change := r.URL.Query().Get("change"); change != "" {
+		if err := pr.AtChange(change); err != nil {
+			x.Logger.Error("failed to resolve change", "error", err, "change", change, "channel", channel)
+			writeError(w, xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("ChangeNotFound"),
+				xrpcerr.WithMessage("change not found on channel"),
+			), http.StatusNotFound)
+			return
+		}
+	}
+
 	// Try to read as text first
 	const maxSize = 1024 * 1024 // 1MB
 	content, err := pr.FileContentN(path, maxSize)
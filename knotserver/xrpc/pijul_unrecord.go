@@ -0,0 +1,168 @@
+package xrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"tangled.org/core/api/tangled"
+	"tangled.org/core/knotserver/db"
+	"tangled.org/core/knotserver/pijul"
+	"tangled.org/core/rbac"
+	"tangled.org/core/tid"
+	xrpcerr "tangled.org/core/xrpc/errors"
+)
+
+// RepoUnrecordChanges handles the sh.tangled.repo.unrecordChanges endpoint
+// Removes changes from a channel, optionally along with their dependents
+func (x *Xrpc) RepoUnrecordChanges(w http.ResponseWriter, r *http.Request) {
+	l := x.Logger.With("handler", "RepoUnrecordChanges")
+	fail := func(e xrpcerr.XrpcError, status int) {
+		l.Error("failed", "kind", e.Tag, "error", e.Message)
+		writeError(w, e, status)
+	}
+
+	actorDid, ok := r.Context().Value(ActorDid).(syntax.DID)
+	if !ok {
+		fail(xrpcerr.MissingActorDidError, http.StatusBadRequest)
+		return
+	}
+
+	var req tangled.RepoUnrecordChanges_Input
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("invalid request body"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	if req.Repo == "" || req.Channel == "" || len(req.Changes) == 0 {
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("InvalidRequest"),
+			xrpcerr.WithMessage("repo, channel, and changes are required"),
+		), http.StatusBadRequest)
+		return
+	}
+
+	repoPath, err := x.parseRepoParam(req.Repo)
+	if err != nil {
+		fail(err.(xrpcerr.XrpcError), http.StatusBadRequest)
+		return
+	}
+
+	repoParts := strings.SplitN(req.Repo, "/", 2)
+	didSlashRepo, err := securejoin.SecureJoin(repoParts[0], repoParts[1])
+	if err != nil {
+		fail(xrpcerr.InvalidRepoError(req.Repo), http.StatusBadRequest)
+		return
+	}
+
+	if ok, err := x.Enforcer.E.Enforce(actorDid.String(), rbac.ThisServer, didSlashRepo, rbac.PijulApply); !ok || err != nil {
+		l.Error("insufficent permissions", "did", actorDid.String())
+		writeError(w, xrpcerr.AccessControlError(actorDid.String()), http.StatusUnauthorized)
+		return
+	}
+
+	pr, err := pijul.Open(repoPath, req.Channel)
+	if err != nil {
+		if errors.Is(err, pijul.ErrChannelNotFound) {
+			fail(xrpcerr.NewXrpcError(
+				xrpcerr.WithTag("ChannelNotFound"),
+				xrpcerr.WithMessage("channel not found"),
+			), http.StatusNotFound)
+			return
+		}
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("RepoNotFound"),
+			xrpcerr.WithMessage("failed to open pijul repository"),
+		), http.StatusNotFound)
+		return
+	}
+
+	oldState, err := pr.ChannelState(req.Channel)
+	if err != nil {
+		fail(xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	cascade := req.Cascade != nil && *req.Cascade
+	unrecorded, err := pr.UnrecordChanges(req.Changes, cascade)
+
+	var depErr *pijul.DependentsError
+	switch {
+	case errors.As(err, &depErr):
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("HasDependents"),
+			xrpcerr.WithMessage("changes on the channel still depend on this: "+strings.Join(depErr.Dependents, ", ")),
+		), http.StatusConflict)
+		return
+	case errors.Is(err, pijul.ErrChangeNotFound):
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("ChangeNotFound"),
+			xrpcerr.WithMessage(err.Error()),
+		), http.StatusNotFound)
+		return
+	case err != nil && len(unrecorded) == 0:
+		fail(xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("UnrecordFailed"),
+			xrpcerr.WithMessage(err.Error()),
+		), http.StatusInternalServerError)
+		return
+	case err != nil:
+		// some changes are already gone, the channel moved regardless
+		l.Error("partially unrecorded changes", "err", err, "unrecorded", unrecorded)
+	}
+
+	newState, err := pr.ChannelState(req.Channel)
+	if err != nil {
+		fail(xrpcerr.GenericError(err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := x.insertUnrecordEvent(didSlashRepo, req.Channel, oldState, newState, unrecorded); err != nil {
+		l.Error("failed to insert refUpdate event", "err", err)
+		// non-fatal
+	}
+
+	l.Info("unrecorded changes", "channel", req.Channel, "changes", unrecorded, "did", actorDid.String())
+
+	output := tangled.RepoUnrecordChanges_Output{
+		Unrecorded: unrecorded,
+	}
+	if newState != "" {
+		output.NewState = &newState
+	}
+
+	writeJson(w, output)
+}
+
+// insertUnrecordEvent emits a refUpdate for a channel that lost changes
+func (x *Xrpc) insertUnrecordEvent(didSlashRepo, channel, oldState, newState string, unrecorded []string) error {
+	refUpdate := tangled.PijulRefUpdate{
+		Repo:       didSlashRepo,
+		Channel:    channel,
+		NewState:   newState,
+		Changes:    []string{},
+		Unrecorded: unrecorded,
+	}
+	if oldState != "" {
+		refUpdate.OldState = &oldState
+	}
+
+	eventJson, err := json.Marshal(refUpdate)
+	if err != nil {
+		return err
+	}
+
+	event := db.Event{
+		Rkey:      tid.TID(),
+		Nsid:      tangled.PijulRefUpdateNSID,
+		EventJson: string(eventJson),
+	}
+
+	return x.Db.InsertEvent(event, x.Notifier)
+}
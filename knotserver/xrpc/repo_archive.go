@@ -2,6 +2,7 @@ package xrpc
 
 import (
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -11,6 +12,7 @@ import (
 
 	"tangled.org/core/api/tangled"
 	"tangled.org/core/knotserver/git"
+	"tangled.org/core/knotserver/pijul"
 	xrpcerr "tangled.org/core/xrpc/errors"
 )
 
@@ -40,15 +42,20 @@ func (x *Xrpc) RepoArchive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	repoParts := strings.Split(repo, "/")
+	repoName := repoParts[len(repoParts)-1]
+
+	if pijul.IsPijulRepo(repoPath) {
+		x.pijulRepoArchive(w, repo, repoPath, repoName, ref, prefix)
+		return
+	}
+
 	gr, err := git.Open(repoPath, ref)
 	if err != nil {
 		writeError(w, xrpcerr.RefNotFoundError, http.StatusNotFound)
 		return
 	}
 
-	repoParts := strings.Split(repo, "/")
-	repoName := repoParts[len(repoParts)-1]
-
 	immutableLink, err := x.buildImmutableLink(repo, format, gr.Hash().String(), prefix)
 	if err != nil {
 		x.Logger.Error(
@@ -95,6 +102,73 @@ func (x *Xrpc) RepoArchive(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// pijulRepoArchive serves repo.archive for pijul repositories, where ref is
+// either a channel or a tag
+//
+// channel states are only addressable through tags, so an immutable link is
+// only returned when archiving a tag
+func (x *Xrpc) pijulRepoArchive(w http.ResponseWriter, repo, repoPath, repoName, ref, prefix string) {
+	pr, err := pijul.Open(repoPath, ref)
+	if errors.Is(err, pijul.ErrChannelNotFound) {
+		var tag *pijul.Tag
+		pr, tag, err = pijulTagRepo(repoPath, ref)
+		if err == nil {
+			immutableLink, linkErr := x.buildImmutableLink(repo, "tar.gz", tag.State, prefix)
+			if linkErr != nil {
+				x.Logger.Error("failed to build immutable link", "err", linkErr.Error(), "repo", repo, "ref", ref)
+			} else {
+				w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"immutable\"", immutableLink))
+			}
+		}
+	}
+	if err != nil {
+		writeError(w, xrpcerr.RefNotFoundError, http.StatusNotFound)
+		return
+	}
+
+	safeRefFilename := strings.ReplaceAll(ref, "/", "-")
+	if safeRefFilename == "" {
+		safeRefFilename = "default"
+	}
+
+	archivePrefix := prefix
+	if archivePrefix == "" {
+		archivePrefix = fmt.Sprintf("%s-%s", repoName, safeRefFilename)
+	}
+
+	filename := fmt.Sprintf("%s-%s.tar.gz", repoName, safeRefFilename)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	w.Header().Set("Content-Type", "application/gzip")
+
+	gw := gzip.NewWriter(w)
+	defer gw.Close()
+
+	if err := pr.WriteTar(gw, archivePrefix); err != nil {
+		// once we start writing to the body we can't report error anymore
+		// so we are only left with logging the error
+		x.Logger.Error("writing tar file", "error", err.Error())
+		return
+	}
+
+	if err := gw.Flush(); err != nil {
+		x.Logger.Error("flushing", "error", err.Error())
+		return
+	}
+}
+
+// pijulTagRepo opens a pijul repository pinned to the state of a tag
+func pijulTagRepo(repoPath, ref string) (*pijul.PijulRepo, *pijul.Tag, error) {
+	pr, err := pijul.PlainOpen(repoPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	tag, err := pr.AtTag(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pr, tag, nil
+}
+
 func (x *Xrpc) buildImmutableLink(repo string, format string, ref string, prefix string) (string, error) {
 	scheme := "https"
 	if x.Config.Server.Dev {
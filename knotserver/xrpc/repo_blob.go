@@ -13,6 +13,7 @@ import (
 
 	"tangled.org/core/api/tangled"
 	"tangled.org/core/knotserver/git"
+	"tangled.org/core/knotserver/pijul"
 	xrpcerr "tangled.org/core/xrpc/errors"
 )
 
@@ -38,6 +39,11 @@ func (x *Xrpc) RepoBlob(w http.ResponseWriter, r *http.Request) {
 
 	raw := r.URL.Query().Get("raw") == "true"
 
+	if pijul.IsPijulRepo(repoPath) {
+		x.pijulRepoBlob(w, r, repoPath, ref, treePath, raw)
+		return
+	}
+
 	gr, err := git.Open(repoPath, ref)
 	if err != nil {
 		writeError(w, xrpcerr.RefNotFoundError, http.StatusNotFound)
@@ -72,6 +78,65 @@ func (x *Xrpc) RepoBlob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	response := x.blobResponse(w, r, ref, treePath, contents, raw)
+	if response == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	lastCommit, err := gr.LastCommitFile(ctx, treePath)
+	if err == nil && lastCommit != nil {
+		response.LastCommit = &tangled.RepoBlob_LastCommit{
+			Hash:    lastCommit.Hash.String(),
+			Message: lastCommit.Message,
+			When:    lastCommit.When.Format(time.RFC3339),
+		}
+
+		// try to get author information
+		commit, err := gr.Commit(lastCommit.Hash)
+		if err == nil {
+			response.LastCommit.Author = &tangled.RepoBlob_Signature{
+				Name:  commit.Author.Name,
+				Email: commit.Author.Email,
+			}
+		}
+	}
+
+	writeJson(w, response)
+}
+
+// pijulRepoBlob serves repo.blob for pijul repositories, where ref is the
+// channel to read from
+func (x *Xrpc) pijulRepoBlob(w http.ResponseWriter, r *http.Request, repoPath, ref, treePath string, raw bool) {
+	pr, err := pijul.Open(repoPath, ref)
+	if err != nil {
+		writeError(w, xrpcerr.RefNotFoundError, http.StatusNotFound)
+		return
+	}
+
+	contents, err := pr.RawContent(treePath)
+	if err != nil {
+		x.Logger.Error("file content", "error", err.Error(), "treePath", treePath)
+		writeError(w, xrpcerr.NewXrpcError(
+			xrpcerr.WithTag("FileNotFound"),
+			xrpcerr.WithMessage("file not found at the specified path"),
+		), http.StatusNotFound)
+		return
+	}
+
+	response := x.blobResponse(w, r, ref, treePath, contents, raw)
+	if response == nil {
+		return
+	}
+
+	writeJson(w, response)
+}
+
+// blobResponse builds the blob output for the given file contents. For raw
+// requests the contents are written out directly and nil is returned.
+func (x *Xrpc) blobResponse(w http.ResponseWriter, r *http.Request, ref, treePath string, contents []byte, raw bool) *tangled.RepoBlob_Output {
 	mimeType := http.DetectContentType(contents)
 
 	if filepath.Ext(treePath) == ".svg" {
@@ -86,7 +151,7 @@ func (x *Xrpc) RepoBlob(w http.ResponseWriter, r *http.Request) {
 		case strings.HasPrefix(mimeType, "image/"), strings.HasPrefix(mimeType, "video/"):
 			if clientETag := r.Header.Get("If-None-Match"); clientETag == eTag {
 				w.WriteHeader(http.StatusNotModified)
-				return
+				return nil
 			}
 			w.Header().Set("ETag", eTag)
 			w.Header().Set("Content-Type", mimeType)
@@ -107,10 +172,10 @@ func (x *Xrpc) RepoBlob(w http.ResponseWriter, r *http.Request) {
 				xrpcerr.WithTag("InvalidRequest"),
 				xrpcerr.WithMessage("only image, video, and text files can be accessed directly"),
 			), http.StatusForbidden)
-			return
+			return nil
 		}
 		w.Write(contents)
-		return
+		return nil
 	}
 
 	isTextual := func(mt string) bool {
@@ -131,7 +196,7 @@ func (x *Xrpc) RepoBlob(w http.ResponseWriter, r *http.Request) {
 		encoding = "utf-8"
 	}
 
-	response := tangled.RepoBlob_Output{
+	response := &tangled.RepoBlob_Output{
 		Ref:      ref,
 		Path:     treePath,
 		Content:  &content,
@@ -144,28 +209,7 @@ func (x *Xrpc) RepoBlob(w http.ResponseWriter, r *http.Request) {
 		response.MimeType = &mimeType
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
-	defer cancel()
-
-	lastCommit, err := gr.LastCommitFile(ctx, treePath)
-	if err == nil && lastCommit != nil {
-		response.LastCommit = &tangled.RepoBlob_LastCommit{
-			Hash:    lastCommit.Hash.String(),
-			Message: lastCommit.Message,
-			When:    lastCommit.When.Format(time.RFC3339),
-		}
-
-		// try to get author information
-		commit, err := gr.Commit(lastCommit.Hash)
-		if err == nil {
-			response.LastCommit.Author = &tangled.RepoBlob_Signature{
-				Name:  commit.Author.Name,
-				Email: commit.Author.Email,
-			}
-		}
-	}
-
-	writeJson(w, response)
+	return response
 }
 
 // isTextualMimeType returns true if the MIME type represents textual content
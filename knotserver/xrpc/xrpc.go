@@ -12,6 +12,8 @@ import (
 	"tangled.org/core/jetstream"
 	"tangled.org/core/knotserver/config"
 	"tangled.org/core/knotserver/db"
+	"tangled.org/core/knotserver/git"
+	"tangled.org/core/knotserver/pijul"
 	"tangled.org/core/notifier"
 	"tangled.org/core/rbac"
 	xrpcerr "tangled.org/core/xrpc/errors"
@@ -29,6 +31,14 @@ type Xrpc struct {
 	Notifier    *notifier.Notifier
 	Resolver    *idresolver.Resolver
 	ServiceAuth *serviceauth.ServiceAuth
+
+	// ProcessGitPush records the refs that an update made through the knot
+	// without a push moved, and runs their pipelines, as for a push
+	ProcessGitPush func(lines []git.PostReceiveLine, repoDid, repoName, pusherDid string) []string
+
+	// ProcessPijulPush records the channels that an update made through the
+	// knot moved, and runs their conflict checks and pipelines, as for a push
+	ProcessPijulPush func(lines []pijul.PostPushLine, repoDid, repoName, pusherDid string) []string
 }
 
 func (x *Xrpc) Router() http.Handler {
@@ -39,6 +49,8 @@ func (x *Xrpc) Router() http.Handler {
 
 		r.Post("/"+tangled.RepoSetDefaultBranchNSID, x.SetDefaultBranch)
 		r.Post("/"+tangled.RepoDeleteBranchNSID, x.DeleteBranch)
+		r.Post("/"+tangled.RepoSetBranchProtectionNSID, x.RepoSetBranchProtection)
+		r.Post("/"+tangled.RepoDeleteBranchProtectionNSID, x.RepoDeleteBranchProtection)
 		r.Post("/"+tangled.RepoCreateNSID, x.CreateRepo)
 		r.Post("/"+tangled.RepoDeleteNSID, x.DeleteRepo)
 		r.Post("/"+tangled.RepoForkStatusNSID, x.ForkStatus)
@@ -46,10 +58,19 @@ func (x *Xrpc) Router() http.Handler {
 		r.Post("/"+tangled.RepoHiddenRefNSID, x.HiddenRef)
 		r.Post("/"+tangled.RepoMergeNSID, x.Merge)
 		r.Post("/"+tangled.RepoApplyChangesNSID, x.RepoApplyChanges)
+		r.Post("/"+tangled.RepoApplyCheckNSID, x.RepoApplyCheck)
+		r.Post("/"+tangled.RepoUnrecordChangesNSID, x.RepoUnrecordChanges)
+		r.Post("/"+tangled.RepoCreatePijulTagNSID, x.RepoCreatePijulTag)
+		r.Post("/"+tangled.RepoDeletePijulTagNSID, x.RepoDeletePijulTag)
+		r.Post("/"+tangled.RepoPijulForkSyncNSID, x.RepoPijulForkSync)
+		r.Post("/"+tangled.RepoCreateChannelNSID, x.RepoCreateChannel)
+		r.Post("/"+tangled.RepoRenameChannelNSID, x.RepoRenameChannel)
+		r.Post("/"+tangled.RepoDeleteChannelNSID, x.RepoDeleteChannel)
+		r.Post("/"+tangled.RepoSetDefaultChannelNSID, x.RepoSetDefaultChannel)
 		r.Get("/"+tangled.RepoPermissionsNSID, x.RepoPermissions)
 	})
 
-	// merge check is an open endpoint
+	// merge checks are open endpoints
 	//
 	// TODO: should we constrain this more?
 	// - we can calculate on PR submit/resubmit/gitRefUpdate etc.
@@ -66,15 +87,22 @@ func (x *Xrpc) Router() http.Handler {
 	r.Get("/"+tangled.RepoPijulBlobNSID, x.RepoPijulBlob)
 	r.Get("/"+tangled.RepoDiffNSID, x.RepoDiff)
 	r.Get("/"+tangled.RepoCompareNSID, x.RepoCompare)
+	r.Get("/"+tangled.RepoCompareChannelsNSID, x.RepoCompareChannels)
 	r.Get("/"+tangled.RepoGetDefaultBranchNSID, x.RepoGetDefaultBranch)
 	r.Get("/"+tangled.RepoGetDefaultChannelNSID, x.RepoGetDefaultChannel)
 	r.Get("/"+tangled.RepoBranchNSID, x.RepoBranch)
+	r.Get("/"+tangled.RepoBranchProtectionsNSID, x.RepoBranchProtections)
 	r.Get("/"+tangled.RepoArchiveNSID, x.RepoArchive)
 	r.Get("/"+tangled.RepoLanguagesNSID, x.RepoLanguages)
 	r.Get("/"+tangled.RepoChannelListNSID, x.RepoChannelList)
+	r.Get("/"+tangled.RepoPijulForkStatusNSID, x.RepoPijulForkStatus)
 	r.Get("/"+tangled.RepoChangeListNSID, x.RepoChangeList)
 	r.Get("/"+tangled.RepoChangeGetNSID, x.RepoChangeGet)
+	r.Get("/"+tangled.RepoChangeDependenciesNSID, x.RepoChangeDependencies)
+	r.Get("/"+tangled.RepoChannelConflictsNSID, x.RepoChannelConflicts)
 	r.Get("/"+tangled.RepoPijulTreeNSID, x.RepoPijulTree)
+	r.Get("/"+tangled.RepoPijulTagsNSID, x.RepoPijulTags)
+	r.Get("/"+tangled.RepoPijulCreditNSID, x.RepoPijulCredit)
 
 	// knot query endpoints (no auth required)
 	r.Get("/"+tangled.KnotListKeysNSID, x.ListKeys)
@@ -130,6 +130,82 @@ func (s *InterdiffFile) String() string {
 	return b.String()
 }
 
+// MapLines maps a range of lines of the file, as revised by the first
+// patch, to the same lines of the file as revised by the second patch. ok is
+// false if any of the lines were changed between the two patches, or if the
+// patches could not be compared.
+func (s *InterdiffFile) MapLines(start, end int64) (int64, int64, bool) {
+	switch {
+	case s.Status.IsUnchanged():
+		return start, end, true
+	case !s.Status.IsOk() || s.File == nil:
+		return 0, 0, false
+	}
+
+	newStart, ok := mapLine(s.TextFragments, start)
+	if !ok {
+		return 0, 0, false
+	}
+	newEnd, ok := mapLine(s.TextFragments, end)
+	if !ok {
+		return 0, 0, false
+	}
+
+	// lines added or deleted within the range change it as well
+	if newEnd-newStart != end-start {
+		return 0, 0, false
+	}
+
+	return newStart, newEnd, true
+}
+
+// mapLine maps a line of the old side of a diff to the new side, and fails
+// if the line is deleted
+func mapLine(fragments []*gitdiff.TextFragment, line int64) (int64, bool) {
+	var offset int64
+
+	for _, f := range fragments {
+		if f.OldLines == 0 {
+			// pure insertions come after the line at OldPosition
+			if f.OldPosition < line {
+				offset += f.NewLines
+				continue
+			}
+			break
+		}
+
+		if line >= f.OldPosition+f.OldLines {
+			offset += f.NewLines - f.OldLines
+			continue
+		}
+		if line < f.OldPosition {
+			break
+		}
+
+		oldPos, newPos := f.OldPosition, f.NewPosition
+		for _, l := range f.Lines {
+			switch l.Op {
+			case gitdiff.OpContext:
+				if oldPos == line {
+					return newPos, true
+				}
+				oldPos++
+				newPos++
+			case gitdiff.OpDelete:
+				if oldPos == line {
+					return 0, false
+				}
+				oldPos++
+			case gitdiff.OpAdd:
+				newPos++
+			}
+		}
+		return 0, false
+	}
+
+	return line + offset, true
+}
+
 type InterdiffFileStatus struct {
 	StatusKind StatusKind
 	Error      error
@@ -265,6 +341,12 @@ func interdiffFiles(f1, f2 *gitdiff.File) *InterdiffFile {
 }
 
 func Interdiff(patch1, patch2 []*gitdiff.File) *InterdiffResult {
+	return interdiff(patch1, patch2, interdiffFiles)
+}
+
+// interdiff pairs up the files of both patches by name, and compares each
+// pair with interdiffFile
+func interdiff(patch1, patch2 []*gitdiff.File, interdiffFile func(f1, f2 *gitdiff.File) *InterdiffFile) *InterdiffResult {
 	fileToIdx1 := make(map[string]int)
 	fileToIdx2 := make(map[string]int)
 	visited := make(map[string]struct{})
@@ -279,20 +361,20 @@ func Interdiff(patch1, patch2 []*gitdiff.File) *InterdiffResult {
 	}
 
 	for _, f1 := range patch1 {
-		var interdiffFile *InterdiffFile
+		var file *InterdiffFile
 
 		fileName := bestName(f1)
 		if idx, ok := fileToIdx2[fileName]; ok {
 			f2 := patch2[idx]
 
 			// we have f1 and f2, calculate interdiff
-			interdiffFile = interdiffFiles(f1, f2)
+			file = interdiffFile(f1, f2)
 		} else {
 			// only in patch 1, this change would have to be "inverted" to dissapear
 			// from patch 2, so we reverseDiff(f1)
 			reverseDiff(f1)
 
-			interdiffFile = &InterdiffFile{
+			file = &InterdiffFile{
 				File: f1,
 				Name: fileName,
 				Status: InterdiffFileStatus{
@@ -301,7 +383,7 @@ func Interdiff(patch1, patch2 []*gitdiff.File) *InterdiffResult {
 			}
 		}
 
-		result.Files = append(result.Files, interdiffFile)
+		result.Files = append(result.Files, file)
 		visited[fileName] = struct{}{}
 	}
 
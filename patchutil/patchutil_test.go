@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
 	"tangled.org/core/types"
 )
 
@@ -326,6 +327,67 @@ content
 	}
 }
 
+func TestInterdiffMapLines(t *testing.T) {
+	ctx := func(l string) gitdiff.Line { return gitdiff.Line{Op: gitdiff.OpContext, Line: l} }
+	del := func(l string) gitdiff.Line { return gitdiff.Line{Op: gitdiff.OpDelete, Line: l} }
+	add := func(l string) gitdiff.Line { return gitdiff.Line{Op: gitdiff.OpAdd, Line: l} }
+
+	// line 5 is replaced by two lines, and a line is inserted after line 20
+	file := &InterdiffFile{
+		File: &gitdiff.File{
+			TextFragments: []*gitdiff.TextFragment{
+				{
+					OldPosition: 4, OldLines: 3,
+					NewPosition: 4, NewLines: 4,
+					Lines: []gitdiff.Line{ctx("4"), del("5"), add("5a"), add("5b"), ctx("6")},
+				},
+				{
+					OldPosition: 20, OldLines: 0,
+					NewPosition: 22, NewLines: 1,
+					Lines: []gitdiff.Line{add("20a")},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		start, end int64
+		wantStart  int64
+		wantEnd    int64
+		wantOk     bool
+	}{
+		{"before changes", 1, 3, 1, 3, true},
+		{"context of a hunk", 4, 4, 4, 4, true},
+		{"deleted line", 5, 5, 0, 0, false},
+		{"range over a deletion", 3, 6, 0, 0, false},
+		{"after a hunk", 6, 10, 7, 11, true},
+		{"up to an insertion", 19, 20, 20, 21, true},
+		{"after an insertion", 21, 22, 23, 24, true},
+		{"range over an insertion", 20, 21, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := file.MapLines(tt.start, tt.end)
+			if ok != tt.wantOk || start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("MapLines(%d, %d) = %d, %d, %v, want %d, %d, %v",
+					tt.start, tt.end, start, end, ok, tt.wantStart, tt.wantEnd, tt.wantOk)
+			}
+		})
+	}
+
+	unchanged := &InterdiffFile{Status: InterdiffFileStatus{StatusKind: StatusUnchanged}}
+	if start, end, ok := unchanged.MapLines(3, 4); !ok || start != 3 || end != 4 {
+		t.Errorf("unchanged file: MapLines(3, 4) = %d, %d, %v", start, end, ok)
+	}
+
+	rebased := &InterdiffFile{Status: InterdiffFileStatus{StatusKind: StatusRebased}}
+	if _, _, ok := rebased.MapLines(3, 4); ok {
+		t.Errorf("rebased file: MapLines should fail")
+	}
+}
+
 func TestImplsInterfaces(t *testing.T) {
 	id := &InterdiffResult{}
 	_ = isDiffsRenderer(id)
@@ -0,0 +1,137 @@
+package patchutil
+
+import (
+	"strings"
+
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"tangled.org/core/api/tangled"
+)
+
+// FromPijulChange converts the files of a pijul change into git diff files.
+// Pijul only reports where a hunk lands in the new file, so old line
+// numbers are derived from the hunks before it. Hunks carry no context
+// lines.
+func FromPijulChange(change *tangled.RepoChangeGet_Output) []*gitdiff.File {
+	var files []*gitdiff.File
+	for _, f := range change.Files {
+		file := &gitdiff.File{}
+		switch f.Status {
+		case "added":
+			file.IsNew = true
+			file.NewName = f.Path
+		case "deleted":
+			file.IsDelete = true
+			file.OldName = f.Path
+		case "renamed":
+			file.IsRename = true
+			file.NewName = f.Path
+			file.OldName = f.Path
+			if f.OldPath != nil {
+				file.OldName = *f.OldPath
+			}
+		default:
+			file.OldName = f.Path
+			file.NewName = f.Path
+		}
+
+		var offset int64
+		for _, h := range f.Hunks {
+			if len(h.Lines) == 0 {
+				continue
+			}
+
+			frag := &gitdiff.TextFragment{}
+			for _, l := range h.Lines {
+				switch l.Op {
+				case "+":
+					frag.LinesAdded++
+					frag.Lines = append(frag.Lines, gitdiff.Line{Op: gitdiff.OpAdd, Line: l.Text + "\n"})
+				case "-":
+					frag.LinesDeleted++
+					frag.Lines = append(frag.Lines, gitdiff.Line{Op: gitdiff.OpDelete, Line: l.Text + "\n"})
+				}
+			}
+			frag.NewLines = frag.LinesAdded
+			frag.OldLines = frag.LinesDeleted
+
+			switch {
+			case file.IsNew:
+				frag.NewPosition = 1
+			case file.IsDelete:
+				frag.OldPosition = 1
+			default:
+				frag.NewPosition = 1
+				if h.Line != nil {
+					frag.NewPosition = *h.Line
+				}
+				frag.OldPosition = max(frag.NewPosition-offset, 1)
+			}
+			offset += frag.LinesAdded - frag.LinesDeleted
+
+			file.TextFragments = append(file.TextFragments, frag)
+		}
+
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// PijulInterdiff compares two pijul changes file by file.
+//
+// Pijul hunks have no context lines, so the original file cannot be
+// rebuilt the way Interdiff does for git patches. Instead, the lines of the
+// hunks of both changes are diffed against each other. Where the hunks land
+// is left out, so a change rebased over unrelated edits to the same file
+// shows no difference.
+func PijulInterdiff(change1, change2 []*gitdiff.File) *InterdiffResult {
+	return interdiff(change1, change2, interdiffHunks)
+}
+
+func interdiffHunks(f1, f2 *gitdiff.File) *InterdiffFile {
+	interdiffFile := InterdiffFile{
+		Name: bestName(f1),
+	}
+
+	diff, err := Unified(hunksText(f1), bestName(f1), hunksText(f2), bestName(f2))
+	if err != nil {
+		interdiffFile.Status = InterdiffFileStatus{
+			StatusKind: StatusError,
+			Error:      err,
+		}
+		return &interdiffFile
+	}
+
+	parsed, _, err := gitdiff.Parse(strings.NewReader(diff))
+	if err != nil {
+		interdiffFile.Status = InterdiffFileStatus{
+			StatusKind: StatusError,
+			Error:      err,
+		}
+		return &interdiffFile
+	}
+
+	if len(parsed) != 1 {
+		// hunks are identical
+		interdiffFile.Status = InterdiffFileStatus{
+			StatusKind: StatusUnchanged,
+		}
+		return &interdiffFile
+	}
+
+	interdiffFile.File = parsed[0]
+	return &interdiffFile
+}
+
+// hunksText renders the lines of the fragments of a file the way they
+// appear in a unified diff, without the fragment headers and the positions
+// they hold
+func hunksText(file *gitdiff.File) string {
+	var b strings.Builder
+	for _, fragment := range file.TextFragments {
+		for _, line := range fragment.Lines {
+			b.WriteString(line.String())
+		}
+	}
+	return b.String()
+}
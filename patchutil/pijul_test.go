@@ -0,0 +1,174 @@
+package patchutil
+
+import (
+	"testing"
+
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"tangled.org/core/api/tangled"
+)
+
+func pijulHunk(line int64, lines ...string) *tangled.RepoChangeGet_Hunk {
+	h := &tangled.RepoChangeGet_Hunk{Kind: "edit", Line: &line}
+	for _, l := range lines {
+		h.Lines = append(h.Lines, &tangled.RepoChangeGet_HunkLine{Op: l[:1], Text: l[1:]})
+	}
+	return h
+}
+
+func TestFromPijulChange(t *testing.T) {
+	oldPath := "old.txt"
+
+	type fragment struct {
+		oldPosition, oldLines int64
+		newPosition, newLines int64
+	}
+
+	tests := []struct {
+		name      string
+		file      *tangled.RepoChangeGet_FileDiff
+		oldName   string
+		newName   string
+		isNew     bool
+		isDelete  bool
+		isRename  bool
+		fragments []fragment
+	}{
+		{
+			name: "added file",
+			file: &tangled.RepoChangeGet_FileDiff{
+				Path: "new.txt", Status: "added",
+				Hunks: []*tangled.RepoChangeGet_Hunk{pijulHunk(1, "+a", "+b")},
+			},
+			newName:   "new.txt",
+			isNew:     true,
+			fragments: []fragment{{0, 0, 1, 2}},
+		},
+		{
+			name: "deleted file",
+			file: &tangled.RepoChangeGet_FileDiff{
+				Path: "gone.txt", Status: "deleted",
+				Hunks: []*tangled.RepoChangeGet_Hunk{pijulHunk(1, "-a")},
+			},
+			oldName:   "gone.txt",
+			isDelete:  true,
+			fragments: []fragment{{1, 1, 0, 0}},
+		},
+		{
+			name: "renamed file",
+			file: &tangled.RepoChangeGet_FileDiff{
+				Path: "new.txt", Status: "renamed", OldPath: &oldPath,
+			},
+			oldName:  "old.txt",
+			newName:  "new.txt",
+			isRename: true,
+		},
+		{
+			name: "old positions follow the hunks before",
+			file: &tangled.RepoChangeGet_FileDiff{
+				Path: "main.go", Status: "modified",
+				Hunks: []*tangled.RepoChangeGet_Hunk{
+					pijulHunk(3, "+x", "+y"),
+					pijulHunk(10, "-z", "+w"),
+					pijulHunk(20),
+				},
+			},
+			oldName: "main.go",
+			newName: "main.go",
+			fragments: []fragment{
+				{3, 0, 3, 2},
+				{8, 1, 10, 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := FromPijulChange(&tangled.RepoChangeGet_Output{
+				Files: []*tangled.RepoChangeGet_FileDiff{tt.file},
+			})
+			if len(files) != 1 {
+				t.Fatalf("expected 1 file, got %d", len(files))
+			}
+
+			f := files[0]
+			if f.OldName != tt.oldName || f.NewName != tt.newName {
+				t.Errorf("names = %q, %q, want %q, %q", f.OldName, f.NewName, tt.oldName, tt.newName)
+			}
+			if f.IsNew != tt.isNew || f.IsDelete != tt.isDelete || f.IsRename != tt.isRename {
+				t.Errorf("new, delete, rename = %v, %v, %v, want %v, %v, %v",
+					f.IsNew, f.IsDelete, f.IsRename, tt.isNew, tt.isDelete, tt.isRename)
+			}
+
+			if len(f.TextFragments) != len(tt.fragments) {
+				t.Fatalf("expected %d fragments, got %d", len(tt.fragments), len(f.TextFragments))
+			}
+			for i, want := range tt.fragments {
+				frag := f.TextFragments[i]
+				got := fragment{frag.OldPosition, frag.OldLines, frag.NewPosition, frag.NewLines}
+				if got != want {
+					t.Errorf("fragment %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPijulInterdiff(t *testing.T) {
+	change := func(files ...*tangled.RepoChangeGet_FileDiff) []*gitdiff.File {
+		return FromPijulChange(&tangled.RepoChangeGet_Output{Files: files})
+	}
+	modified := func(path string, hunks ...*tangled.RepoChangeGet_Hunk) *tangled.RepoChangeGet_FileDiff {
+		return &tangled.RepoChangeGet_FileDiff{Path: path, Status: "modified", Hunks: hunks}
+	}
+
+	tests := []struct {
+		name    string
+		change1 []*gitdiff.File
+		change2 []*gitdiff.File
+		want    map[string]StatusKind
+	}{
+		{
+			name:    "same hunks",
+			change1: change(modified("a.txt", pijulHunk(3, "-old", "+new"))),
+			change2: change(modified("a.txt", pijulHunk(3, "-old", "+new"))),
+			want:    map[string]StatusKind{"a.txt": StatusUnchanged},
+		},
+		{
+			name:    "same hunks at other lines",
+			change1: change(modified("a.txt", pijulHunk(3, "-old", "+new"))),
+			change2: change(modified("a.txt", pijulHunk(12, "-old", "+new"))),
+			want:    map[string]StatusKind{"a.txt": StatusUnchanged},
+		},
+		{
+			name:    "different hunks",
+			change1: change(modified("a.txt", pijulHunk(3, "-old", "+new"))),
+			change2: change(modified("a.txt", pijulHunk(3, "-old", "+newer"))),
+			want:    map[string]StatusKind{"a.txt": StatusOk},
+		},
+		{
+			name:    "files only in one change",
+			change1: change(modified("a.txt", pijulHunk(1, "+a"))),
+			change2: change(modified("b.txt", pijulHunk(1, "+b"))),
+			want:    map[string]StatusKind{"a.txt": StatusOnlyInOne, "b.txt": StatusOnlyInTwo},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := PijulInterdiff(tt.change1, tt.change2)
+
+			got := map[string]StatusKind{}
+			for _, f := range result.Files {
+				got[f.Name] = f.Status.StatusKind
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got files %v, want %v", got, tt.want)
+			}
+			for name, kind := range tt.want {
+				if got[name] != kind {
+					t.Errorf("%s: status %s, want %s", name, got[name], kind)
+				}
+			}
+		})
+	}
+}
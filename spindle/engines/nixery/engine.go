@@ -103,6 +103,13 @@ func (e *Engine) InitWorkflow(twf tangled.Pipeline_Workflow, tpl tangled.Pipelin
 	}
 	swf.Name = twf.Name
 	swf.Environment = dwf.Environment
+	// pijul repos are cloned with pijul
+	if tpl.TriggerMetadata != nil && models.IsPijulTrigger(*tpl.TriggerMetadata) {
+		if dwf.Dependencies == nil {
+			dwf.Dependencies = make(map[string][]string)
+		}
+		dwf.Dependencies["nixpkgs"] = append(dwf.Dependencies["nixpkgs"], "pijul")
+	}
 	addl.image = workflowImage(dwf.Dependencies, e.cfg.NixeryPipelines.Nixery)
 
 	setup := &setupSteps{}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/go-git/go-git/v5/plumbing"
 	"tangled.org/core/api/tangled"
 	"tangled.org/core/workflow"
 )
@@ -30,7 +31,8 @@ func (s CloneStep) Kind() StepKind {
 	return s.kind
 }
 
-// BuildCloneStep generates git clone commands.
+// BuildCloneStep generates git clone commands, or pijul clone commands for
+// pijul repos (see buildPijulCloneStep).
 // The caller must ensure the current working directory is set to the desired
 // workspace directory before executing these commands.
 //
@@ -40,12 +42,16 @@ func (s CloneStep) Kind() StepKind {
 // - git fetch --depth=<d> --recurse-submodules=<yes|no> <sha>
 // - git checkout FETCH_HEAD
 //
-// Supports all trigger types (push, PR, manual) and clone options.
+// Supports all trigger types (push, PR, discussion, manual) and clone options.
 func BuildCloneStep(twf tangled.Pipeline_Workflow, tr tangled.Pipeline_TriggerMetadata, dev bool) CloneStep {
 	if twf.Clone != nil && twf.Clone.Skip {
 		return CloneStep{}
 	}
 
+	if IsPijulTrigger(tr) {
+		return buildPijulCloneStep(tr, dev)
+	}
+
 	commitSHA, err := extractCommitSHA(tr)
 	if err != nil {
 		return CloneStep{
@@ -75,6 +81,103 @@ func BuildCloneStep(twf tangled.Pipeline_Workflow, tr tangled.Pipeline_TriggerMe
 	}
 }
 
+// IsPijulTrigger reports whether the pipeline was triggered on a pijul repo.
+// Discussions only exist on pijul repos.
+func IsPijulTrigger(tr tangled.Pipeline_TriggerMetadata) bool {
+	if workflow.TriggerKind(tr.Kind) == workflow.TriggerKindDiscussion {
+		return true
+	}
+	return tr.Repo != nil && tr.Repo.Vcs != nil && *tr.Repo.Vcs == "pijul"
+}
+
+// pijul clones into a new directory, this is moved into the workspace
+const pijulCloneDir = ".tangled-pijul-clone"
+
+// buildPijulCloneStep generates pijul clone commands.
+//
+// The generated commands are:
+// - pijul clone --channel <channel> [--state <state>] <url> <tmp>
+// - mv <tmp>/* .
+// - pijul pull <url> <hash>... (discussions only)
+//
+// Pushes are pinned to the state of the channel after the push. Discussions
+// clone the target channel and pull their active patches on top of it;
+// pulling a change also pulls the changes it depends on.
+func buildPijulCloneStep(tr tangled.Pipeline_TriggerMetadata, dev bool) CloneStep {
+	channel, state, err := extractChannelState(tr)
+	if err != nil {
+		return CloneStep{
+			kind:     StepKindSystem,
+			name:     "Clone repository into workspace (error)",
+			commands: []string{fmt.Sprintf("echo 'Failed to get clone info: %s' && exit 1", err.Error())},
+		}
+	}
+
+	repoURL := BuildRepoURL(tr.Repo, dev)
+
+	var cloneArgs []string
+	if channel != "" {
+		cloneArgs = append(cloneArgs, "--channel", shellQuote(channel))
+	}
+	if state != "" {
+		cloneArgs = append(cloneArgs, "--state", shellQuote(state))
+	}
+	cloneArgs = append(cloneArgs, repoURL, pijulCloneDir)
+
+	commands := []string{
+		fmt.Sprintf("pijul clone %s", strings.Join(cloneArgs, " ")),
+		fmt.Sprintf("shopt -s dotglob && mv %s/* . && rmdir %s", pijulCloneDir, pijulCloneDir),
+	}
+
+	if tr.Discussion != nil && len(tr.Discussion.Patches) > 0 {
+		hashes := make([]string, len(tr.Discussion.Patches))
+		for i, h := range tr.Discussion.Patches {
+			hashes[i] = shellQuote(h)
+		}
+		commands = append(commands, fmt.Sprintf("pijul pull %s %s", repoURL, strings.Join(hashes, " ")))
+	}
+
+	return CloneStep{
+		kind:     StepKindSystem,
+		name:     "Clone repository into workspace",
+		commands: commands,
+	}
+}
+
+// extractChannelState extracts the pijul channel to clone, and the state to
+// pin it to, from trigger metadata based on trigger type
+func extractChannelState(tr tangled.Pipeline_TriggerMetadata) (string, string, error) {
+	switch workflow.TriggerKind(tr.Kind) {
+	case workflow.TriggerKindPush:
+		if tr.Push == nil {
+			return "", "", fmt.Errorf("push trigger metadata is nil")
+		}
+		// channels are pushed as branch refs
+		return plumbing.ReferenceName(tr.Push.Ref).Short(), tr.Push.NewSha, nil
+
+	case workflow.TriggerKindDiscussion:
+		if tr.Discussion == nil {
+			return "", "", fmt.Errorf("discussion trigger metadata is nil")
+		}
+		return tr.Discussion.TargetChannel, "", nil
+
+	case workflow.TriggerKindManual:
+		// clone the latest state of the default channel
+		if tr.Repo != nil {
+			return tr.Repo.DefaultBranch, "", nil
+		}
+		return "", "", nil
+
+	default:
+		return "", "", fmt.Errorf("unsupported trigger kind for pijul repos: %s", tr.Kind)
+	}
+}
+
+// shellQuote quotes s for use as a single shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // extractCommitSHA extracts the commit SHA from trigger metadata based on trigger type
 func extractCommitSHA(tr tangled.Pipeline_TriggerMetadata) (string, error) {
 	switch workflow.TriggerKind(tr.Kind) {
@@ -97,6 +97,148 @@ func TestBuildCloneStep_PullRequestTrigger(t *testing.T) {
 	}
 }
 
+func TestBuildCloneStep_DiscussionTrigger(t *testing.T) {
+	twf := tangled.Pipeline_Workflow{
+		Clone: &tangled.Pipeline_CloneOpts{
+			Depth: 1,
+			Skip:  false,
+		},
+	}
+	tr := tangled.Pipeline_TriggerMetadata{
+		Kind: string(workflow.TriggerKindDiscussion),
+		Discussion: &tangled.Pipeline_DiscussionTriggerData{
+			Discussion:    "at://did:plc:user123/sh.tangled.repo.discussion/abc",
+			TargetChannel: "main",
+			PatchHash:     "HASH2",
+			Patches:       []string{"HASH1", "HASH2"},
+			Action:        "create",
+		},
+		Repo: &tangled.Pipeline_TriggerRepo{
+			Knot: "example.com",
+			Did:  "did:plc:user123",
+			Repo: "my-repo",
+		},
+	}
+
+	step := BuildCloneStep(twf, tr, false)
+
+	commands := step.Commands()
+	if len(commands) != 3 {
+		t.Fatalf("Expected 3 commands, got %d", len(commands))
+	}
+	if commands[0] != "pijul clone --channel 'main' https://example.com/did:plc:user123/my-repo .tangled-pijul-clone" {
+		t.Errorf("Unexpected clone: '%s'", commands[0])
+	}
+	if commands[2] != "pijul pull https://example.com/did:plc:user123/my-repo 'HASH1' 'HASH2'" {
+		t.Errorf("Unexpected patch pull: '%s'", commands[2])
+	}
+}
+
+func TestBuildCloneStep_PijulPushTrigger(t *testing.T) {
+	vcs := "pijul"
+	twf := tangled.Pipeline_Workflow{
+		Clone: &tangled.Pipeline_CloneOpts{
+			Depth: 1,
+		},
+	}
+	tr := tangled.Pipeline_TriggerMetadata{
+		Kind: string(workflow.TriggerKindPush),
+		Push: &tangled.Pipeline_PushTriggerData{
+			NewSha: "NEWSTATE",
+			OldSha: "OLDSTATE",
+			Ref:    "refs/heads/dev",
+		},
+		Repo: &tangled.Pipeline_TriggerRepo{
+			Knot: "example.com",
+			Did:  "did:plc:user123",
+			Repo: "my-repo",
+			Vcs:  &vcs,
+		},
+	}
+
+	step := BuildCloneStep(twf, tr, false)
+
+	commands := step.Commands()
+	if len(commands) != 2 {
+		t.Fatalf("Expected 2 commands, got %d", len(commands))
+	}
+	if commands[0] != "pijul clone --channel 'dev' --state 'NEWSTATE' https://example.com/did:plc:user123/my-repo .tangled-pijul-clone" {
+		t.Errorf("Unexpected clone: '%s'", commands[0])
+	}
+	if strings.Contains(step.Command(), "git ") {
+		t.Error("Pijul repos should not be cloned with git")
+	}
+}
+
+func TestBuildCloneStep_PijulManualTrigger(t *testing.T) {
+	vcs := "pijul"
+	twf := tangled.Pipeline_Workflow{}
+	tr := tangled.Pipeline_TriggerMetadata{
+		Kind:   string(workflow.TriggerKindManual),
+		Manual: &tangled.Pipeline_ManualTriggerData{},
+		Repo: &tangled.Pipeline_TriggerRepo{
+			Knot:          "example.com",
+			Did:           "did:plc:user123",
+			Repo:          "my-repo",
+			DefaultBranch: "main",
+			Vcs:           &vcs,
+		},
+	}
+
+	step := BuildCloneStep(twf, tr, false)
+
+	commands := step.Commands()
+	if commands[0] != "pijul clone --channel 'main' https://example.com/did:plc:user123/my-repo .tangled-pijul-clone" {
+		t.Errorf("Unexpected clone: '%s'", commands[0])
+	}
+}
+
+func TestBuildCloneStep_PijulSkipFlag(t *testing.T) {
+	vcs := "pijul"
+	twf := tangled.Pipeline_Workflow{
+		Clone: &tangled.Pipeline_CloneOpts{
+			Skip: true,
+		},
+	}
+	tr := tangled.Pipeline_TriggerMetadata{
+		Kind: string(workflow.TriggerKindPush),
+		Push: &tangled.Pipeline_PushTriggerData{
+			NewSha: "NEWSTATE",
+			Ref:    "refs/heads/main",
+		},
+		Repo: &tangled.Pipeline_TriggerRepo{
+			Knot: "example.com",
+			Did:  "did:plc:user123",
+			Repo: "my-repo",
+			Vcs:  &vcs,
+		},
+	}
+
+	step := BuildCloneStep(twf, tr, false)
+
+	if len(step.Commands()) != 0 {
+		t.Errorf("Expected no commands when clone is skipped, got %d", len(step.Commands()))
+	}
+}
+
+func TestBuildCloneStep_NilDiscussionData(t *testing.T) {
+	twf := tangled.Pipeline_Workflow{}
+	tr := tangled.Pipeline_TriggerMetadata{
+		Kind: string(workflow.TriggerKindDiscussion),
+		Repo: &tangled.Pipeline_TriggerRepo{
+			Knot: "example.com",
+			Did:  "did:plc:user123",
+			Repo: "my-repo",
+		},
+	}
+
+	step := BuildCloneStep(twf, tr, false)
+
+	if step.Name() != "Clone repository into workspace (error)" {
+		t.Errorf("Expected error step, got '%s'", step.Name())
+	}
+}
+
 func TestBuildCloneStep_ManualTrigger(t *testing.T) {
 	twf := tangled.Pipeline_Workflow{
 		Clone: &tangled.Pipeline_CloneOpts{
@@ -29,6 +29,12 @@ func PipelineEnvVars(tr *tangled.Pipeline_TriggerMetadata, pipelineId PipelineId
 		env["TANGLED_REPO_NAME"] = tr.Repo.Repo
 		env["TANGLED_REPO_DEFAULT_BRANCH"] = tr.Repo.DefaultBranch
 		env["TANGLED_REPO_URL"] = BuildRepoURL(tr.Repo, devMode)
+
+		vcs := "git"
+		if IsPijulTrigger(*tr) {
+			vcs = "pijul"
+		}
+		env["TANGLED_REPO_VCS"] = vcs
 	}
 
 	switch workflow.TriggerKind(tr.Kind) {
@@ -63,6 +69,21 @@ func PipelineEnvVars(tr *tangled.Pipeline_TriggerMetadata, pipelineId PipelineId
 			env["TANGLED_PR_ACTION"] = tr.PullRequest.Action
 		}
 
+	case workflow.TriggerKindDiscussion:
+		if tr.Discussion != nil {
+			// For discussions, the "ref" is the target channel. TANGLED_REF
+			// is left unset, pijul repos have no git refs.
+			env["TANGLED_REF_NAME"] = tr.Discussion.TargetChannel
+			env["TANGLED_REF_TYPE"] = "channel"
+
+			// Discussion-specific variables
+			env["TANGLED_DISCUSSION"] = tr.Discussion.Discussion
+			env["TANGLED_DISCUSSION_TARGET_CHANNEL"] = tr.Discussion.TargetChannel
+			env["TANGLED_DISCUSSION_PATCH_HASH"] = tr.Discussion.PatchHash
+			env["TANGLED_DISCUSSION_PATCHES"] = strings.Join(tr.Discussion.Patches, " ")
+			env["TANGLED_DISCUSSION_ACTION"] = tr.Discussion.Action
+		}
+
 	case workflow.TriggerKindManual:
 		// Manual triggers may not have ref/sha info
 		// Include any manual inputs if present
@@ -73,5 +94,20 @@ func PipelineEnvVars(tr *tangled.Pipeline_TriggerMetadata, pipelineId PipelineId
 		}
 	}
 
+	// Pijul-specific variables, pushes to a channel are reported as branch
+	// pushes above
+	if IsPijulTrigger(*tr) {
+		channel, state, err := extractChannelState(*tr)
+		if err == nil {
+			env["TANGLED_PIJUL_CHANNEL"] = channel
+			if state != "" {
+				env["TANGLED_PIJUL_STATE"] = state
+			}
+			if tr.Push != nil {
+				env["TANGLED_REF_TYPE"] = "channel"
+			}
+		}
+	}
+
 	return env
 }
@@ -156,6 +156,84 @@ func TestPipelineEnvVars_PullRequest(t *testing.T) {
 	}
 }
 
+func TestPipelineEnvVars_Discussion(t *testing.T) {
+	tr := &tangled.Pipeline_TriggerMetadata{
+		Kind: string(workflow.TriggerKindDiscussion),
+		Discussion: &tangled.Pipeline_DiscussionTriggerData{
+			Discussion:    "at://did:plc:user123/sh.tangled.repo.discussion/abc",
+			TargetChannel: "main",
+			PatchHash:     "HASH2",
+			Patches:       []string{"HASH1", "HASH2"},
+			Action:        "create",
+		},
+		Repo: &tangled.Pipeline_TriggerRepo{
+			Knot: "example.com",
+			Did:  "did:plc:user123",
+			Repo: "my-repo",
+		},
+	}
+	id := PipelineId{
+		Knot: "example.com",
+		Rkey: "123123",
+	}
+	env := PipelineEnvVars(tr, id, false)
+
+	if env["TANGLED_REF_NAME"] != "main" {
+		t.Errorf("Expected TANGLED_REF_NAME='main', got '%s'", env["TANGLED_REF_NAME"])
+	}
+	if env["TANGLED_REF_TYPE"] != "channel" {
+		t.Errorf("Expected TANGLED_REF_TYPE='channel', got '%s'", env["TANGLED_REF_TYPE"])
+	}
+	if env["TANGLED_DISCUSSION_PATCH_HASH"] != "HASH2" {
+		t.Errorf("Expected TANGLED_DISCUSSION_PATCH_HASH='HASH2', got '%s'", env["TANGLED_DISCUSSION_PATCH_HASH"])
+	}
+	if env["TANGLED_DISCUSSION_PATCHES"] != "HASH1 HASH2" {
+		t.Errorf("Expected TANGLED_DISCUSSION_PATCHES='HASH1 HASH2', got '%s'", env["TANGLED_DISCUSSION_PATCHES"])
+	}
+	if _, ok := env["TANGLED_SHA"]; ok {
+		t.Error("TANGLED_SHA should not be set for discussions")
+	}
+	if _, ok := env["TANGLED_REF"]; ok {
+		t.Error("TANGLED_REF should not be set for discussions")
+	}
+}
+
+func TestPipelineEnvVars_PijulPush(t *testing.T) {
+	vcs := "pijul"
+	tr := &tangled.Pipeline_TriggerMetadata{
+		Kind: string(workflow.TriggerKindPush),
+		Push: &tangled.Pipeline_PushTriggerData{
+			Ref:    "refs/heads/main",
+			NewSha: "NEWSTATE",
+			OldSha: "OLDSTATE",
+		},
+		Repo: &tangled.Pipeline_TriggerRepo{
+			Knot: "example.com",
+			Did:  "did:plc:user123",
+			Repo: "my-repo",
+			Vcs:  &vcs,
+		},
+	}
+	id := PipelineId{
+		Knot: "example.com",
+		Rkey: "123123",
+	}
+	env := PipelineEnvVars(tr, id, false)
+
+	if env["TANGLED_REPO_VCS"] != "pijul" {
+		t.Errorf("Expected TANGLED_REPO_VCS='pijul', got '%s'", env["TANGLED_REPO_VCS"])
+	}
+	if env["TANGLED_REF_TYPE"] != "channel" {
+		t.Errorf("Expected TANGLED_REF_TYPE='channel', got '%s'", env["TANGLED_REF_TYPE"])
+	}
+	if env["TANGLED_PIJUL_CHANNEL"] != "main" {
+		t.Errorf("Expected TANGLED_PIJUL_CHANNEL='main', got '%s'", env["TANGLED_PIJUL_CHANNEL"])
+	}
+	if env["TANGLED_PIJUL_STATE"] != "NEWSTATE" {
+		t.Errorf("Expected TANGLED_PIJUL_STATE='NEWSTATE', got '%s'", env["TANGLED_PIJUL_STATE"])
+	}
+}
+
 func TestPipelineEnvVars_ManualWithInputs(t *testing.T) {
 	tr := &tangled.Pipeline_TriggerMetadata{
 		Kind: string(workflow.TriggerKindManual),
@@ -0,0 +1,143 @@
+package types
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// BranchProtection restricts how the branches of a repo matching Pattern can
+// be updated. Rules are stored on the knot hosting the repo, which enforces
+// them on every push and merge.
+type BranchProtection struct {
+	Pattern string
+
+	// reject updates that rewrite the history of the branch
+	NoForcePush bool
+
+	// reject deleting the branch
+	NoDeletion bool
+
+	// the branch can only be updated through sh.tangled.repo.merge, which
+	// the appview calls to merge pulls. The knot does not track pulls, so it
+	// cannot tell whether a merge names an open one: collaborators calling
+	// the endpoint themselves get past this rule.
+	RequirePull bool
+
+	// dids that can update the branch; if empty, everyone with push access
+	// to the repo can
+	AllowedPushers []string
+}
+
+// MatchBranch reports whether a branch pattern matches a branch. Patterns
+// use the syntax of path.Match, so "release/*" matches "release/v1".
+func MatchBranch(pattern, branch string) bool {
+	ok, _ := path.Match(pattern, branch)
+	return ok
+}
+
+// ValidateBranchPattern checks that a pattern can be passed to MatchBranch
+func ValidateBranchPattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("branch pattern cannot be empty")
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid branch pattern: %s", pattern)
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to a branch
+func (p BranchProtection) Matches(branch string) bool {
+	return MatchBranch(p.Pattern, branch)
+}
+
+func (p BranchProtection) Validate() error {
+	if err := ValidateBranchPattern(p.Pattern); err != nil {
+		return err
+	}
+	for _, did := range p.AllowedPushers {
+		if !strings.HasPrefix(did, "did:") {
+			return fmt.Errorf("allowed pushers must be dids: %s", did)
+		}
+	}
+	if !p.NoForcePush && !p.NoDeletion && !p.RequirePull && len(p.AllowedPushers) == 0 {
+		return fmt.Errorf("the rule for %s does not protect anything", p.Pattern)
+	}
+	return nil
+}
+
+// IsAllowedPusher reports whether the rule lets a user update the branches
+// it matches
+func (p BranchProtection) IsAllowedPusher(did string) bool {
+	return len(p.AllowedPushers) == 0 || slices.Contains(p.AllowedPushers, did)
+}
+
+// BranchUpdate is a change to a branch, checked against the protection rules
+// of its repo before it is accepted
+type BranchUpdate struct {
+	Branch string
+
+	// did of the user updating the branch
+	Pusher string
+
+	Create bool
+	Delete bool
+
+	// the new tip of the branch does not descend from the old one
+	Force bool
+
+	// the update lands a pull merged by the pusher
+	Merge bool
+}
+
+// ProtectionError is returned when a protection rule rejects an update
+type ProtectionError struct {
+	Branch  string
+	Pattern string
+	Reason  string
+}
+
+func (e *ProtectionError) Error() string {
+	if e.Pattern == e.Branch {
+		return fmt.Sprintf("branch %s is protected: %s", e.Branch, e.Reason)
+	}
+	return fmt.Sprintf("branch %s is protected by %s: %s", e.Branch, e.Pattern, e.Reason)
+}
+
+// CheckBranchUpdate checks an update against every rule matching its branch,
+// returning a *ProtectionError for the first rule that rejects it
+func CheckBranchUpdate(rules []BranchProtection, u BranchUpdate) error {
+	for _, p := range rules {
+		if !p.Matches(u.Branch) {
+			continue
+		}
+		if reason := p.rejects(u); reason != "" {
+			return &ProtectionError{
+				Branch:  u.Branch,
+				Pattern: p.Pattern,
+				Reason:  reason,
+			}
+		}
+	}
+	return nil
+}
+
+// rejects explains why the rule rejects an update, or returns an empty
+// string if it allows it
+func (p BranchProtection) rejects(u BranchUpdate) string {
+	switch {
+	case !p.IsAllowedPusher(u.Pusher):
+		return fmt.Sprintf("%s is not allowed to update it", u.Pusher)
+	case u.Delete && p.NoDeletion:
+		return "it cannot be deleted"
+	case u.Force && p.NoForcePush:
+		return "force-pushes are not allowed"
+	// creating or deleting a branch does not change any commits on it, so
+	// there is no pull to merge
+	case p.RequirePull && !u.Merge && !u.Create && !u.Delete:
+		return "changes must be merged through a pull"
+	}
+	return ""
+}
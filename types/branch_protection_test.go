@@ -0,0 +1,113 @@
+package types
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckBranchUpdate(t *testing.T) {
+	rules := []BranchProtection{
+		{Pattern: "main", NoForcePush: true, NoDeletion: true, RequirePull: true},
+		{Pattern: "release/*", NoDeletion: true, AllowedPushers: []string{"did:plc:maintainer"}},
+	}
+
+	tests := []struct {
+		name   string
+		update BranchUpdate
+		reason string
+	}{
+		{
+			name:   "merge into main",
+			update: BranchUpdate{Branch: "main", Pusher: "did:plc:alice", Merge: true},
+		},
+		{
+			name:   "direct push to main",
+			update: BranchUpdate{Branch: "main", Pusher: "did:plc:alice"},
+			reason: "changes must be merged through a pull",
+		},
+		{
+			name:   "force-push to main",
+			update: BranchUpdate{Branch: "main", Pusher: "did:plc:alice", Force: true},
+			reason: "force-pushes are not allowed",
+		},
+		{
+			name:   "delete main",
+			update: BranchUpdate{Branch: "main", Pusher: "did:plc:alice", Delete: true},
+			reason: "it cannot be deleted",
+		},
+		{
+			name:   "force-push to a release by its maintainer",
+			update: BranchUpdate{Branch: "release/v1", Pusher: "did:plc:maintainer", Force: true},
+		},
+		{
+			name:   "create a release",
+			update: BranchUpdate{Branch: "release/v2", Pusher: "did:plc:alice", Create: true},
+			reason: "did:plc:alice is not allowed to update it",
+		},
+		{
+			name:   "unprotected branch",
+			update: BranchUpdate{Branch: "feature", Pusher: "did:plc:alice", Force: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckBranchUpdate(rules, tt.update)
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("expected update to be allowed, got %v", err)
+				}
+				return
+			}
+
+			var protectionErr *ProtectionError
+			if !errors.As(err, &protectionErr) {
+				t.Fatalf("expected a protection error, got %v", err)
+			}
+			if protectionErr.Reason != tt.reason {
+				t.Errorf("reason = %q, want %q", protectionErr.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestBranchProtectionValidate(t *testing.T) {
+	tests := []struct {
+		rule  BranchProtection
+		valid bool
+	}{
+		{BranchProtection{Pattern: "main", NoForcePush: true}, true},
+		{BranchProtection{Pattern: "release/*", AllowedPushers: []string{"did:plc:foo"}}, true},
+		{BranchProtection{Pattern: "", NoForcePush: true}, false},
+		{BranchProtection{Pattern: "[", NoForcePush: true}, false},
+		{BranchProtection{Pattern: "main", AllowedPushers: []string{"alice.tngl.sh"}}, false},
+		{BranchProtection{Pattern: "main"}, false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.rule.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate(%+v) = %v, want valid = %v", tt.rule, err, tt.valid)
+		}
+	}
+}
+
+func TestMatchBranch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		branch  string
+		want    bool
+	}{
+		{"main", "main", true},
+		{"main", "mainline", false},
+		{"release/*", "release/v1", true},
+		{"release/*", "release/v1/fix", false},
+		{"*", "feature/x", false},
+		{"[", "[", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchBranch(tt.pattern, tt.branch); got != tt.want {
+			t.Errorf("MatchBranch(%q, %q) = %v, want %v", tt.pattern, tt.branch, got, tt.want)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package types
 
+import "slices"
+
 type ConflictInfo struct {
-	Filename string `json:"filename"`
-	Reason   string `json:"reason"`
+	Filename string   `json:"filename"`
+	Reason   string   `json:"reason"`
+	Changes  []string `json:"changes,omitempty"`
 }
 
 type MergeCheckResponse struct {
@@ -20,3 +23,50 @@ type MergeRequest struct {
 	CommitMessage string `json:"commitMessage,omitempty"`
 	Branch        string `json:"branch"`
 }
+
+// MergeStrategy is how the commits of a pull land on its target branch
+type MergeStrategy string
+
+const (
+	// MergeStrategyMerge applies the commits on a side branch and joins it
+	// to the target with a merge commit
+	MergeStrategyMerge MergeStrategy = "merge"
+
+	// MergeStrategySquash combines all commits into a single commit
+	MergeStrategySquash MergeStrategy = "squash"
+
+	// MergeStrategyRebase replays every commit on top of the target
+	MergeStrategyRebase MergeStrategy = "rebase"
+
+	// MergeStrategyFastForward moves the target to the tip of the source
+	// branch, creating no commit. Only pulls from a branch of the same repo
+	// can be fast-forwarded, as the knot needs the commits, and only while
+	// the target has not moved past the point the branch started from.
+	MergeStrategyFastForward MergeStrategy = "fastForward"
+)
+
+// MergeStrategies lists every merge strategy, in the order they are offered
+var MergeStrategies = []MergeStrategy{
+	MergeStrategyMerge,
+	MergeStrategySquash,
+	MergeStrategyRebase,
+	MergeStrategyFastForward,
+}
+
+func (s MergeStrategy) IsValid() bool {
+	return slices.Contains(MergeStrategies, s)
+}
+
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeStrategyMerge:
+		return "merge commit"
+	case MergeStrategySquash:
+		return "squash"
+	case MergeStrategyRebase:
+		return "rebase"
+	case MergeStrategyFastForward:
+		return "fast-forward"
+	}
+	return string(s)
+}
@@ -18,6 +18,7 @@ type RepoIndexResponse struct {
 	Branches       []Branch        `json:"branches,omitempty"`
 	Tags           []*TagReference `json:"tags,omitempty"`
 	TotalCommits   int             `json:"total_commits,omitempty"`
+	Conflicts      []ConflictInfo  `json:"conflicts,omitempty"`
 }
 
 type RepoLogResponse struct {
@@ -34,7 +34,7 @@ type (
 
 	Constraint struct {
 		Event  StringList `yaml:"event"`
-		Branch StringList `yaml:"branch"` // required for pull_request; for push, either branch or tag must be specified
+		Branch StringList `yaml:"branch"` // required for pull_request and discussion; for push, either branch or tag must be specified
 		Tag    StringList `yaml:"tag"`    // optional; only applies to push events
 	}
 
@@ -54,6 +54,7 @@ const (
 
 	TriggerKindPush        TriggerKind = "push"
 	TriggerKindPullRequest TriggerKind = "pull_request"
+	TriggerKindDiscussion  TriggerKind = "discussion"
 	TriggerKindManual      TriggerKind = "manual"
 )
 
@@ -138,6 +139,16 @@ func (c *Constraint) Match(trigger tangled.Pipeline_TriggerMetadata) (bool, erro
 		match = match && matched
 	}
 
+	// apply branch constraints for discussions, pijul channels are matched
+	// like branches
+	if trigger.Discussion != nil {
+		matched, err := c.MatchBranch(trigger.Discussion.TargetChannel)
+		if err != nil {
+			return false, err
+		}
+		match = match && matched
+	}
+
 	// apply ref constraints for pushes
 	if trigger.Push != nil {
 		matched, err := c.MatchRef(trigger.Push.Ref)
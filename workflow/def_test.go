@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"tangled.org/core/api/tangled"
 )
 
 func TestUnmarshalWorkflowWithBranch(t *testing.T) {
@@ -321,3 +322,54 @@ func TestConstraintMatchTag_GlobPatterns(t *testing.T) {
 		})
 	}
 }
+
+func TestConstraintMatch_Discussion(t *testing.T) {
+	trigger := func(channel string) tangled.Pipeline_TriggerMetadata {
+		return tangled.Pipeline_TriggerMetadata{
+			Kind: string(TriggerKindDiscussion),
+			Discussion: &tangled.Pipeline_DiscussionTriggerData{
+				TargetChannel: channel,
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		constraint Constraint
+		channel    string
+		expected   bool
+	}{
+		{
+			name:       "matching channel",
+			constraint: Constraint{Event: []string{"discussion"}, Branch: []string{"main"}},
+			channel:    "main",
+			expected:   true,
+		},
+		{
+			name:       "glob channel",
+			constraint: Constraint{Event: []string{"discussion"}, Branch: []string{"release-*"}},
+			channel:    "release-1.0",
+			expected:   true,
+		},
+		{
+			name:       "other channel",
+			constraint: Constraint{Event: []string{"discussion"}, Branch: []string{"main"}},
+			channel:    "develop",
+			expected:   false,
+		},
+		{
+			name:       "other event",
+			constraint: Constraint{Event: []string{"pull_request"}, Branch: []string{"main"}},
+			channel:    "main",
+			expected:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.constraint.Match(trigger(tt.channel))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}